@@ -0,0 +1,145 @@
+package exiftool
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// archiveSeparator separates the path of an archive from the name of one of its entries
+// in FileMetadata.File (ie. "archive.zip!photo.jpg")
+const archiveSeparator = "!"
+
+const (
+	unknownArchive = iota
+	zipArchive
+	tarArchive
+	tarGzArchive
+)
+
+var zipMagic = []byte("PK\x03\x04")
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// archiveFormat detects the format of an archive from its first bytes
+func archiveFormat(header []byte) int {
+	switch {
+	case bytes.HasPrefix(header, zipMagic):
+		return zipArchive
+	case bytes.HasPrefix(header, gzipMagic):
+		return tarGzArchive
+	case len(header) >= 262 && string(header[257:262]) == "ustar":
+		return tarArchive
+	default:
+		return unknownArchive
+	}
+}
+
+// ExtractArchive extracts metadata from the regular files of the zip, tar or gzipped
+// tar archive path, without unpacking it: each entry is piped to exiftool (see
+// ExtractReader). The FileMetadata are returned in the order of the archive, their File
+// being the path of the archive and the name of the entry separated by "!" (ie.
+// "archive.zip!photo.jpg"). The format is detected from the content of the archive. If
+// the archive can't be read, a non empty error will be returned along with the entries
+// processed so far.
+// Sample :
+//   fms, err := e.ExtractArchive("upload.zip")
+func (e *Exiftool) ExtractArchive(path string) ([]FileMetadata, error) {
+	if err := e.checkFile(path); err != nil {
+		return nil, err
+	}
+
+	f, err := e.fileSystem().Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error while opening archive: %w", err)
+	}
+	defer f.Close()
+
+	header := make([]byte, 512)
+	n, err := io.ReadFull(f, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, fmt.Errorf("error while reading archive: %w", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("error while reading archive: %w", err)
+	}
+
+	switch archiveFormat(header[:n]) {
+	case zipArchive:
+		return e.extractZip(path, f)
+	case tarArchive:
+		return e.extractTar(path, f)
+	case tarGzArchive:
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("error while reading archive: %w", err)
+		}
+		defer gz.Close()
+		return e.extractTar(path, gz)
+	default:
+		return nil, fmt.Errorf("unsupported archive format (%v)", path)
+	}
+}
+
+func (e *Exiftool) extractZip(path string, f File) ([]FileMetadata, error) {
+	fi, err := e.fileSystem().Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading archive: %w", err)
+	}
+
+	// zip needs random access, the archive is loaded in memory if the file lacks it
+	ra, ok := f.(io.ReaderAt)
+	if !ok {
+		b, err := ioutil.ReadAll(f)
+		if err != nil {
+			return nil, fmt.Errorf("error while reading archive: %w", err)
+		}
+		ra = bytes.NewReader(b)
+	}
+
+	zr, err := zip.NewReader(ra, fi.Size())
+	if err != nil {
+		return nil, fmt.Errorf("error while reading archive: %w", err)
+	}
+
+	var fms []FileMetadata
+	for _, zf := range zr.File {
+		if !zf.FileInfo().Mode().IsRegular() {
+			continue
+		}
+
+		name := path + archiveSeparator + zf.Name
+		rc, err := zf.Open()
+		if err != nil {
+			fms = append(fms, FileMetadata{File: name, Err: fmt.Errorf("error while opening entry: %w", err)})
+			continue
+		}
+		fms = append(fms, e.ExtractReader(rc, name))
+		rc.Close()
+	}
+
+	return fms, nil
+}
+
+func (e *Exiftool) extractTar(path string, r io.Reader) ([]FileMetadata, error) {
+	var fms []FileMetadata
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return fms, nil
+		}
+		if err != nil {
+			return fms, fmt.Errorf("error while reading archive: %w", err)
+		}
+
+		if !hdr.FileInfo().Mode().IsRegular() {
+			continue
+		}
+		fms = append(fms, e.ExtractReader(tr, path+archiveSeparator+hdr.Name))
+	}
+}
@@ -0,0 +1,171 @@
+package exiftool
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// archiveEntries are the entries of the test archives, a nil content being a directory
+func archiveEntries(t *testing.T) []struct {
+	name    string
+	content []byte
+} {
+	img, err := ioutil.ReadFile("./testdata/20190404_131804.jpg")
+	assert.Nil(t, err)
+
+	return []struct {
+		name    string
+		content []byte
+	}{
+		{"photos/", nil},
+		{"photos/a.jpg", img},
+		{"notes.txt", []byte("notes")},
+	}
+}
+
+func createZip(t *testing.T, p string) {
+	f, err := os.Create(p)
+	assert.Nil(t, err)
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for _, en := range archiveEntries(t) {
+		w, err := zw.Create(en.name)
+		assert.Nil(t, err)
+		_, err = w.Write(en.content)
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, zw.Close())
+}
+
+func createTar(t *testing.T, p string, compress bool) {
+	f, err := os.Create(p)
+	assert.Nil(t, err)
+	defer f.Close()
+
+	var w io.Writer = f
+	if compress {
+		gz := gzip.NewWriter(f)
+		defer gz.Close()
+		w = gz
+	}
+
+	tw := tar.NewWriter(w)
+	for _, en := range archiveEntries(t) {
+		hdr := tar.Header{Name: en.name, Mode: 0644, Size: int64(len(en.content)), Typeflag: tar.TypeReg}
+		if en.content == nil {
+			hdr.Typeflag, hdr.Mode = tar.TypeDir, 0755
+		}
+		assert.Nil(t, tw.WriteHeader(&hdr))
+		_, err := tw.Write(en.content)
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, tw.Close())
+}
+
+func TestArchiveFormat(t *testing.T) {
+	ustar := make([]byte, 512)
+	copy(ustar[257:], "ustar")
+
+	var tcs = []struct {
+		tcID  string
+		in    []byte
+		expFt int
+	}{
+		{"zip", []byte("PK\x03\x04rest"), zipArchive},
+		{"gzip", []byte{0x1f, 0x8b, 0x08}, tarGzArchive},
+		{"tar", ustar, tarArchive},
+		{"short", []byte("PK"), unknownArchive},
+		{"empty", nil, unknownArchive},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			assert.Equal(t, tc.expFt, archiveFormat(tc.in))
+		})
+	}
+}
+
+func TestExtractArchiveErrors(t *testing.T) {
+	e, err := NewExiftool()
+	require.Nil(t, err)
+	defer e.Close()
+
+	_, err = e.ExtractArchive("./testdata/nonExisting")
+	assert.True(t, errors.Is(err, ErrNotExist))
+
+	_, err = e.ExtractArchive("./testdata/empty.jpg")
+	assert.NotNil(t, err)
+}
+
+func TestExtractArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-exiftool")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	var tcs = []struct {
+		tcID   string
+		name   string
+		create func(t *testing.T, p string)
+	}{
+		{"zip", "archive.zip", createZip},
+		{"tar", "archive.tar", func(t *testing.T, p string) { createTar(t, p, false) }},
+		{"tarGz", "archive.tar.gz", func(t *testing.T, p string) { createTar(t, p, true) }},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			p := filepath.Join(dir, tc.name)
+			tc.create(t, p)
+
+			fms, err := e.ExtractArchive(p)
+			assert.Nil(t, err)
+			assert.Equal(t, 2, len(fms))
+			assert.Equal(t, p+"!photos/a.jpg", fms[0].File)
+			assert.Nil(t, fms[0].Err)
+			mimeType, err := fms[0].Groups["File"].GetString("MIMEType")
+			assert.Nil(t, err)
+			assert.Equal(t, "image/jpeg", mimeType)
+			assert.Equal(t, p+"!notes.txt", fms[1].File)
+		})
+	}
+}
+
+func TestExtractArchiveFS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-exiftool")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	createZip(t, filepath.Join(dir, "a.zip"))
+	b, err := ioutil.ReadFile(filepath.Join(dir, "a.zip"))
+	assert.Nil(t, err)
+
+	m := newMemFileSystem()
+	m.files["upload.zip"] = b
+	e, err := NewExiftool(SetFileSystem(m))
+	require.Nil(t, err)
+	defer e.Close()
+
+	fms, err := e.ExtractArchive("upload.zip")
+	assert.Nil(t, err)
+	var names []string
+	for _, fm := range fms {
+		names = append(names, fm.File)
+	}
+	assert.Equal(t, []string{"upload.zip!photos/a.jpg", "upload.zip!notes.txt"}, names)
+}
@@ -0,0 +1,75 @@
+package exiftool
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// defaultArgFileThreshold is the number of arguments of a command above which they are
+// passed to exiftool through an argfile
+const defaultArgFileThreshold = 1000
+
+// ArgFileThreshold defines the number of arguments (1000 by default) of a command above
+// which they are written to a temporary argfile read by exiftool (-@ ARGFILE) instead of
+// being sent one by one, which is mostly useful when writing huge batches of files. The
+// dedicated exiftool processes (ie. ExtractCSV, ExtractStream) always read their file
+// list from stdin (-@ -).
+// Sample :
+//   e, err := NewExiftool(ArgFileThreshold(100))
+func ArgFileThreshold(n int) Option {
+	return func(e *Exiftool) error {
+		if n < 1 {
+			return fmt.Errorf("invalid argfile threshold (%v)", n)
+		}
+		e.argFileAt = n
+		return nil
+	}
+}
+
+// AlwaysUseArgFiles makes every command be passed to exiftool through a temporary
+// argfile, see ArgFileThreshold
+// Sample :
+//   e, err := NewExiftool(AlwaysUseArgFiles())
+func AlwaysUseArgFiles() Option {
+	return func(e *Exiftool) error {
+		e.argFiles = true
+		return nil
+	}
+}
+
+// useArgFile returns true if args must be passed to exiftool through an argfile
+func (e *Exiftool) useArgFile(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	if e.argFiles {
+		return true
+	}
+
+	threshold := e.argFileAt
+	if threshold == 0 {
+		threshold = defaultArgFileThreshold
+	}
+	return len(args) > threshold
+}
+
+// writeArgFile writes args, one per line, to a new temporary argfile of fsys and returns
+// its path
+func writeArgFile(fsys FileSystem, args []string) (string, error) {
+	f, err := fsys.TempFile("", "go-exiftool-*.args")
+	if err != nil {
+		return "", fmt.Errorf("error when creating argfile: %w", err)
+	}
+
+	_, err = io.WriteString(f, strings.Join(args, "\n")+"\n")
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		fsys.Remove(f.Name())
+		return "", fmt.Errorf("error when writing argfile: %w", err)
+	}
+
+	return f.Name(), nil
+}
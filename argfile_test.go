@@ -0,0 +1,87 @@
+package exiftool
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArgFileThreshold(t *testing.T) {
+	var tcs = []struct {
+		tcID  string
+		in    int
+		expOk bool
+	}{
+		{"one", 1, true},
+		{"many", 500, true},
+		{"zero", 0, false},
+		{"negative", -1, false},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			e := Exiftool{}
+			err := ArgFileThreshold(tc.in)(&e)
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.in, e.argFileAt)
+			}
+		})
+	}
+}
+
+func TestUseArgFile(t *testing.T) {
+	many := make([]string, defaultArgFileThreshold+1)
+
+	var tcs = []struct {
+		tcID   string
+		opts   []Option
+		in     []string
+		expUse bool
+	}{
+		{"default", nil, []string{"-ver"}, false},
+		{"defaultThreshold", nil, many[:defaultArgFileThreshold], false},
+		{"defaultExceeded", nil, many, true},
+		{"threshold", []Option{ArgFileThreshold(2)}, []string{"-a", "-b"}, false},
+		{"thresholdExceeded", []Option{ArgFileThreshold(2)}, []string{"-a", "-b", "-c"}, true},
+		{"always", []Option{AlwaysUseArgFiles()}, []string{"-ver"}, true},
+		{"alwaysEmpty", []Option{AlwaysUseArgFiles()}, nil, false},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			e := Exiftool{}
+			for _, opt := range tc.opts {
+				assert.Nil(t, opt(&e))
+			}
+			assert.Equal(t, tc.expUse, e.useArgFile(tc.in))
+		})
+	}
+}
+
+func TestWriteArgFile(t *testing.T) {
+	f, err := writeArgFile(OSFileSystem{}, []string{"-Artist=me", "a b.jpg"})
+	assert.Nil(t, err)
+	defer os.Remove(f)
+
+	b, err := ioutil.ReadFile(f)
+	assert.Nil(t, err)
+	assert.Equal(t, "-Artist=me\na b.jpg\n", string(b))
+}
+
+func TestNewExifTool_AlwaysUseArgFiles(t *testing.T) {
+	e, err := NewExiftool(AlwaysUseArgFiles())
+	assert.Nil(t, err)
+	defer e.Close()
+
+	fms := e.ExtractMetadata("./testdata/20190404_131804.jpg")
+	assert.Equal(t, 1, len(fms))
+	assert.Nil(t, fms[0].Err)
+	mk, err := fms[0].Groups["EXIF"].GetString("Make")
+	assert.Nil(t, err)
+	assert.Equal(t, "samsung", mk)
+}
@@ -0,0 +1,175 @@
+package exiftool
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AudioInfo gathers the main characteristics of an audio file. Fields are zero when the
+// corresponding tag can't be found. SampleRate is in Hz, gains are in dB.
+type AudioInfo struct {
+	SampleRate int
+	Channels   int
+	BitDepth   int
+	Duration   time.Duration
+	Title      string
+	Artist     string
+	Album      string
+	ReplayGain ReplayGain
+}
+
+// ReplayGain holds the ReplayGain loudness normalization values of an audio file
+type ReplayGain struct {
+	TrackGain float64
+	TrackPeak float64
+	AlbumGain float64
+	AlbumPeak float64
+}
+
+// Keys of the audio tags, in MPEG (MP3), FLAC, Vorbis (OGG), APE, RIFF (WAV) and
+// QuickTime (M4A) files
+var (
+	audioSampleRateKeys = []string{"MPEG:SampleRate", "FLAC:SampleRate", "Vorbis:SampleRate", "RIFF:SampleRate", "QuickTime:AudioSampleRate", "SampleRate", "AudioSampleRate"}
+	audioChannelsKeys   = []string{"FLAC:Channels", "Vorbis:AudioChannels", "RIFF:NumChannels", "QuickTime:AudioChannels", "MPEG:ChannelMode", "Channels", "AudioChannels"}
+	audioBitDepthKeys   = []string{"FLAC:BitsPerSample", "RIFF:BitsPerSample", "QuickTime:AudioBitsPerSample", "BitsPerSample", "AudioBitsPerSample"}
+	audioDurationKeys   = []string{"Composite:Duration", "QuickTime:Duration", "RIFF:Duration", "Duration"}
+	audioTitleKeys      = []string{"ID3:Title", "Vorbis:Title", "APE:Title", "QuickTime:Title", "RIFF:Title", "Title"}
+	audioArtistKeys     = []string{"ID3:Artist", "Vorbis:Artist", "APE:Artist", "QuickTime:Artist", "RIFF:Artist", "Artist"}
+	audioAlbumKeys      = []string{"ID3:Album", "Vorbis:Album", "APE:Album", "QuickTime:Album", "Album"}
+)
+
+// AudioInfo returns the main characteristics of the audio file, whatever its format.
+// ReplayGain values are read from the Vorbis comments and APE tags, or from the
+// user-defined ID3 text frames (TXXX) of MP3 files. ErrKeyNotFound will be returned if
+// none of them can be found.
+func (fm FileMetadata) AudioInfo() (AudioInfo, error) {
+	var ai AudioInfo
+	found := false
+
+	for _, i := range []struct {
+		keys []string
+		dst  *int
+		name string
+	}{
+		{audioSampleRateKeys, &ai.SampleRate, "SampleRate"},
+		{audioBitDepthKeys, &ai.BitDepth, "BitsPerSample"},
+	} {
+		if v, err := fm.lookupInt(i.keys...); err == nil {
+			*i.dst, found = int(v), true
+		} else if err != ErrKeyNotFound {
+			return AudioInfo{}, fmt.Errorf("%v parsing error: %w", i.name, err)
+		}
+	}
+
+	if s, ok := fm.lookupString(audioChannelsKeys...); ok {
+		c, err := parseChannels(s)
+		if err != nil {
+			return AudioInfo{}, fmt.Errorf("Channels parsing error: %w", err)
+		}
+		ai.Channels, found = c, true
+	}
+
+	if s, ok := fm.lookupString(audioDurationKeys...); ok {
+		d, err := parseDuration(s)
+		if err != nil {
+			return AudioInfo{}, fmt.Errorf("Duration parsing error: %w", err)
+		}
+		ai.Duration, found = d, true
+	}
+
+	for _, i := range []struct {
+		keys []string
+		dst  *string
+	}{
+		{audioTitleKeys, &ai.Title},
+		{audioArtistKeys, &ai.Artist},
+		{audioAlbumKeys, &ai.Album},
+	} {
+		if s, ok := fm.lookupString(i.keys...); ok {
+			*i.dst, found = s, true
+		}
+	}
+
+	rg, ok, err := fm.replayGain()
+	if err != nil {
+		return AudioInfo{}, err
+	}
+	ai.ReplayGain, found = rg, found || ok
+
+	if !found {
+		return AudioInfo{}, ErrKeyNotFound
+	}
+
+	return ai, nil
+}
+
+// replayGain returns the ReplayGain values of the file and whether at least one of them
+// has been found
+func (fm FileMetadata) replayGain() (ReplayGain, bool, error) {
+	var rg ReplayGain
+	found := false
+
+	txxx, _ := fm.lookupStrings("ID3:UserDefinedText")
+
+	for _, i := range []struct {
+		tag string
+		dst *float64
+	}{
+		{"ReplayGainTrackGain", &rg.TrackGain},
+		{"ReplayGainTrackPeak", &rg.TrackPeak},
+		{"ReplayGainAlbumGain", &rg.AlbumGain},
+		{"ReplayGainAlbumPeak", &rg.AlbumPeak},
+	} {
+		s, ok := fm.lookupString(i.tag)
+		if !ok {
+			s, ok = userDefinedText(txxx, i.tag)
+		}
+		if !ok {
+			continue
+		}
+
+		f, err := toFloatFallback(strings.TrimSpace(s))
+		if err != nil {
+			return ReplayGain{}, false, fmt.Errorf("%v parsing error: %w", i.tag, err)
+		}
+		*i.dst, found = f, true
+	}
+
+	return rg, found, nil
+}
+
+// userDefinedText returns the value of the user-defined ID3 text frame whose description
+// matches tag (ie. "(REPLAYGAIN_TRACK_GAIN) -6.48 dB" for "ReplayGainTrackGain")
+func userDefinedText(txxx []string, tag string) (string, bool) {
+	for _, t := range txxx {
+		if !strings.HasPrefix(t, "(") {
+			continue
+		}
+		idx := strings.Index(t, ")")
+		if idx == -1 {
+			continue
+		}
+		if strings.EqualFold(strings.Replace(t[1:idx], "_", "", -1), tag) {
+			return strings.TrimSpace(t[idx+1:]), true
+		}
+	}
+	return "", false
+}
+
+// parseChannels parses a number of channels, which can also be an MPEG channel mode
+// ("Mono", "Joint Stereo", ...)
+func parseChannels(s string) (int, error) {
+	if c, err := toIntFallback(s); err == nil {
+		return int(c), nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "mono", "single channel":
+		return 1, nil
+	case "stereo", "joint stereo", "dual channel":
+		return 2, nil
+	}
+
+	return 0, fmt.Errorf("invalid number of channels (%v)", s)
+}
@@ -0,0 +1,108 @@
+package exiftool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseChannels(t *testing.T) {
+	var tcs = []struct {
+		tcID  string
+		in    string
+		expOk bool
+		expC  int
+	}{
+		{"numeric", "6", true, 6},
+		{"mono", "Mono", true, 1},
+		{"jointStereo", "Joint Stereo", true, 2},
+		{"dual", "dual channel", true, 2},
+		{"unknown", "Surround", false, 0},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			c, err := parseChannels(tc.in)
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.expC, c)
+			}
+		})
+	}
+}
+
+func TestUserDefinedText(t *testing.T) {
+	txxx := []string{"(MusicBrainz Album Id) 1234", "no description", "(REPLAYGAIN_TRACK_GAIN) -6.48 dB", "(unclosed"}
+
+	s, found := userDefinedText(txxx, "ReplayGainTrackGain")
+	assert.True(t, found)
+	assert.Equal(t, "-6.48 dB", s)
+
+	_, found = userDefinedText(txxx, "ReplayGainAlbumGain")
+	assert.False(t, found)
+}
+
+func TestAudioInfo(t *testing.T) {
+	var tcs = []struct {
+		tcID  string
+		in    map[string]FileMetadataValues
+		expOk bool
+		expAi AudioInfo
+	}{
+		{"mp3", map[string]FileMetadataValues{
+			"MPEG": {{"SampleRate", float64(44100)}, {"ChannelMode", "Joint Stereo"}},
+			"ID3": {
+				{"Title", "Song"},
+				{"Artist", "Band"},
+				{"Album", "Record"},
+				{"UserDefinedText", []interface{}{"(REPLAYGAIN_TRACK_GAIN) -6.48 dB", "(REPLAYGAIN_TRACK_PEAK) 0.988"}},
+			},
+			"Composite": {{"Duration", "0:03:45 (approx)"}},
+		}, true, AudioInfo{
+			SampleRate: 44100,
+			Channels:   2,
+			Duration:   225 * time.Second,
+			Title:      "Song",
+			Artist:     "Band",
+			Album:      "Record",
+			ReplayGain: ReplayGain{TrackGain: -6.48, TrackPeak: 0.988},
+		}},
+		{"flac", map[string]FileMetadataValues{
+			"FLAC":   {{"SampleRate", float64(96000)}, {"Channels", float64(2)}, {"BitsPerSample", float64(24)}},
+			"Vorbis": {{"Title", "Song"}, {"ReplayGainAlbumGain", "-7.1 dB"}, {"ReplayGainAlbumPeak", "1.0"}},
+		}, true, AudioInfo{
+			SampleRate: 96000,
+			Channels:   2,
+			BitDepth:   24,
+			Title:      "Song",
+			ReplayGain: ReplayGain{AlbumGain: -7.1, AlbumPeak: 1},
+		}},
+		{"wav", map[string]FileMetadataValues{
+			"RIFF":      {{"SampleRate", float64(48000)}, {"NumChannels", float64(1)}, {"BitsPerSample", float64(16)}},
+			"Composite": {{"Duration", float64(1.5)}},
+		}, true, AudioInfo{SampleRate: 48000, Channels: 1, BitDepth: 16, Duration: 1500 * time.Millisecond}},
+		{"replayGainOnly", map[string]FileMetadataValues{"APE": {{"ReplayGainTrackGain", "+1.2 dB"}}}, true, AudioInfo{
+			ReplayGain: ReplayGain{TrackGain: 1.2},
+		}},
+		{"invalidSampleRate", map[string]FileMetadataValues{"MPEG": {{"SampleRate", "high"}}}, false, AudioInfo{}},
+		{"invalidChannels", map[string]FileMetadataValues{"MPEG": {{"ChannelMode", "Surround"}}}, false, AudioInfo{}},
+		{"invalidGain", map[string]FileMetadataValues{"Vorbis": {{"ReplayGainTrackGain", "loud"}}}, false, AudioInfo{}},
+		{"image", map[string]FileMetadataValues{"EXIF": {{"Make", "samsung"}}}, false, AudioInfo{}},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			ai, err := FileMetadata{Groups: tc.in}.AudioInfo()
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.expAi, ai)
+			}
+		})
+	}
+
+	_, err := FileMetadata{}.AudioInfo()
+	assert.Equal(t, ErrKeyNotFound, err)
+}
@@ -0,0 +1,173 @@
+package exiftool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// WithBatchSize makes the extraction send the files by batches of size files per
+// exiftool command instead of one command per file, which amortizes the cost of each
+// command on large sets of small files. The files of a batch that can't be extracted
+// with it (ie. the command failed or timed out, or a file is missing from its output)
+// are extracted again one by one, so that one bad file doesn't fail the whole batch.
+// The timeout of a batch is the per-file timeout multiplied by its number of files.
+// Sample :
+//   fms := e.Extract(files, WithBatchSize(50))
+func WithBatchSize(size int) ExtractOption {
+	return func(c *extractConfig) error {
+		if size < 1 {
+			return fmt.Errorf("invalid batch size (%v)", size)
+		}
+		c.batch = size
+		return nil
+	}
+}
+
+// DirBatchSize makes the directory extraction send the files by batches of size files,
+// see WithBatchSize. The results of a batch are sent once it has been extracted.
+// Sample :
+//   c, err := e.ExtractDir("photos", DirBatchSize(50))
+func DirBatchSize(size int) DirOption {
+	return func(c *dirConfig) error {
+		if size < 1 {
+			return fmt.Errorf("invalid batch size (%v)", size)
+		}
+		c.batch = size
+		return nil
+	}
+}
+
+// extractChunk extracts files configured by cfg with a single command, falling back to
+// one command per file if needed. The caller must hold e.lock.
+func (e *Exiftool) extractChunk(ctx context.Context, cfg extractConfig, files []string) []FileMetadata {
+	fms := make([]FileMetadata, len(files))
+	if len(files) == 1 {
+		fms[0] = e.extractFileRetry(ctx, cfg, files[0])
+		return fms
+	}
+
+	args := e.extractArgs(cfg)
+	keys := make([]CacheKey, len(files))
+	var batch []int
+	for i, f := range files {
+		fms[i].File = f
+		if err := ctx.Err(); err != nil {
+			fms[i].Err = err
+			continue
+		}
+		if err := e.checkFile(f); err != nil {
+			fms[i].Err = err
+			continue
+		}
+		if e.cache != nil {
+			if k, err := cacheKey(e.fileSystem(), f, append(append([]string{}, e.extraInitArgs...), args...)); err == nil {
+				if cached, found := e.cache.Get(k); found && !cfg.refresh {
+					fms[i] = cached
+					continue
+				}
+				keys[i] = k
+			}
+		}
+		batch = append(batch, i)
+	}
+
+	for _, i := range e.extractBatch(ctx, cfg, args, files, batch, fms) {
+		fms[i] = e.extractFileRetry(ctx, cfg, files[i])
+	}
+
+	if e.cache != nil {
+		for _, i := range batch {
+			if keys[i].File != "" && fms[i].Err == nil {
+				e.cache.Set(keys[i], fms[i])
+			}
+		}
+	}
+
+	return fms
+}
+
+// extractBatch extracts the files #batch of files with a single command built from
+// args, fills fms with their metadata and returns the indexes of the files that must be
+// extracted one by one
+func (e *Exiftool) extractBatch(ctx context.Context, cfg extractConfig, args, files []string, batch []int, fms []FileMetadata) []int {
+	if len(batch) < 2 {
+		return batch
+	}
+
+	for _, i := range batch {
+		args = append(args, files[i])
+	}
+	timeout := cfg.timeout * time.Duration(len(batch))
+	out, err := e.executeFile(ctx, timeout, files[batch[0]], args...)
+	if err != nil {
+		e.log("batch fallback", "files", len(batch), "err", err)
+		return batch
+	}
+	if len(cfg.conditions) > 0 && failedCondition(out) {
+		for _, i := range batch {
+			fms[i] = FileMetadata{File: files[i], Err: ErrConditionNotMet}
+		}
+		return nil
+	}
+
+	out, messages := splitMessages(out)
+	var raws []json.RawMessage
+	if err := json.Unmarshal(out, &raws); err != nil {
+		e.log("batch fallback", "files", len(batch), "err", err)
+		return batch
+	}
+
+	bySource := map[string]json.RawMessage{}
+	for _, raw := range raws {
+		var sf struct {
+			SourceFile string
+		}
+		if json.Unmarshal(raw, &sf) == nil {
+			bySource[sf.SourceFile] = raw
+		}
+	}
+
+	// files missing from the output didn't match the conditions if exiftool reported it
+	notMet := false
+	for _, m := range messages {
+		if len(cfg.conditions) > 0 && failedCondition([]byte(m)) {
+			notMet = true
+		}
+	}
+
+	var fallback []int
+	for _, i := range batch {
+		f := files[i]
+		raw, found := bySource[f]
+		if !found {
+			raw, found = bySource[filepath.ToSlash(f)]
+		}
+		if !found && notMet {
+			fms[i] = FileMetadata{File: f, Err: ErrConditionNotMet}
+			continue
+		}
+		if !found {
+			fallback = append(fallback, i)
+			continue
+		}
+		fms[i] = FileMetadata{File: f}
+		e.decodeRaw(&fms[i], raw, fileMessages(messages, f))
+	}
+
+	return fallback
+}
+
+// fileMessages returns the messages printed by exiftool about file f during a batch
+func fileMessages(messages []string, f string) []string {
+	var ms []string
+	for _, m := range messages {
+		if strings.HasSuffix(m, " - "+f) || strings.HasSuffix(m, " - "+filepath.ToSlash(f)) {
+			ms = append(ms, m)
+		}
+	}
+	return ms
+}
@@ -0,0 +1,132 @@
+package exiftool
+
+import (
+	"errors"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithBatchSize(t *testing.T) {
+	var tcs = []struct {
+		tcID  string
+		in    int
+		expOk bool
+	}{
+		{"one", 1, true},
+		{"many", 50, true},
+		{"zero", 0, false},
+		{"negative", -1, false},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			e := Exiftool{}
+			cfg, err := e.newExtractConfig([]ExtractOption{WithBatchSize(tc.in)})
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.in, cfg.batch)
+			}
+
+			c, err := newDirConfig([]DirOption{DirBatchSize(tc.in)})
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.in, c.batch)
+			}
+		})
+	}
+
+	assert.Equal(t, 1, (&Exiftool{}).extractConfig().batch)
+}
+
+func TestFileMessages(t *testing.T) {
+	messages := []string{
+		"Warning: Bad IFD0 directory - a.jpg",
+		"Warning: Bad MakerNotes - b.jpg",
+		"Error: File not found - dir/a.jpg",
+		"2 image files read",
+	}
+	assert.Equal(t, []string{"Warning: Bad IFD0 directory - a.jpg"}, fileMessages(messages, "a.jpg"))
+	assert.Equal(t, []string{"Error: File not found - dir/a.jpg"}, fileMessages(messages, filepath.Join("dir", "a.jpg")))
+	assert.Nil(t, fileMessages(messages, "c.jpg"))
+}
+
+func TestExtractBatch(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	files := []string{
+		"./testdata/20190404_131804.jpg",
+		"./testdata/nonExisting",
+		"./testdata/empty.jpg",
+		"./testdata/extractEmbedded.mp4",
+		"./testdata/20190404_131804.jpg",
+	}
+	for _, size := range []int{1, 2, 3, 10} {
+		fms := e.Extract(files, WithBatchSize(size))
+		assert.Equal(t, len(files), len(fms))
+		for i, fm := range fms {
+			assert.Equal(t, files[i], fm.File)
+			assert.Equal(t, i, fm.Index)
+			assert.Equal(t, i == 1, fm.Err != nil)
+			assert.Equal(t, i != 1, fm.Groups != nil)
+		}
+		assert.True(t, errors.Is(fms[1].Err, ErrNotExist))
+	}
+}
+
+func TestExtractBatchFallback(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	files := []string{"./testdata/20190404_131804.jpg", "./testdata/empty.jpg"}
+	fms := e.Extract(files, WithBatchSize(2), Where("$Make eq 'samsung'"))
+	assert.Equal(t, 2, len(fms))
+	assert.Nil(t, fms[0].Err)
+	assert.Equal(t, ErrConditionNotMet, fms[1].Err)
+
+	fms = e.Extract(files, WithBatchSize(2), Where("$Make eq 'nobody'"))
+	assert.Equal(t, 2, len(fms))
+	for i, fm := range fms {
+		assert.Equal(t, files[i], fm.File)
+		assert.Equal(t, ErrConditionNotMet, fm.Err)
+	}
+}
+
+func TestPoolExtractBatch(t *testing.T) {
+	p, err := NewPool(2)
+	assert.Nil(t, err)
+	defer p.Close()
+
+	files := []string{
+		"./testdata/20190404_131804.jpg",
+		"./testdata/empty.jpg",
+		"./testdata/nonExisting",
+		"./testdata/extractEmbedded.mp4",
+		"./testdata/empty.jpg",
+	}
+	fms := p.Extract(files, WithBatchSize(2))
+	assert.Equal(t, len(files), len(fms))
+	for i, fm := range fms {
+		assert.Equal(t, files[i], fm.File)
+		assert.Equal(t, i, fm.Index)
+		assert.Equal(t, i == 2, fm.Err != nil)
+	}
+
+	c, err := p.ExtractDir("./testdata", DirBatchSize(2))
+	assert.Nil(t, err)
+	var dirFiles []string
+	for fm := range c {
+		assert.Nil(t, fm.Err)
+		dirFiles = append(dirFiles, filepath.Base(fm.File))
+	}
+	sort.Strings(dirFiles)
+	assert.Contains(t, dirFiles, "20190404_131804.jpg")
+	assert.Contains(t, dirFiles, "empty.jpg")
+	assert.Contains(t, dirFiles, "extractEmbedded.mp4")
+}
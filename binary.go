@@ -0,0 +1,58 @@
+package exiftool
+
+import (
+	"errors"
+	"regexp"
+)
+
+// ErrBinaryNotExtracted is returned by GetBytes when the binary value was not extracted,
+// see BinaryValues
+var ErrBinaryNotExtracted = errors.New("binary value not extracted (see BinaryValues)")
+
+// binaryPlaceholderRegexp matches the placeholder printed by exiftool instead of the
+// binary values when -b isn't used
+var binaryPlaceholderRegexp = regexp.MustCompile(`^\(Binary data \d+ bytes`)
+
+// BinaryValues extracts the binary values (ie. ThumbnailImage, ICC_Profile) inline,
+// base64 encoded and prefixed by "base64:" (activates Exiftool's '-b' parameter along
+// with '-j'), instead of a placeholder. They are decoded by GetBytes. As the values are
+// read like any other exiftool output, the Buffer option may have to be used.
+// Sample :
+//   e, err := NewExiftool(BinaryValues())
+//   thumb, err := e.ExtractMetadata("a.jpg")[0].Groups["EXIF"].GetBytes("ThumbnailImage")
+func BinaryValues() Option {
+	return func(e *Exiftool) error {
+		e.extraInitArgs = append(e.extraInitArgs, "-b")
+		return nil
+	}
+}
+
+// ExtractBinary extracts the binary value of tag (ie. ThumbnailImage, PreviewImage,
+// JpgFromRaw, ICC_Profile) from file (activates Exiftool's '-b' parameter). Tag can be
+// prefixed by a group. ErrKeyNotFound will be returned if file has no such tag. As the
+// value is read like any other exiftool output, the Buffer option may have to be used
+// for large values.
+// Sample :
+//   thumb, err := e.ExtractBinary("photo.jpg", "ThumbnailImage")
+func (e *Exiftool) ExtractBinary(file, tag string) ([]byte, error) {
+	if err := e.checkFile(file); err != nil {
+		return nil, err
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	out, err := e.execute("-b", "-"+tag, file)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(out) == 0 {
+		return nil, ErrKeyNotFound
+	}
+
+	b := make([]byte, len(out))
+	copy(b, out)
+
+	return b, nil
+}
@@ -0,0 +1,49 @@
+package exiftool
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractBinary(t *testing.T) {
+	e, err := NewExiftool(Buffer(make([]byte, 128*1000), 1024*1000))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	thumb, err := e.ExtractBinary("./testdata/20190404_131804.jpg", "ThumbnailImage")
+	assert.Nil(t, err)
+	assert.True(t, bytes.HasPrefix(thumb, []byte{0xff, 0xd8}), "not a jpeg")
+
+	_, err = e.ExtractBinary("./testdata/empty.jpg", "ThumbnailImage")
+	assert.Equal(t, ErrKeyNotFound, err)
+
+	_, err = e.ExtractBinary("./testdata/nonExisting", "ThumbnailImage")
+	assert.True(t, errors.Is(err, ErrNotExist))
+
+	fms := e.ExtractMetadata("./testdata/20190404_131804.jpg")
+	assert.Equal(t, 1, len(fms))
+	assert.Nil(t, fms[0].Err)
+}
+
+func TestBinaryValues(t *testing.T) {
+	e, err := NewExiftool(BinaryValues(), Buffer(make([]byte, 128*1000), 1024*1000))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	fms := e.ExtractMetadata("./testdata/20190404_131804.jpg")
+	assert.Nil(t, fms[0].Err)
+	g, _, found := fms[0].lookup("ThumbnailImage")
+	assert.True(t, found)
+	thumb, err := g.GetBytes("ThumbnailImage")
+	assert.Nil(t, err)
+	assert.True(t, bytes.HasPrefix(thumb, []byte{0xff, 0xd8}), "not a jpeg")
+}
+
+func TestBinaryValuesOption(t *testing.T) {
+	e := Exiftool{}
+	assert.Nil(t, BinaryValues()(&e))
+	assert.Equal(t, []string{"-b"}, e.extraInitArgs)
+}
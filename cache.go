@@ -0,0 +1,141 @@
+package exiftool
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheKey identifies an extraction: a file, in a given state, extracted with given
+// arguments
+type CacheKey struct {
+	File    string
+	Size    int64
+	ModTime time.Time
+	Args    string
+}
+
+// Cache stores the results of extractions, so that exiftool isn't invoked again for files
+// that didn't change (same path, size and modification time). Only successful
+// extractions are stored. Implementations must be safe for concurrent use. Cached
+// FileMetadata are shared, hence must not be modified.
+type Cache interface {
+	Get(k CacheKey) (FileMetadata, bool)
+	Set(k CacheKey, fm FileMetadata)
+}
+
+// CacheInvalidator is implemented by the Caches able to drop the extractions of a file,
+// done by Exiftool once it renamed or rebuilt files, whose cache keys may not change
+// (same size, modification time preserved).
+type CacheInvalidator interface {
+	Invalidate(file string)
+}
+
+// SetCache defines the Cache used by the extractions of the Exiftool, see NewLRUCache.
+// It can be shared between the workers of a Pool, or more generally between Exiftools
+// decoding the output of exiftool the same way (ie. same CustomDecoder).
+// Sample :
+//   e, err := NewExiftool(SetCache(NewLRUCache(10000)))
+func SetCache(c Cache) Option {
+	return func(e *Exiftool) error {
+		e.cache = c
+		return nil
+	}
+}
+
+// cacheKey returns the CacheKey of the extraction of f, a file of fsys, with args
+func cacheKey(fsys FileSystem, f string, args []string) (CacheKey, error) {
+	fi, err := fsys.Stat(f)
+	if err != nil {
+		return CacheKey{}, err
+	}
+
+	return CacheKey{File: f, Size: fi.Size(), ModTime: fi.ModTime(), Args: strings.Join(args, "\n")}, nil
+}
+
+// invalidateCache drops the cached extractions of files, if the Cache supports it
+func (e *Exiftool) invalidateCache(files ...string) {
+	c, ok := e.cache.(CacheInvalidator)
+	if !ok {
+		return
+	}
+	for _, f := range files {
+		c.Invalidate(f)
+	}
+}
+
+// LRUCache is an in-memory Cache keeping the most recently used extractions
+type LRUCache struct {
+	lock    sync.Mutex
+	size    int
+	entries *list.List
+	index   map[CacheKey]*list.Element
+}
+
+type lruEntry struct {
+	key CacheKey
+	fm  FileMetadata
+}
+
+// NewLRUCache instanciates a new LRUCache keeping at most size extractions (at least 1)
+func NewLRUCache(size int) *LRUCache {
+	if size < 1 {
+		size = 1
+	}
+	return &LRUCache{size: size, entries: list.New(), index: map[CacheKey]*list.Element{}}
+}
+
+// Get returns the FileMetadata stored for k, if any
+func (c *LRUCache) Get(k CacheKey) (FileMetadata, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	el, found := c.index[k]
+	if !found {
+		return FileMetadata{}, false
+	}
+	c.entries.MoveToFront(el)
+
+	return el.Value.(*lruEntry).fm, true
+}
+
+// Set stores fm for k, evicting the least recently used extraction if the cache is full
+func (c *LRUCache) Set(k CacheKey, fm FileMetadata) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if el, found := c.index[k]; found {
+		el.Value.(*lruEntry).fm = fm
+		c.entries.MoveToFront(el)
+		return
+	}
+
+	c.index[k] = c.entries.PushFront(&lruEntry{key: k, fm: fm})
+	if c.entries.Len() > c.size {
+		last := c.entries.Back()
+		c.entries.Remove(last)
+		delete(c.index, last.Value.(*lruEntry).key)
+	}
+}
+
+// Invalidate drops the extractions stored for file
+func (c *LRUCache) Invalidate(file string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for k, el := range c.index {
+		if k.File == file {
+			c.entries.Remove(el)
+			delete(c.index, k)
+		}
+	}
+}
+
+// Len returns the number of stored extractions
+func (c *LRUCache) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.entries.Len()
+}
@@ -0,0 +1,127 @@
+package exiftool
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCache(t *testing.T) {
+	c := NewLRUCache(2)
+	k1, k2, k3 := CacheKey{File: "a"}, CacheKey{File: "b"}, CacheKey{File: "c"}
+
+	_, found := c.Get(k1)
+	assert.False(t, found)
+
+	c.Set(k1, FileMetadata{File: "a"})
+	c.Set(k2, FileMetadata{File: "b"})
+	fm, found := c.Get(k1)
+	assert.True(t, found)
+	assert.Equal(t, "a", fm.File)
+
+	c.Set(k3, FileMetadata{File: "c"})
+	assert.Equal(t, 2, c.Len())
+	_, found = c.Get(k2)
+	assert.False(t, found)
+	_, found = c.Get(k1)
+	assert.True(t, found)
+
+	c.Set(k3, FileMetadata{File: "c2"})
+	fm, _ = c.Get(k3)
+	assert.Equal(t, "c2", fm.File)
+	assert.Equal(t, 2, c.Len())
+
+	assert.Equal(t, 1, NewLRUCache(0).size)
+}
+
+func TestInvalidateCache(t *testing.T) {
+	c := NewLRUCache(10)
+	c.Set(CacheKey{File: "a", Args: "-j"}, FileMetadata{File: "a"})
+	c.Set(CacheKey{File: "a", Args: "-j\n-g"}, FileMetadata{File: "a"})
+	c.Set(CacheKey{File: "b"}, FileMetadata{File: "b"})
+
+	e := &Exiftool{cache: c}
+	e.invalidateCache("a", "c")
+	assert.Equal(t, 1, c.Len())
+	_, found := c.Get(CacheKey{File: "b"})
+	assert.True(t, found)
+
+	(&Exiftool{}).invalidateCache("a")
+}
+
+func TestCacheKey(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/empty.jpg")
+	defer clean()
+
+	k1, err := cacheKey(OSFileSystem{}, f, []string{"-j", "-g"})
+	assert.Nil(t, err)
+	k2, err := cacheKey(OSFileSystem{}, f, []string{"-j", "-g"})
+	assert.Nil(t, err)
+	assert.Equal(t, k1, k2)
+
+	k3, err := cacheKey(OSFileSystem{}, f, []string{"-j", "-g1"})
+	assert.Nil(t, err)
+	assert.NotEqual(t, k1, k3)
+
+	assert.Nil(t, ioutil.WriteFile(f, []byte("changed"), 0644))
+	mt := time.Now().Add(time.Hour)
+	assert.Nil(t, os.Chtimes(f, mt, mt))
+	k4, err := cacheKey(OSFileSystem{}, f, []string{"-j", "-g"})
+	assert.Nil(t, err)
+	assert.NotEqual(t, k1, k4)
+
+	_, err = cacheKey(OSFileSystem{}, "./testdata/nonExisting", nil)
+	assert.NotNil(t, err)
+}
+
+func TestNewExifTool_WithCache(t *testing.T) {
+	c := NewLRUCache(10)
+	l := &recordingLogger{}
+	e, err := NewExiftool(SetCache(c), SetLogger(l))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	for i := 0; i < 2; i++ {
+		fms := e.ExtractMetadata("./testdata/20190404_131804.jpg", "./testdata/nonExisting")
+		assert.Equal(t, 2, len(fms))
+		assert.Nil(t, fms[0].Err)
+		assert.NotNil(t, fms[1].Err)
+	}
+	assert.Equal(t, 1, c.Len())
+	assert.Equal(t, []string{"command", "extraction", "extraction", "extraction", "extraction"}, l.messages())
+
+	fms := e.Extract([]string{"./testdata/20190404_131804.jpg"}, WithFast(1))
+	assert.Nil(t, fms[0].Err)
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestRefreshCache(t *testing.T) {
+	c := NewLRUCache(10)
+	e, err := NewExiftool(SetCache(c))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	f := "./testdata/20190404_131804.jpg"
+	k, err := cacheKey(e.fileSystem(), f, append(append([]string{}, e.extraInitArgs...), e.extractArgs(e.extractConfig())...))
+	assert.Nil(t, err)
+	stale := FileMetadata{File: f, Groups: map[string]FileMetadataValues{"Stale": {}}}
+
+	for _, size := range []int{1, 2} {
+		c.Set(k, stale)
+		fms := e.Extract([]string{f, f}, WithBatchSize(size))
+		assert.Equal(t, stale.Groups, fms[0].Groups)
+		assert.Equal(t, stale.Groups, fms[1].Groups)
+
+		fms = e.Extract([]string{f, f}, WithBatchSize(size), refreshCache())
+		for _, fm := range fms {
+			assert.Nil(t, fm.Err)
+			assert.NotEqual(t, stale.Groups, fm.Groups)
+		}
+		cached, found := c.Get(k)
+		assert.True(t, found)
+		assert.Equal(t, fms[0].Groups, cached.Groups)
+	}
+}
@@ -0,0 +1,120 @@
+package exiftool
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// CameraInfo identifies the camera and lens that took a picture, along with the main
+// shooting settings. Fields are zero when the corresponding tag can't be found.
+// Focal lengths are in mm.
+type CameraInfo struct {
+	Make             string
+	Model            string
+	SerialNumber     string
+	LensSerialNumber string
+	LensModel        string
+	LensID           string
+	FocalLength      float64
+	FocalLength35mm  float64
+	Aperture         float64
+	ISO              int
+	ShutterSpeed     time.Duration
+}
+
+// Keys of the camera tags, from the standard EXIF and XMP tags to the Canon, Nikon and
+// Sony MakerNotes equivalents
+var (
+	cameraMakeKeys         = []string{"EXIF:Make", "QuickTime:Make", "XMP:Make", "Make"}
+	cameraModelKeys        = []string{"EXIF:Model", "QuickTime:Model", "XMP:Model", "Model"}
+	cameraSerialKeys       = []string{"EXIF:SerialNumber", "MakerNotes:SerialNumber", "MakerNotes:InternalSerialNumber", "XMP:SerialNumber"}
+	cameraLensSerialKeys   = []string{"EXIF:LensSerialNumber", "MakerNotes:LensSerialNumber", "XMP:LensSerialNumber"}
+	cameraLensModelKeys    = []string{"EXIF:LensModel", "MakerNotes:LensModel", "MakerNotes:Lens", "XMP:LensModel", "XMP:Lens"}
+	cameraLensIDKeys       = []string{"Composite:LensID", "MakerNotes:LensID", "MakerNotes:LensIDNumber", "MakerNotes:LensType", "XMP:LensID"}
+	cameraFocalLengthKeys  = []string{"EXIF:FocalLength", "MakerNotes:FocalLength", "XMP:FocalLength", "FocalLength"}
+	cameraFocal35mmKeys    = []string{"EXIF:FocalLengthIn35mmFormat", "XMP:FocalLengthIn35mmFormat", "Composite:FocalLength35efl"}
+	cameraApertureKeys     = []string{"EXIF:FNumber", "Composite:Aperture", "MakerNotes:FNumber", "XMP:FNumber", "FNumber"}
+	cameraISOKeys          = []string{"EXIF:ISO", "Composite:ISO", "MakerNotes:ISO", "MakerNotes:SonyISO", "MakerNotes:CameraISO", "XMP:ISO", "ISO"}
+	cameraShutterSpeedKeys = []string{"EXIF:ExposureTime", "Composite:ShutterSpeed", "MakerNotes:ExposureTime", "XMP:ExposureTime", "ExposureTime"}
+)
+
+// focalLength35eflRegexp matches the 35mm equivalent of the print converted
+// Composite:FocalLength35efl tag (ie. "4.2 mm (35 mm equivalent: 26.0 mm)")
+var focalLength35eflRegexp = regexp.MustCompile(`equivalent:\s*([\d.]+)\s*mm`)
+
+// CameraInfo returns the camera and lens identification along with the shooting
+// settings, whatever the tags used by the manufacturer. ErrKeyNotFound will be returned
+// if none of them can be found.
+func (fm FileMetadata) CameraInfo() (CameraInfo, error) {
+	var ci CameraInfo
+	found := false
+
+	for _, i := range []struct {
+		keys []string
+		dst  *string
+	}{
+		{cameraMakeKeys, &ci.Make},
+		{cameraModelKeys, &ci.Model},
+		{cameraSerialKeys, &ci.SerialNumber},
+		{cameraLensSerialKeys, &ci.LensSerialNumber},
+		{cameraLensModelKeys, &ci.LensModel},
+		{cameraLensIDKeys, &ci.LensID},
+	} {
+		if s, ok := fm.lookupString(i.keys...); ok {
+			*i.dst, found = strings.TrimSpace(s), true
+		}
+	}
+
+	for _, i := range []struct {
+		keys  []string
+		dst   *float64
+		name  string
+		parse func(string) (float64, error)
+	}{
+		{cameraFocalLengthKeys, &ci.FocalLength, "FocalLength", toFloatFallback},
+		{cameraFocal35mmKeys, &ci.FocalLength35mm, "FocalLengthIn35mmFormat", parseFocalLength35mm},
+		{cameraApertureKeys, &ci.Aperture, "FNumber", toFloatFallback},
+	} {
+		s, ok := fm.lookupString(i.keys...)
+		if !ok {
+			continue
+		}
+		f, err := i.parse(s)
+		if err != nil {
+			return CameraInfo{}, fmt.Errorf("%v parsing error: %w", i.name, err)
+		}
+		*i.dst, found = f, true
+	}
+
+	iso, err := fm.lookupInt(cameraISOKeys...)
+	if err == nil {
+		ci.ISO, found = int(iso), true
+	} else if err != ErrKeyNotFound {
+		return CameraInfo{}, fmt.Errorf("ISO parsing error: %w", err)
+	}
+
+	if s, ok := fm.lookupString(cameraShutterSpeedKeys...); ok {
+		secs, err := toFloatFallback(s)
+		if err != nil {
+			return CameraInfo{}, fmt.Errorf("ExposureTime parsing error: %w", err)
+		}
+		ci.ShutterSpeed, found = secondsToDuration(secs), true
+	}
+
+	if !found {
+		return CameraInfo{}, ErrKeyNotFound
+	}
+
+	return ci, nil
+}
+
+// parseFocalLength35mm parses a 35mm equivalent focal length, either as a plain focal
+// length ("26 mm") or as the print converted Composite:FocalLength35efl tag
+func parseFocalLength35mm(s string) (float64, error) {
+	if m := focalLength35eflRegexp.FindStringSubmatch(s); m != nil {
+		return toFloatFallback(m[1])
+	}
+	return toFloatFallback(s)
+}
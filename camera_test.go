@@ -0,0 +1,115 @@
+package exiftool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFocalLength35mm(t *testing.T) {
+	var tcs = []struct {
+		tcID  string
+		in    string
+		expOk bool
+		expF  float64
+	}{
+		{"numeric", "26", true, 26},
+		{"unit", "26 mm", true, 26},
+		{"efl", "4.2 mm (35 mm equivalent: 26.0 mm)", true, 26},
+		{"invalid", "wide", false, 0},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			f, err := parseFocalLength35mm(tc.in)
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.expF, f)
+			}
+		})
+	}
+}
+
+func TestCameraInfo(t *testing.T) {
+	var tcs = []struct {
+		tcID  string
+		in    map[string]FileMetadataValues
+		expOk bool
+		expCi CameraInfo
+	}{
+		{"exif", map[string]FileMetadataValues{"EXIF": {
+			{"Make", "samsung"},
+			{"Model", "SM-G930F"},
+			{"FocalLength", "4.2 mm"},
+			{"FocalLengthIn35mmFormat", "26 mm"},
+			{"FNumber", float64(1.7)},
+			{"ISO", float64(40)},
+			{"ExposureTime", "1/250"},
+		}}, true, CameraInfo{
+			Make:            "samsung",
+			Model:           "SM-G930F",
+			FocalLength:     4.2,
+			FocalLength35mm: 26,
+			Aperture:        1.7,
+			ISO:             40,
+			ShutterSpeed:    4 * time.Millisecond,
+		}},
+		{"canon", map[string]FileMetadataValues{
+			"EXIF":       {{"Make", "Canon"}, {"Model", "Canon EOS 5D Mark IV"}, {"FNumber", "2.8"}},
+			"MakerNotes": {{"InternalSerialNumber", "XA1234"}, {"LensModel", "EF24-70mm f/2.8L II USM"}, {"LensType", "Canon EF 24-70mm f/2.8L II USM"}, {"CameraISO", "Auto"}},
+			"Composite":  {{"ISO", float64(800)}, {"FocalLength35efl", "50.0 mm (35 mm equivalent: 50.0 mm)"}, {"ShutterSpeed", "2"}},
+		}, true, CameraInfo{
+			Make:            "Canon",
+			Model:           "Canon EOS 5D Mark IV",
+			SerialNumber:    "XA1234",
+			LensModel:       "EF24-70mm f/2.8L II USM",
+			LensID:          "Canon EF 24-70mm f/2.8L II USM",
+			FocalLength35mm: 50,
+			Aperture:        2.8,
+			ISO:             800,
+			ShutterSpeed:    2 * time.Second,
+		}},
+		{"nikon", map[string]FileMetadataValues{
+			"EXIF":       {{"Make", "NIKON CORPORATION "}, {"Model", "NIKON D850"}},
+			"MakerNotes": {{"SerialNumber", "3012345"}, {"Lens", "24-70mm f/2.8"}, {"LensIDNumber", float64(162)}, {"ISO", float64(64)}},
+			"Composite":  {{"LensID", "AF-S Nikkor 24-70mm f/2.8E ED VR"}},
+		}, true, CameraInfo{
+			Make:         "NIKON CORPORATION",
+			Model:        "NIKON D850",
+			SerialNumber: "3012345",
+			LensModel:    "24-70mm f/2.8",
+			LensID:       "AF-S Nikkor 24-70mm f/2.8E ED VR",
+			ISO:          64,
+		}},
+		{"sony", map[string]FileMetadataValues{
+			"EXIF":       {{"Make", "SONY"}, {"LensModel", "FE 24-70mm F2.8 GM"}, {"LensSerialNumber", "1800123"}},
+			"MakerNotes": {{"SonyISO", float64(100)}, {"LensType", "Sony FE 24-70mm F2.8 GM"}},
+		}, true, CameraInfo{
+			Make:             "SONY",
+			LensModel:        "FE 24-70mm F2.8 GM",
+			LensSerialNumber: "1800123",
+			LensID:           "Sony FE 24-70mm F2.8 GM",
+			ISO:              100,
+		}},
+		{"invalidFocalLength", map[string]FileMetadataValues{"EXIF": {{"FocalLength", "wide"}}}, false, CameraInfo{}},
+		{"invalidISO", map[string]FileMetadataValues{"EXIF": {{"ISO", "Auto"}}}, false, CameraInfo{}},
+		{"invalidShutterSpeed", map[string]FileMetadataValues{"EXIF": {{"ExposureTime", "Bulb"}}}, false, CameraInfo{}},
+		{"video", map[string]FileMetadataValues{"QuickTime": {{"Duration", "1 s"}}}, false, CameraInfo{}},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			ci, err := FileMetadata{Groups: tc.in}.CameraInfo()
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.expCi, ci)
+			}
+		})
+	}
+
+	_, err := FileMetadata{}.CameraInfo()
+	assert.Equal(t, ErrKeyNotFound, err)
+}
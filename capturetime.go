@@ -0,0 +1,179 @@
+package exiftool
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxGPSOffset is the largest timezone offset that can be inferred from the GPS time
+const maxGPSOffset = 14 * time.Hour
+
+// zoneRegexp matches the timezone suffixing a date printed by exiftool
+var zoneRegexp = regexp.MustCompile(`(Z|[+-]\d{2}:?\d{2})$`)
+
+// offsetRegexp matches a timezone offset (ie. EXIF:OffsetTimeOriginal)
+var offsetRegexp = regexp.MustCompile(`^([+-])(\d{2}):?(\d{2})$`)
+
+// captureDateSources are the local capture dates looked for by BestCaptureTime, by order
+// of precedence, along with their subseconds and timezone offset tags
+var captureDateSources = []struct {
+	key     string
+	subSec  string
+	offsets []string
+}{
+	{"EXIF:DateTimeOriginal", "EXIF:SubSecTimeOriginal", []string{"EXIF:OffsetTimeOriginal", "EXIF:OffsetTime"}},
+	{"XMP:DateTimeOriginal", "", nil},
+	{"EXIF:CreateDate", "EXIF:SubSecTimeDigitized", []string{"EXIF:OffsetTimeDigitized", "EXIF:OffsetTime"}},
+	{"XMP:CreateDate", "", nil},
+	{"QuickTime:CreationDate", "", nil},
+}
+
+// gpsDateTimeKeys are the keys of the GPS time (UTC)
+var gpsDateTimeKeys = []string{"Composite:GPSDateTime", "XMP:GPSDateTime"}
+
+// CaptureTime is the capture time of a file inferred by BestCaptureTime
+type CaptureTime struct {
+	Time time.Time
+	// Zoned is false if the timezone couldn't be inferred, Time being the local capture
+	// time expressed in UTC
+	Zoned bool
+	// Sources are the keys of the tags used, by order of use
+	Sources []string
+}
+
+// BestCaptureTime returns the most accurate capture time of the file, along with the
+// tags it was inferred from. The local capture time is read from the first tag found
+// among EXIF:DateTimeOriginal, XMP:DateTimeOriginal, EXIF:CreateDate, XMP:CreateDate and
+// QuickTime:CreationDate. Its subseconds are completed with EXIF:SubSecTimeOriginal
+// (EXIF:SubSecTimeDigitized for EXIF:CreateDate) if missing. If it has no timezone, the
+// timezone is read from EXIF:OffsetTimeOriginal (EXIF:OffsetTimeDigitized for
+// EXIF:CreateDate) then EXIF:OffsetTime, and is otherwise inferred from the GPS time
+// (Composite:GPSDateTime, XMP:GPSDateTime) rounded to the quarter-hour. Without local
+// capture time, the GPS time is returned. ErrKeyNotFound will be returned if none of
+// these tags can be found.
+func (fm FileMetadata) BestCaptureTime() (CaptureTime, error) {
+	gps, gpsKey, err := fm.gpsDateTime()
+	if err != nil {
+		return CaptureTime{}, err
+	}
+
+	for _, src := range captureDateSources {
+		s, found := fm.lookupString(src.key)
+		if !found {
+			continue
+		}
+		s = strings.TrimSpace(s)
+		t, err := toDateLayout(s, fm.DateLayout)
+		if err != nil {
+			return CaptureTime{}, fmt.Errorf("%v parsing error: %w", src.key, err)
+		}
+		ct := CaptureTime{Time: t, Zoned: zoneRegexp.MatchString(s), Sources: []string{src.key}}
+
+		if src.subSec != "" && t.Nanosecond() == 0 {
+			if ss, found := fm.lookupString(src.subSec); found {
+				ns, err := parseSubSec(ss)
+				if err != nil {
+					return CaptureTime{}, fmt.Errorf("%v parsing error: %w", src.subSec, err)
+				}
+				ct.Time = ct.Time.Add(ns)
+				ct.Sources = append(ct.Sources, src.subSec)
+			}
+		}
+
+		if ct.Zoned {
+			return ct, nil
+		}
+
+		for _, k := range src.offsets {
+			o, found := fm.lookupString(k)
+			if !found {
+				continue
+			}
+			loc, err := parseOffset(o)
+			if err != nil {
+				return CaptureTime{}, fmt.Errorf("%v parsing error: %w", k, err)
+			}
+			ct.Time = inLocation(ct.Time, loc)
+			ct.Zoned = true
+			ct.Sources = append(ct.Sources, k)
+			return ct, nil
+		}
+
+		if !gps.IsZero() {
+			if loc, ok := gpsLocation(ct.Time, gps); ok {
+				ct.Time = inLocation(ct.Time, loc)
+				ct.Zoned = true
+				ct.Sources = append(ct.Sources, gpsKey)
+			}
+		}
+		return ct, nil
+	}
+
+	if gps.IsZero() {
+		return CaptureTime{}, ErrKeyNotFound
+	}
+	return CaptureTime{Time: gps, Zoned: true, Sources: []string{gpsKey}}, nil
+}
+
+// gpsDateTime returns the GPS time (UTC) and its key, a zero time if it can't be found
+func (fm FileMetadata) gpsDateTime() (time.Time, string, error) {
+	for _, k := range gpsDateTimeKeys {
+		s, found := fm.lookupString(k)
+		if !found {
+			continue
+		}
+		t, err := toDateLayout(s, fm.DateLayout)
+		if err != nil {
+			return time.Time{}, "", fmt.Errorf("%v parsing error: %w", k, err)
+		}
+		return t.UTC(), k, nil
+	}
+	return time.Time{}, "", nil
+}
+
+// parseSubSec parses subseconds (ie. "0937" for 0.0937 second)
+func parseSubSec(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.Trim(s, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid subseconds (%v)", s)
+	}
+	f, err := strconv.ParseFloat("0."+s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid subseconds (%v): %w", s, err)
+	}
+	return time.Duration(math.Round(f * float64(time.Second))), nil
+}
+
+// parseOffset parses a timezone offset (ie. "+02:00")
+func parseOffset(s string) (*time.Location, error) {
+	m := offsetRegexp.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return nil, fmt.Errorf("invalid offset (%v)", s)
+	}
+	h, _ := strconv.Atoi(m[2])
+	min, _ := strconv.Atoi(m[3])
+	secs := h*3600 + min*60
+	if m[1] == "-" {
+		secs = -secs
+	}
+	return time.FixedZone("", secs), nil
+}
+
+// gpsLocation returns the timezone of the local time (expressed in UTC) inferred from
+// the GPS time, ok being false if they are too far apart
+func gpsLocation(local, gps time.Time) (*time.Location, bool) {
+	offset := local.Sub(gps).Round(15 * time.Minute)
+	if offset > maxGPSOffset || offset < -maxGPSOffset {
+		return nil, false
+	}
+	return time.FixedZone("", int(offset/time.Second)), true
+}
+
+// inLocation returns the time of loc having the same wall clock as t
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
@@ -0,0 +1,153 @@
+package exiftool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSubSec(t *testing.T) {
+	var tcs = []struct {
+		in    string
+		expOk bool
+		expD  time.Duration
+	}{
+		{"0937", true, 93700 * time.Microsecond},
+		{"5", true, 500 * time.Millisecond},
+		{" 123456 ", true, 123456 * time.Microsecond},
+		{"", false, 0},
+		{"-5", false, 0},
+		{"1.5", false, 0},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.in, func(t *testing.T) {
+			d, err := parseSubSec(tc.in)
+			assert.Equal(t, tc.expOk, err == nil)
+			assert.Equal(t, tc.expD, d)
+		})
+	}
+}
+
+func TestParseOffset(t *testing.T) {
+	var tcs = []struct {
+		in     string
+		expOk  bool
+		expSec int
+	}{
+		{"+02:00", true, 7200},
+		{"-05:30", true, -19800},
+		{"+0100", true, 3600},
+		{"02:00", false, 0},
+		{"UTC", false, 0},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.in, func(t *testing.T) {
+			loc, err := parseOffset(tc.in)
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				_, offset := time.Date(2020, 1, 1, 0, 0, 0, 0, loc).Zone()
+				assert.Equal(t, tc.expSec, offset)
+			}
+		})
+	}
+}
+
+func TestBestCaptureTime(t *testing.T) {
+	plus2 := time.FixedZone("", 2*3600)
+
+	var tcs = []struct {
+		tcID  string
+		in    map[string]FileMetadataValues
+		expOk bool
+		expCt CaptureTime
+	}{
+		{"offset", map[string]FileMetadataValues{"EXIF": {
+			{"DateTimeOriginal", "2019:04:04 13:18:03"},
+			{"SubSecTimeOriginal", "0937"},
+			{"OffsetTimeOriginal", "+02:00"},
+			{"CreateDate", "2019:04:04 13:18:04"},
+		}}, true, CaptureTime{
+			Time:    time.Date(2019, 4, 4, 13, 18, 3, 93700000, plus2),
+			Zoned:   true,
+			Sources: []string{"EXIF:DateTimeOriginal", "EXIF:SubSecTimeOriginal", "EXIF:OffsetTimeOriginal"},
+		}},
+		{"offsetTime", map[string]FileMetadataValues{"EXIF": {
+			{"DateTimeOriginal", "2019:04:04 13:18:03"},
+			{"OffsetTime", "+02:00"},
+		}}, true, CaptureTime{
+			Time:    time.Date(2019, 4, 4, 13, 18, 3, 0, plus2),
+			Zoned:   true,
+			Sources: []string{"EXIF:DateTimeOriginal", "EXIF:OffsetTime"},
+		}},
+		{"gps", map[string]FileMetadataValues{
+			"EXIF":      {{"DateTimeOriginal", "2019:04:04 13:18:03"}},
+			"Composite": {{"GPSDateTime", "2019:04:04 11:17:58Z"}},
+		}, true, CaptureTime{
+			Time:    time.Date(2019, 4, 4, 13, 18, 3, 0, plus2),
+			Zoned:   true,
+			Sources: []string{"EXIF:DateTimeOriginal", "Composite:GPSDateTime"},
+		}},
+		{"gpsTooFar", map[string]FileMetadataValues{
+			"EXIF":      {{"DateTimeOriginal", "2019:04:04 13:18:03"}},
+			"Composite": {{"GPSDateTime", "2019:04:06 11:17:58Z"}},
+		}, true, CaptureTime{
+			Time:    time.Date(2019, 4, 4, 13, 18, 3, 0, time.UTC),
+			Sources: []string{"EXIF:DateTimeOriginal"},
+		}},
+		{"zonedXMP", map[string]FileMetadataValues{
+			"XMP":       {{"DateTimeOriginal", "2019:04:04 13:18:03.25+02:00"}},
+			"Composite": {{"GPSDateTime", "2019:04:04 09:00:00Z"}},
+		}, true, CaptureTime{
+			Time:    time.Date(2019, 4, 4, 13, 18, 3, 250000000, plus2),
+			Zoned:   true,
+			Sources: []string{"XMP:DateTimeOriginal"},
+		}},
+		{"createDate", map[string]FileMetadataValues{"EXIF": {
+			{"CreateDate", "2019:04:04 13:18:03"},
+			{"SubSecTimeDigitized", "5"},
+			{"OffsetTimeDigitized", "-05:00"},
+		}}, true, CaptureTime{
+			Time:    time.Date(2019, 4, 4, 13, 18, 3, 500000000, time.FixedZone("", -5*3600)),
+			Zoned:   true,
+			Sources: []string{"EXIF:CreateDate", "EXIF:SubSecTimeDigitized", "EXIF:OffsetTimeDigitized"},
+		}},
+		{"unzoned", map[string]FileMetadataValues{"QuickTime": {{"CreationDate", "2019:04:04 13:18:03"}}}, true, CaptureTime{
+			Time:    time.Date(2019, 4, 4, 13, 18, 3, 0, time.UTC),
+			Sources: []string{"QuickTime:CreationDate"},
+		}},
+		{"gpsOnly", map[string]FileMetadataValues{"Composite": {{"GPSDateTime", "2019:04:04 11:17:58Z"}}}, true, CaptureTime{
+			Time:    time.Date(2019, 4, 4, 11, 17, 58, 0, time.UTC),
+			Zoned:   true,
+			Sources: []string{"Composite:GPSDateTime"},
+		}},
+		{"invalidDate", map[string]FileMetadataValues{"EXIF": {{"DateTimeOriginal", "yesterday"}}}, false, CaptureTime{}},
+		{"invalidSubSec", map[string]FileMetadataValues{"EXIF": {{"DateTimeOriginal", "2019:04:04 13:18:03"}, {"SubSecTimeOriginal", "a"}}}, false, CaptureTime{}},
+		{"invalidOffset", map[string]FileMetadataValues{"EXIF": {{"DateTimeOriginal", "2019:04:04 13:18:03"}, {"OffsetTimeOriginal", "CET"}}}, false, CaptureTime{}},
+		{"invalidGPS", map[string]FileMetadataValues{"Composite": {{"GPSDateTime", "now"}}}, false, CaptureTime{}},
+		{"none", map[string]FileMetadataValues{"File": {{"FileName", "a.jpg"}}}, false, CaptureTime{}},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			ct, err := FileMetadata{Groups: tc.in}.BestCaptureTime()
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.True(t, tc.expCt.Time.Equal(ct.Time), ct.Time)
+				_, expOffset := tc.expCt.Time.Zone()
+				_, offset := ct.Time.Zone()
+				assert.Equal(t, expOffset, offset)
+				assert.Equal(t, tc.expCt.Zoned, ct.Zoned)
+				assert.Equal(t, tc.expCt.Sources, ct.Sources)
+			}
+		})
+	}
+
+	_, err := FileMetadata{}.BestCaptureTime()
+	assert.Equal(t, ErrKeyNotFound, err)
+}
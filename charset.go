@@ -0,0 +1,83 @@
+package exiftool
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// charsetOption returns an Option setting the charset of family (activates Exiftool's
+// '-charset FAMILY=CHARSET' parameter)
+func charsetOption(family, charset string) Option {
+	return func(e *Exiftool) error {
+		if charset == "" || strings.ContainsAny(charset, "= \t\r\n") {
+			return fmt.Errorf("invalid %v charset (%q)", family, charset)
+		}
+		e.extraInitArgs = append(e.extraInitArgs, "-charset", family+"="+charset)
+		return nil
+	}
+}
+
+// ExifCharset defines the charset of the EXIF strings (ie. "Latin", "ShiftJIS"), that
+// exiftool converts to UTF-8, for cameras that don't store them as UTF-8, see
+// https://exiftool.org/faq.html#Q10
+// Sample :
+//   e, err := NewExiftool(ExifCharset("Latin"))
+func ExifCharset(charset string) Option {
+	return charsetOption("exif", charset)
+}
+
+// IPTCCharset defines the charset of the IPTC strings (ie. "Latin", "Cyrillic") that
+// don't declare it with IPTC:CodedCharacterSet, that exiftool converts to UTF-8, see
+// https://exiftool.org/faq.html#Q10
+// Sample :
+//   e, err := NewExiftool(IPTCCharset("Cyrillic"))
+func IPTCCharset(charset string) Option {
+	return charsetOption("iptc", charset)
+}
+
+// FilenameCharset defines the charset of the file names (ie. "UTF8", "cp1252"), see
+// https://exiftool.org/faq.html#Q18
+// Sample :
+//   e, err := NewExiftool(FilenameCharset("UTF8"))
+func FilenameCharset(charset string) Option {
+	return charsetOption("filename", charset)
+}
+
+// ValidUTF8 makes sure that every extracted string (nested ones included) is valid UTF-8,
+// invalid bytes being replaced by the Unicode replacement character. It is mostly useful
+// along with a CustomDecoder, the default decoder already producing valid UTF-8 strings
+// from the output of exiftool.
+func ValidUTF8() Option {
+	return func(e *Exiftool) error {
+		e.validUTF8 = true
+		return nil
+	}
+}
+
+// toValidUTF8 replaces invalid UTF-8 bytes of every string of g, recursively
+func toValidUTF8(g FileMetadataValues) {
+	for i := range g {
+		g[i].Value = toValidUTF8Value(g[i].Value)
+	}
+}
+
+func toValidUTF8Value(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		if !utf8.ValidString(v) {
+			return strings.ToValidUTF8(v, string(utf8.RuneError))
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = toValidUTF8Value(v[i])
+		}
+		return v
+	case FileMetadataValues:
+		toValidUTF8(v)
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,44 @@
+package exiftool
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCharsetOptions(t *testing.T) {
+	e := Exiftool{}
+	assert.Nil(t, ExifCharset("Latin")(&e))
+	assert.Nil(t, IPTCCharset("Cyrillic")(&e))
+	assert.Nil(t, FilenameCharset("UTF8")(&e))
+	assert.Equal(t, []string{"-charset", "exif=Latin", "-charset", "iptc=Cyrillic", "-charset", "filename=UTF8"}, e.extraInitArgs)
+
+	assert.NotNil(t, ExifCharset("")(&e))
+	assert.NotNil(t, IPTCCharset("a=b")(&e))
+	assert.NotNil(t, FilenameCharset("a\nb")(&e))
+}
+
+func TestValidUTF8(t *testing.T) {
+	e := Exiftool{}
+	assert.Nil(t, ValidUTF8()(&e))
+	assert.Nil(t, CustomDecoder(func(raw json.RawMessage, fm *FileMetadata) error {
+		fm.Groups = map[string]FileMetadataValues{"EXIF": {
+			{"Artist", "Ren\xe9"},
+			{"Valid", "René"},
+			{"List", []interface{}{"caf\xe9", float64(1)}},
+			{"Struct", FileMetadataValues{{"Name", "\xff"}}},
+		}}
+		return nil
+	})(&e))
+
+	var fm FileMetadata
+	e.decode(&fm, []byte(`[{}]`), nil)
+	assert.Nil(t, fm.Err)
+	assert.Equal(t, FileMetadataValues{
+		{"Artist", "Ren�"},
+		{"Valid", "René"},
+		{"List", []interface{}{"caf�", float64(1)}},
+		{"Struct", FileMetadataValues{{"Name", "�"}}},
+	}, fm.Groups["EXIF"])
+}
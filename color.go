@@ -0,0 +1,33 @@
+package exiftool
+
+// ColorInfo gathers the color related tags of a file. Fields are empty when the
+// corresponding tag can't be found.
+type ColorInfo struct {
+	ColorSpace         string
+	ProfileDescription string
+	WhiteBalance       string
+}
+
+// ColorInfo returns the color space (EXIF ColorSpace, or the ICC profile ColorSpaceData
+// as a fallback), the ICC profile description and the white balance of the file.
+// ErrKeyNotFound will be returned if none of them can be found.
+func (fm FileMetadata) ColorInfo() (ColorInfo, error) {
+	var ci ColorInfo
+	var found [3]bool
+
+	ci.ColorSpace, found[0] = fm.lookupString("EXIF:ColorSpace", "ColorSpace", "ICC_Profile:ColorSpaceData")
+	ci.ProfileDescription, found[1] = fm.lookupString("ICC_Profile:ProfileDescription", "ProfileDescription")
+	ci.WhiteBalance, found[2] = fm.lookupString("EXIF:WhiteBalance", "WhiteBalance")
+
+	if !found[0] && !found[1] && !found[2] {
+		return ColorInfo{}, ErrKeyNotFound
+	}
+
+	return ci, nil
+}
+
+// ExtractICCProfile extracts the raw ICC profile embedded in file, see ExtractBinary.
+// ErrKeyNotFound will be returned if file has no ICC profile.
+func (e *Exiftool) ExtractICCProfile(file string) ([]byte, error) {
+	return e.ExtractBinary(file, "ICC_Profile")
+}
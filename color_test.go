@@ -0,0 +1,65 @@
+package exiftool
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColorInfo(t *testing.T) {
+	var tcs = []struct {
+		tcID  string
+		in    map[string]FileMetadataValues
+		expOk bool
+		expCI ColorInfo
+	}{
+		{
+			"exif",
+			map[string]FileMetadataValues{
+				"EXIF":        {{"ColorSpace", "sRGB"}, {"WhiteBalance", "Auto"}},
+				"ICC_Profile": {{"ColorSpaceData", "RGB "}, {"ProfileDescription", "sRGB IEC61966-2.1"}},
+			},
+			true,
+			ColorInfo{ColorSpace: "sRGB", ProfileDescription: "sRGB IEC61966-2.1", WhiteBalance: "Auto"},
+		},
+		{
+			"iccFallback",
+			map[string]FileMetadataValues{"ICC_Profile": {{"ColorSpaceData", "RGB "}}},
+			true,
+			ColorInfo{ColorSpace: "RGB "},
+		},
+		{
+			"numeric",
+			map[string]FileMetadataValues{"EXIF": {{"ColorSpace", float64(1)}, {"WhiteBalance", float64(0)}}},
+			true,
+			ColorInfo{ColorSpace: "1", WhiteBalance: "0"},
+		},
+		{"none", map[string]FileMetadataValues{"File": {{"FileName", "a.jpg"}}}, false, ColorInfo{}},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			ci, err := FileMetadata{Groups: tc.in}.ColorInfo()
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.expCI, ci)
+			} else {
+				assert.Equal(t, ErrKeyNotFound, err)
+			}
+		})
+	}
+}
+
+func TestExtractICCProfile(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	_, err = e.ExtractICCProfile("./testdata/empty.jpg")
+	assert.Equal(t, ErrKeyNotFound, err)
+
+	_, err = e.ExtractICCProfile("./testdata/nonExisting")
+	assert.True(t, errors.Is(err, ErrNotExist))
+}
@@ -0,0 +1,40 @@
+package exiftool
+
+// compositeGroup is the exiftool group of the composite tags
+const compositeGroup = "Composite"
+
+// NoComposites disables the composite tags (activates Exiftool's '-e' parameter): only
+// the values stored in the files are extracted, without the ones derived by exiftool
+// (ie. Composite:GPSPosition, Composite:ImageSize), which also speeds up extractions.
+// Sample :
+//   e, err := NewExiftool(NoComposites())
+func NoComposites() Option {
+	return func(e *Exiftool) error {
+		e.extraInitArgs = append(e.extraInitArgs, "-e")
+		return nil
+	}
+}
+
+// OnlyComposites only extracts the composite tags (-Composite:all) for a single
+// extraction, see https://exiftool.org/TagNames/Composite.html. It can be combined with
+// WithTags to extract some stored tags too, but not with NoComposites.
+// Sample :
+//   fms := e.Extract(files, OnlyComposites())
+func OnlyComposites() ExtractOption {
+	return WithTags(compositeGroup + ":all")
+}
+
+// Composites returns the composite tags, derived by exiftool from the stored ones, of
+// every group belonging to the Composite group (whatever the group families), in the
+// alphabetical order of the groups. It is empty if NoComposites is used.
+// Sample :
+//   pos, err := fm.Composites().GetString("GPSPosition")
+func (fm FileMetadata) Composites() FileMetadataValues {
+	var res FileMetadataValues
+	for _, n := range fm.groupNames() {
+		if hasComponent(n, compositeGroup) {
+			res = append(res, fm.Groups[n]...)
+		}
+	}
+	return res
+}
@@ -0,0 +1,70 @@
+package exiftool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoComposites(t *testing.T) {
+	e := Exiftool{}
+	assert.Nil(t, NoComposites()(&e))
+	assert.Equal(t, []string{"-e"}, e.extraInitArgs)
+}
+
+func TestOnlyComposites(t *testing.T) {
+	e := Exiftool{}
+	cfg, err := e.newExtractConfig([]ExtractOption{OnlyComposites(), WithTags("EXIF:Make")})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"-j", "-g", "-Composite:all", "-EXIF:Make"}, e.extractArgs(cfg))
+}
+
+func TestComposites(t *testing.T) {
+	var tcs = []struct {
+		tcID string
+		in   map[string]FileMetadataValues
+		exp  FileMetadataValues
+	}{
+		{"family0", map[string]FileMetadataValues{
+			"EXIF":      {{"Make", "samsung"}},
+			"Composite": {{"ImageSize", "4032x3024"}, {"GPSPosition", "48.8, 2.3"}},
+		}, FileMetadataValues{{"ImageSize", "4032x3024"}, {"GPSPosition", "48.8, 2.3"}}},
+		{"combined", map[string]FileMetadataValues{
+			"EXIF:IFD0":           {{"Make", "samsung"}},
+			"Composite:Composite": {{"ImageSize", "4032x3024"}},
+			"Composite:Doc1":      {{"GPSPosition", "48.8, 2.3"}},
+		}, FileMetadataValues{{"ImageSize", "4032x3024"}, {"GPSPosition", "48.8, 2.3"}}},
+		{"none", map[string]FileMetadataValues{"EXIF": {{"Make", "samsung"}}}, nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			assert.Equal(t, tc.exp, FileMetadata{Groups: tc.in}.Composites())
+		})
+	}
+}
+
+func TestNewExifTool_WithNoComposites(t *testing.T) {
+	e, err := NewExiftool(NoComposites())
+	assert.Nil(t, err)
+	defer e.Close()
+
+	fms := e.ExtractMetadata("./testdata/20190404_131804.jpg")
+	assert.Equal(t, 1, len(fms))
+	assert.Nil(t, fms[0].Err)
+	assert.Empty(t, fms[0].Composites())
+	_, found := fms[0].Groups["EXIF"]
+	assert.True(t, found)
+
+	e2, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e2.Close()
+
+	fms = e2.Extract([]string{"./testdata/20190404_131804.jpg"}, OnlyComposites())
+	assert.Equal(t, 1, len(fms))
+	assert.Nil(t, fms[0].Err)
+	assert.NotEmpty(t, fms[0].Composites())
+	_, found = fms[0].Groups["EXIF"]
+	assert.False(t, found)
+}
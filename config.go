@@ -0,0 +1,130 @@
+package exiftool
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+// CompositeTable is the exiftool table of the composite tags
+const CompositeTable = "Image::ExifTool::Composite"
+
+// UserDefinedTag is the Go declaration of a tag of an exiftool configuration file, see
+// https://exiftool.org/config.html. Table is the exiftool table the tag is added to (ie.
+// "Image::ExifTool::XMP::xmp", "Image::ExifTool::Exif::Main" or CompositeTable). ID is
+// the tag ID for the tables that are not keyed by name (ie. "0xd000" for EXIF), Name being
+// used if empty. Writable, List and WriteGroup define how the tag is written. Require and
+// ValueConv (a Perl expression on @val) define a composite tag.
+type UserDefinedTag struct {
+	Table      string
+	ID         string
+	Name       string
+	Writable   string
+	List       string
+	WriteGroup string
+	Require    []string
+	ValueConv  string
+}
+
+// WriteConfig writes the exiftool configuration file declaring tags to w. If anything
+// went wrong, a non empty error will be returned.
+func WriteConfig(w io.Writer, tags ...UserDefinedTag) error {
+	tables := map[string][]UserDefinedTag{}
+	for _, t := range tags {
+		if t.Table == "" || t.Name == "" {
+			return fmt.Errorf("table and name are mandatory (%+v)", t)
+		}
+		tables[t.Table] = append(tables[t.Table], t)
+	}
+
+	names := make([]string, 0, len(tables))
+	for n := range tables {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("%Image::ExifTool::UserDefined = (\n")
+	for _, n := range names {
+		fmt.Fprintf(&b, "    %v => {\n", perlString(n))
+		for _, t := range tables[n] {
+			writeUserDefinedTag(&b, t)
+		}
+		b.WriteString("    },\n")
+	}
+	b.WriteString(");\n1;\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeUserDefinedTag(b *strings.Builder, t UserDefinedTag) {
+	key := perlString(t.Name)
+	if strings.HasPrefix(t.ID, "0x") {
+		key = t.ID
+	} else if t.ID != "" {
+		key = perlString(t.ID)
+	}
+
+	var props []string
+	if t.ID != "" {
+		props = append(props, "Name => "+perlString(t.Name))
+	}
+	if t.Writable != "" {
+		props = append(props, "Writable => "+perlString(t.Writable))
+	}
+	if t.List != "" {
+		props = append(props, "List => "+perlString(t.List))
+	}
+	if t.WriteGroup != "" {
+		props = append(props, "WriteGroup => "+perlString(t.WriteGroup))
+	}
+	if len(t.Require) > 0 {
+		req := make([]string, len(t.Require))
+		for i, r := range t.Require {
+			req[i] = fmt.Sprintf("%d => %v", i, perlString(r))
+		}
+		props = append(props, "Require => { "+strings.Join(req, ", ")+" }")
+	}
+	if t.ValueConv != "" {
+		props = append(props, "ValueConv => "+perlString(t.ValueConv))
+	}
+
+	fmt.Fprintf(b, "        %v => { %v },\n", key, strings.Join(props, ", "))
+}
+
+// perlString returns s as a single-quoted Perl string
+func perlString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// WriteTempConfig writes the exiftool configuration file declaring tags (see
+// WriteConfig) to a new temporary file and returns its path, that has to be removed by
+// the caller once every Exiftool using it is closed. If anything went wrong, a non empty
+// error will be returned.
+// Sample :
+//   p, err := WriteTempConfig(UserDefinedTag{Table: "Image::ExifTool::XMP::xmp", Name: "Project", Writable: "string"})
+//   defer os.Remove(p)
+//   e, err := NewExiftool(ConfigFile(p))
+func WriteTempConfig(tags ...UserDefinedTag) (string, error) {
+	f, err := ioutil.TempFile("", "go-exiftool-*.config")
+	if err != nil {
+		return "", fmt.Errorf("error when creating config file: %w", err)
+	}
+
+	if err := WriteConfig(f, tags...); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", fmt.Errorf("error when writing config file: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("error when closing config file: %w", err)
+	}
+
+	return f.Name(), nil
+}
@@ -0,0 +1,59 @@
+package exiftool
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteConfig(t *testing.T) {
+	var b strings.Builder
+	err := WriteConfig(&b,
+		UserDefinedTag{Table: "Image::ExifTool::XMP::xmp", Name: "Project", Writable: "string"},
+		UserDefinedTag{Table: "Image::ExifTool::Exif::Main", ID: "0xd000", Name: "Shot", Writable: "int16u", WriteGroup: "IFD0"},
+		UserDefinedTag{Table: "Image::ExifTool::XMP::xmp", Name: "Tags", Writable: "string", List: "Bag"},
+		UserDefinedTag{Table: CompositeTable, Name: "MakeModel", Require: []string{"Make", "Model"}, ValueConv: `"$val[0] $val[1]"`},
+		UserDefinedTag{Table: CompositeTable, Name: "Quote", Require: []string{"Make"}, ValueConv: `'it\'s'`},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, `%Image::ExifTool::UserDefined = (
+    'Image::ExifTool::Composite' => {
+        'MakeModel' => { Require => { 0 => 'Make', 1 => 'Model' }, ValueConv => '"$val[0] $val[1]"' },
+        'Quote' => { Require => { 0 => 'Make' }, ValueConv => '\'it\\\'s\'' },
+    },
+    'Image::ExifTool::Exif::Main' => {
+        0xd000 => { Name => 'Shot', Writable => 'int16u', WriteGroup => 'IFD0' },
+    },
+    'Image::ExifTool::XMP::xmp' => {
+        'Project' => { Writable => 'string' },
+        'Tags' => { Writable => 'string', List => 'Bag' },
+    },
+);
+1;
+`, b.String())
+
+	assert.NotNil(t, WriteConfig(&b, UserDefinedTag{Name: "a"}))
+	assert.NotNil(t, WriteConfig(&b, UserDefinedTag{Table: CompositeTable}))
+}
+
+func TestWriteTempConfig(t *testing.T) {
+	_, err := WriteTempConfig(UserDefinedTag{})
+	assert.NotNil(t, err)
+
+	p, err := WriteTempConfig(UserDefinedTag{Table: CompositeTable, Name: "MakeModel", Require: []string{"Make", "Model"}, ValueConv: `"$val[0] $val[1]"`})
+	assert.Nil(t, err)
+	defer os.Remove(p)
+
+	e, err := NewExiftool(ConfigFile(p))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	fms := e.ExtractMetadata("./testdata/20190404_131804.jpg")
+	assert.Equal(t, 1, len(fms))
+	assert.Nil(t, fms[0].Err)
+	mm, err := fms[0].Groups["Composite"].GetString("MakeModel")
+	assert.Nil(t, err)
+	assert.Equal(t, "samsung SM-G930F", mm)
+}
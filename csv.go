@@ -0,0 +1,66 @@
+package exiftool
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// csvArgs returns the arguments of a CSV export of tags, files excluded
+func (e *Exiftool) csvArgs(tags []string) ([]string, error) {
+	args := append([]string{}, e.extraInitArgs...)
+	args = append(args, "-csv")
+	if e.fast > 0 {
+		args = append(args, fastArg(e.fast))
+	}
+	for _, t := range tags {
+		if t == "" || strings.ContainsAny(t, "\r\n") {
+			return nil, fmt.Errorf("invalid tag (%q)", t)
+		}
+		args = append(args, "-"+t)
+	}
+	return append(args, "-@", "-"), nil
+}
+
+// ExtractCSV writes to w the tags of files as CSV (activates Exiftool's '-csv'
+// parameter): a header row (SourceFile followed by the tag names) and a row per file.
+// Every tag is exported if tags is empty. Tags can be prefixed by a group and wildcards
+// are supported (ie. "GPS:all"), the columns being labeled by tag name.
+// As exiftool reads every file before printing the CSV, the export is run by a
+// dedicated exiftool process whose output is copied to w as it is printed, the file
+// list being read by exiftool from stdin (-@ -). If anything went wrong, a non empty
+// error will be returned.
+// Sample :
+//   err := e.ExtractCSV(files, []string{"EXIF:DateTimeOriginal", "Make", "Model"}, os.Stdout)
+func (e *Exiftool) ExtractCSV(files []string, tags []string, w io.Writer) error {
+	if len(files) == 0 {
+		return fmt.Errorf("no file to export")
+	}
+	for _, f := range files {
+		if err := e.checkFile(f); err != nil {
+			return err
+		}
+	}
+
+	args, err := e.csvArgs(tags)
+	if err != nil {
+		return err
+	}
+
+	var stderr bytes.Buffer
+	cmd := e.command(args...)
+	cmd.Stdin = strings.NewReader(strings.Join(files, "\n") + "\n")
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+
+	err = e.startCommand(cmd)
+	if err == nil {
+		err = cmd.Wait()
+	}
+	if err != nil {
+		return fmt.Errorf("error when executing command (%v): %w", strings.TrimSpace(stderr.String()), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package exiftool
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVArgs(t *testing.T) {
+	var tcs = []struct {
+		tcID    string
+		inOpts  []Option
+		inTags  []string
+		expOk   bool
+		expArgs []string
+	}{
+		{"allTags", nil, nil, true, []string{"-csv", "-@", "-"}},
+		{"tags", nil, []string{"EXIF:Make", "Model"}, true, []string{"-csv", "-EXIF:Make", "-Model", "-@", "-"}},
+		{"options", []Option{NoPrintConversion(), Fast(2)}, []string{"Make"}, true, []string{"-n", "-csv", "-fast2", "-Make", "-@", "-"}},
+		{"emptyTag", nil, []string{""}, false, nil},
+		{"lineBreak", nil, []string{"a\nb"}, false, nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			e := Exiftool{}
+			for _, opt := range tc.inOpts {
+				assert.Nil(t, opt(&e))
+			}
+			args, err := e.csvArgs(tc.inTags)
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.expArgs, args)
+			}
+		})
+	}
+}
+
+func TestExtractCSV(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	var buf bytes.Buffer
+	assert.NotNil(t, e.ExtractCSV(nil, nil, &buf))
+	assert.True(t, errors.Is(e.ExtractCSV([]string{"./testdata/nonExisting"}, nil, &buf), ErrNotExist))
+	assert.NotNil(t, e.ExtractCSV([]string{"./testdata/20190404_131804.jpg"}, []string{""}, &buf))
+
+	assert.Nil(t, e.ExtractCSV([]string{"./testdata/20190404_131804.jpg"}, []string{"Make", "Model"}, &buf))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{
+		{"SourceFile", "Make", "Model"},
+		{"./testdata/20190404_131804.jpg", "samsung", "SM-G930F"},
+	}, records)
+}
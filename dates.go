@@ -0,0 +1,65 @@
+package exiftool
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dateLayoutCheck is the date used to check the layouts provided to DateFormat
+var dateLayoutCheck = time.Date(2006, 1, 2, 15, 4, 5, 0, time.FixedZone("", -7*3600))
+
+// DateFormat makes exiftool print the dates with format (activates Exiftool's '-d'
+// parameter, see https://exiftool.org/ExifTool.html#DateFormat) and the date getters
+// parse them with layout, the matching Go layout (see
+// https://golang.org/pkg/time/#pkg-constants), instead of guessing among the default
+// exiftool representations. Dates that can't be parsed with layout, like the ones
+// stored without their time zone, fall back to the default representations.
+// Sample :
+//   e, err := NewExiftool(DateFormat("%Y-%m-%dT%H:%M:%S%z", "2006-01-02T15:04:05-0700"))
+func DateFormat(format, layout string) Option {
+	return func(e *Exiftool) error {
+		if format == "" || strings.ContainsAny(format, "\r\n") {
+			return fmt.Errorf("invalid date format (%q)", format)
+		}
+		if layout == "" {
+			return fmt.Errorf("empty date layout")
+		}
+		t, err := time.Parse(layout, dateLayoutCheck.Format(layout))
+		if err != nil {
+			return fmt.Errorf("invalid date layout (%q): %w", layout, err)
+		}
+		if t.YearDay() != dateLayoutCheck.YearDay() || t.Year() != dateLayoutCheck.Year() {
+			return fmt.Errorf("date layout without date (%q)", layout)
+		}
+		e.extraInitArgs = append(e.extraInitArgs, "-d", format)
+		e.dateLayout = layout
+		return nil
+	}
+}
+
+// toDateLayout parses str with layout first (if not empty), then with the default
+// exiftool representations
+func toDateLayout(str, layout string) (time.Time, error) {
+	if layout != "" {
+		if t, err := time.Parse(layout, strings.TrimSpace(str)); err == nil {
+			return t, nil
+		}
+	}
+	return toDate(str)
+}
+
+// GetDate returns the value of the key k ("GROUP:LABEL" or "LABEL", see Decode) as
+// time.Time, using the layout provided to DateFormat if any.
+// ErrKeyNotFound will be returned if the key can't be found.
+// Sample :
+//   d, err := fm.GetDate("EXIF:DateTimeOriginal")
+func (fm FileMetadata) GetDate(k string) (time.Time, error) {
+	g, label, found := fm.lookup(k)
+	if !found {
+		return time.Time{}, ErrKeyNotFound
+	}
+
+	v, _ := g.field(label)
+	return toDateLayout(toString(v), fm.DateLayout)
+}
@@ -0,0 +1,115 @@
+package exiftool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testDateLayout = "2006-01-02T15:04:05-0700"
+
+func TestDateFormat(t *testing.T) {
+	var tcs = []struct {
+		tcID     string
+		inFormat string
+		inLayout string
+		expOk    bool
+	}{
+		{"ok", "%Y-%m-%dT%H:%M:%S%z", testDateLayout, true},
+		{"emptyFormat", "", testDateLayout, false},
+		{"lineBreak", "%Y\n", testDateLayout, false},
+		{"emptyLayout", "%Y", "", false},
+		{"noDate", "%H:%M", "15:04", false},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			e := Exiftool{}
+			err := DateFormat(tc.inFormat, tc.inLayout)(&e)
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, []string{"-d", tc.inFormat}, e.extraInitArgs)
+				assert.Equal(t, tc.inLayout, e.dateLayout)
+			}
+		})
+	}
+}
+
+func TestToDateLayout(t *testing.T) {
+	cest := time.FixedZone("", 7200)
+
+	var tcs = []struct {
+		tcID     string
+		in       string
+		inLayout string
+		expOk    bool
+		expDate  time.Time
+	}{
+		{"layout", "2019-04-04T13:18:04+0200", testDateLayout, true, time.Date(2019, 4, 4, 13, 18, 4, 0, cest)},
+		{"fallback", "2019:04:04 13:18:04", testDateLayout, true, time.Date(2019, 4, 4, 13, 18, 4, 0, time.UTC)},
+		{"noLayout", "2019:04:04 13:18:04+02:00", "", true, time.Date(2019, 4, 4, 13, 18, 4, 0, cest)},
+		{"invalid", "yesterday", testDateLayout, false, time.Time{}},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			d, err := toDateLayout(tc.in, tc.inLayout)
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.True(t, tc.expDate.Equal(d), "%v != %v", tc.expDate, d)
+			}
+		})
+	}
+}
+
+func TestFileMetadataGetDate(t *testing.T) {
+	fm := FileMetadata{
+		Groups: map[string]FileMetadataValues{
+			"EXIF":      {{"DateTimeOriginal", "2019-04-04T13:18:04+0200"}},
+			"Composite": {{"SubSecDateTimeOriginal", "04/04/2019"}},
+		},
+		DateLayout: testDateLayout,
+	}
+
+	d, err := fm.GetDate("EXIF:DateTimeOriginal")
+	assert.Nil(t, err)
+	assert.True(t, time.Date(2019, 4, 4, 11, 18, 4, 0, time.UTC).Equal(d), "unexpected date %v", d)
+
+	d, err = fm.GetDate("DateTimeOriginal")
+	assert.Nil(t, err)
+	assert.True(t, time.Date(2019, 4, 4, 11, 18, 4, 0, time.UTC).Equal(d), "unexpected date %v", d)
+
+	_, err = fm.GetDate("SubSecDateTimeOriginal")
+	assert.NotNil(t, err)
+
+	_, err = fm.GetDate("CreateDate")
+	assert.Equal(t, ErrKeyNotFound, err)
+
+	var s struct {
+		Date time.Time `exiftool:"EXIF:DateTimeOriginal"`
+	}
+	assert.Nil(t, fm.Decode(&s))
+	assert.True(t, d.Equal(s.Date), "unexpected date %v", s.Date)
+}
+
+func TestNewExifTool_WithDateFormat(t *testing.T) {
+	e, err := NewExiftool(DateFormat("%Y-%m-%dT%H:%M:%S", "2006-01-02T15:04:05"))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	fms := e.ExtractMetadata("./testdata/20190404_131804.jpg")
+	assert.Equal(t, 1, len(fms))
+	assert.Nil(t, fms[0].Err)
+	assert.Equal(t, "2006-01-02T15:04:05", fms[0].DateLayout)
+
+	s, err := fms[0].Groups["EXIF"].GetString("DateTimeOriginal")
+	assert.Nil(t, err)
+	assert.Equal(t, "2019-04-04T13:18:04", s)
+
+	d, err := fms[0].GetDate("EXIF:DateTimeOriginal")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2019, 4, 4, 13, 18, 4, 0, time.UTC), d)
+}
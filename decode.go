@@ -0,0 +1,191 @@
+package exiftool
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const decodeTag = "exiftool"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Decode populates the struct pointed by v with the values of the fields tagged with
+// `exiftool:"GROUP:LABEL"` (or `exiftool:"LABEL"` to look for the label in every group).
+// Supported field types are string, ints, uints, floats, bool, time.Time and []string.
+// Fields whose key can't be found are left untouched.
+// Sample :
+//   var s struct {
+//     Date  time.Time `exiftool:"EXIF:DateTimeOriginal"`
+//     Width int       `exiftool:"ImageWidth"`
+//   }
+//   err := fm.Decode(&s)
+func (fm FileMetadata) Decode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("decode target must be a non nil pointer to a struct (%T)", v)
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		k, ok := sf.Tag.Lookup(decodeTag)
+		if !ok || k == "" || k == "-" || sf.PkgPath != "" {
+			continue
+		}
+
+		g, label, found := fm.lookup(k)
+		if !found {
+			continue
+		}
+
+		if err := decodeField(rv.Field(i), g, label, fm.DateLayout); err != nil {
+			return fmt.Errorf("error while decoding field %v (%v): %w", sf.Name, k, err)
+		}
+	}
+
+	return nil
+}
+
+// lookup returns the group containing key k, which can be either "GROUP:LABEL" or
+// "LABEL". GROUP is either a key of Groups (possibly combining several families, ie.
+// "EXIF:IFD0:Make") or one of its components (ie. "IFD0:Make"). Groups are scanned in
+// alphabetical order when several of them match.
+func (fm FileMetadata) lookup(k string) (FileMetadataValues, string, bool) {
+	names := fm.groupNames()
+
+	if idx := strings.LastIndex(k, ":"); idx != -1 {
+		grp, label := k[:idx], k[idx+1:]
+		if g, found := fm.Groups[grp]; found {
+			_, found = g.field(label)
+			return g, label, found
+		}
+		for _, n := range names {
+			if !hasComponent(n, grp) {
+				continue
+			}
+			if _, found := fm.Groups[n].field(label); found {
+				return fm.Groups[n], label, true
+			}
+		}
+		return nil, "", false
+	}
+
+	for _, n := range names {
+		if _, found := fm.Groups[n].field(k); found {
+			return fm.Groups[n], k, true
+		}
+	}
+
+	return nil, "", false
+}
+
+// lookupString returns the string value of the first key of ks found, see lookup
+func (fm FileMetadata) lookupString(ks ...string) (string, bool) {
+	for _, k := range ks {
+		if g, label, found := fm.lookup(k); found {
+			s, _ := g.GetString(label)
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// lookupStrings returns the []string value of the first key of ks found, see lookup
+func (fm FileMetadata) lookupStrings(ks ...string) ([]string, bool) {
+	for _, k := range ks {
+		if g, label, found := fm.lookup(k); found {
+			s, _ := g.GetStrings(label)
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// lookupInt returns the int64 value of the first key of ks found, see lookup.
+// ErrKeyNotFound will be returned if none of them can be found.
+func (fm FileMetadata) lookupInt(ks ...string) (int64, error) {
+	for _, k := range ks {
+		if g, label, found := fm.lookup(k); found {
+			return g.GetInt(label)
+		}
+	}
+	return 0, ErrKeyNotFound
+}
+
+// hasComponent returns true if c is one of the components of the combined group name n
+// (ie. "IFD0" for "EXIF:IFD0")
+func hasComponent(n, c string) bool {
+	for _, p := range strings.Split(n, ":") {
+		if p == c {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeField(f reflect.Value, g FileMetadataValues, label, layout string) error {
+	if f.Type() == timeType {
+		v, _ := g.field(label)
+		t, err := toDateLayout(toString(v), layout)
+		if err != nil {
+			return err
+		}
+		f.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		s, err := g.GetString(label)
+		if err != nil {
+			return err
+		}
+		f.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := g.GetInt(label)
+		if err != nil {
+			return err
+		}
+		if f.OverflowInt(i) {
+			return fmt.Errorf("%v overflows %v", i, f.Type())
+		}
+		f.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := g.GetInt(label)
+		if err != nil {
+			return err
+		}
+		if i < 0 || f.OverflowUint(uint64(i)) {
+			return fmt.Errorf("%v overflows %v", i, f.Type())
+		}
+		f.SetUint(uint64(i))
+	case reflect.Float32, reflect.Float64:
+		fl, err := g.GetFloat(label)
+		if err != nil {
+			return err
+		}
+		f.SetFloat(fl)
+	case reflect.Bool:
+		b, err := g.GetBool(label)
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	case reflect.Slice:
+		if f.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported slice type %v", f.Type())
+		}
+		s, err := g.GetStrings(label)
+		if err != nil {
+			return err
+		}
+		f.Set(reflect.ValueOf(s).Convert(f.Type()))
+	default:
+		return fmt.Errorf("unsupported type %v", f.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package exiftool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getDecodeFileMetadata() FileMetadata {
+	return FileMetadata{
+		Groups: map[string]FileMetadataValues{
+			"EXIF": {
+				{"DateTimeOriginal", "2019:04:04 13:18:03"},
+				{"ISO", float64(100)},
+				{"FNumber", float64(1.7)},
+				{"Make", "samsung"},
+			},
+			"File": {
+				{"ImageWidth", float64(4032)},
+				{"Flag", true},
+				{"Enabled", "Yes"},
+			},
+			"XMP": {
+				{"Subject", []interface{}{"a", "b"}},
+				{"Make", "xmpMake"},
+				{"BadInt", "abc"},
+			},
+		},
+	}
+}
+
+func TestDecode(t *testing.T) {
+	var s struct {
+		Date     time.Time `exiftool:"EXIF:DateTimeOriginal"`
+		ISO      int       `exiftool:"EXIF:ISO"`
+		Width    uint32    `exiftool:"ImageWidth"`
+		FNumber  float32   `exiftool:"FNumber"`
+		Make     string    `exiftool:"Make"`
+		XMPMake  string    `exiftool:"XMP:Make"`
+		Subject  []string  `exiftool:"XMP:Subject"`
+		Flag     bool      `exiftool:"File:Flag"`
+		Enabled  bool      `exiftool:"Enabled"`
+		Missing  string    `exiftool:"EXIF:Missing"`
+		Ignored  string    `exiftool:"-"`
+		Untagged string
+	}
+	s.Missing = "untouched"
+
+	assert.Nil(t, getDecodeFileMetadata().Decode(&s))
+	assert.Equal(t, time.Date(2019, 4, 4, 13, 18, 3, 0, time.UTC), s.Date)
+	assert.Equal(t, 100, s.ISO)
+	assert.Equal(t, uint32(4032), s.Width)
+	assert.Equal(t, float32(1.7), s.FNumber)
+	assert.Equal(t, "samsung", s.Make)
+	assert.Equal(t, "xmpMake", s.XMPMake)
+	assert.Equal(t, []string{"a", "b"}, s.Subject)
+	assert.True(t, s.Flag)
+	assert.True(t, s.Enabled)
+	assert.Equal(t, "untouched", s.Missing)
+	assert.Equal(t, "", s.Ignored)
+	assert.Equal(t, "", s.Untagged)
+}
+
+func TestDecodeCombinedGroups(t *testing.T) {
+	fm := FileMetadata{
+		Groups: map[string]FileMetadataValues{
+			"EXIF:IFD0": {{"Make", "samsung"}},
+		},
+		GroupFamilies: []int{0, 1},
+	}
+
+	var s struct {
+		Make     string `exiftool:"EXIF:IFD0:Make"`
+		ExifMake string `exiftool:"EXIF:Make"`
+		IFD0Make string `exiftool:"IFD0:Make"`
+		Missing  string `exiftool:"XMP:Make"`
+	}
+	assert.Nil(t, fm.Decode(&s))
+	assert.Equal(t, "samsung", s.Make)
+	assert.Equal(t, "samsung", s.ExifMake)
+	assert.Equal(t, "samsung", s.IFD0Make)
+	assert.Equal(t, "", s.Missing)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	fm := getDecodeFileMetadata()
+
+	var notStruct int
+	assert.NotNil(t, fm.Decode(notStruct))
+	assert.NotNil(t, fm.Decode(&notStruct))
+
+	var nilPtr *struct{}
+	assert.NotNil(t, fm.Decode(nilPtr))
+
+	var badInt struct {
+		V int `exiftool:"XMP:BadInt"`
+	}
+	assert.NotNil(t, fm.Decode(&badInt))
+
+	var badBool struct {
+		V bool `exiftool:"Make"`
+	}
+	assert.NotNil(t, fm.Decode(&badBool))
+
+	var overflow struct {
+		V int8 `exiftool:"ImageWidth"`
+	}
+	assert.NotNil(t, fm.Decode(&overflow))
+
+	var unsupported struct {
+		V map[string]string `exiftool:"Make"`
+	}
+	assert.NotNil(t, fm.Decode(&unsupported))
+}
@@ -0,0 +1,54 @@
+package exiftool
+
+import "sync/atomic"
+
+// Deduplicate makes the extractions of the Exiftool (or of the Pool when used with
+// NewPool) extract each path once when it appears several times in a batch, the
+// duplicates getting a copy of the FileMetadata of the first occurrence (sharing its
+// Groups, which must hence not be modified). Paths are compared as provided.
+// Sample :
+//   e, err := NewExiftool(Deduplicate())
+func Deduplicate() Option {
+	return func(e *Exiftool) error {
+		e.dedup = true
+		return nil
+	}
+}
+
+// WithDeduplication overrides the deduplication set with Deduplicate for a single
+// extraction
+// Sample :
+//   fms := e.Extract(files, WithDeduplication(true))
+func WithDeduplication(enabled bool) ExtractOption {
+	return func(c *extractConfig) error {
+		c.dedup = enabled
+		return nil
+	}
+}
+
+// Deduplicated returns the number of files whose extraction was skipped because they
+// appeared several times in a batch, see Deduplicate
+func (e *Exiftool) Deduplicated() int64 {
+	return atomic.LoadInt64(&e.dedups)
+}
+
+// Deduplicated returns the number of files whose extraction was skipped because they
+// appeared several times in a batch, see Deduplicate
+func (p *Pool) Deduplicated() int64 {
+	return atomic.LoadInt64(&p.dedups)
+}
+
+// firstOccurrences returns, for each file, the index of its first occurrence in files
+func firstOccurrences(files []string) []int {
+	first := make([]int, len(files))
+	seen := make(map[string]int, len(files))
+	for i, f := range files {
+		if j, found := seen[f]; found {
+			first[i] = j
+			continue
+		}
+		seen[f] = i
+		first[i] = i
+	}
+	return first
+}
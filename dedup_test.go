@@ -0,0 +1,92 @@
+package exiftool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeduplicate(t *testing.T) {
+	e := Exiftool{}
+	assert.Nil(t, Deduplicate()(&e))
+	assert.True(t, e.dedup)
+	assert.True(t, e.extractConfig().dedup)
+
+	cfg, err := e.newExtractConfig([]ExtractOption{WithDeduplication(false)})
+	assert.Nil(t, err)
+	assert.False(t, cfg.dedup)
+}
+
+func TestFirstOccurrences(t *testing.T) {
+	var tcs = []struct {
+		tcID     string
+		in       []string
+		expFirst []int
+	}{
+		{"empty", []string{}, []int{}},
+		{"unique", []string{"a", "b", "c"}, []int{0, 1, 2}},
+		{"duplicates", []string{"a", "b", "a", "a", "b"}, []int{0, 1, 0, 0, 1}},
+		{"asProvided", []string{"a", "./a"}, []int{0, 1}},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			assert.Equal(t, tc.expFirst, firstOccurrences(tc.in))
+		})
+	}
+}
+
+func TestExtractDeduplicated(t *testing.T) {
+	e, err := NewExiftool(Deduplicate())
+	assert.Nil(t, err)
+	defer e.Close()
+
+	files := []string{
+		"./testdata/20190404_131804.jpg",
+		"./testdata/empty.jpg",
+		"./testdata/20190404_131804.jpg",
+		"./testdata/20190404_131804.jpg",
+	}
+	var completed []int
+	fms := e.Extract(files, WithProgress(func(c, total int, file string, elapsed time.Duration) {
+		completed = append(completed, c)
+	}))
+	assert.Equal(t, len(files), len(fms))
+	for i, fm := range fms {
+		assert.Nil(t, fm.Err)
+		assert.Equal(t, files[i], fm.File)
+		assert.Equal(t, i, fm.Index)
+	}
+	assert.Equal(t, fms[0].Groups, fms[2].Groups)
+	assert.Equal(t, []int{1, 2, 3, 4}, completed)
+	assert.Equal(t, int64(2), e.Deduplicated())
+
+	e.Extract(files, WithDeduplication(false))
+	assert.Equal(t, int64(2), e.Deduplicated())
+}
+
+func TestPoolExtractDeduplicated(t *testing.T) {
+	p, err := NewPool(2, Deduplicate())
+	assert.Nil(t, err)
+	defer p.Close()
+
+	files := []string{
+		"./testdata/20190404_131804.jpg",
+		"./testdata/20190404_131804.jpg",
+		"./testdata/empty.jpg",
+		"./testdata/empty.jpg",
+	}
+	fms := p.Extract(files)
+	assert.Equal(t, len(files), len(fms))
+	for i, fm := range fms {
+		assert.Nil(t, fm.Err)
+		assert.Equal(t, files[i], fm.File)
+		assert.Equal(t, i, fm.Index)
+	}
+	assert.Equal(t, int64(2), p.Deduplicated())
+
+	p.Extract(files, WithDeduplication(false))
+	assert.Equal(t, int64(2), p.Deduplicated())
+}
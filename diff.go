@@ -0,0 +1,155 @@
+package exiftool
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// TagChange describes a tag whose value differs between two extractions
+type TagChange struct {
+	Label string
+	Old   interface{}
+	New   interface{}
+}
+
+// MetadataDiff lists, per group, the tags added, removed or changed between two
+// extractions
+type MetadataDiff struct {
+	Added   map[string]FileMetadataValues
+	Removed map[string]FileMetadataValues
+	Changed map[string][]TagChange
+}
+
+// Empty returns true if both extractions hold the same tags and values
+func (d MetadataDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff compares the tags of a and b, group by group. Tags are listed in the order of b
+// (in the order of a for the removed ones). The values of duplicated tags (see
+// AllowDuplicates) are compared as a whole and reported as a []interface{}. Groups
+// describing the file itself (ie. "File:FileName") are compared like the others, they
+// can be removed from the result when comparing different files.
+// Sample :
+//   d := Diff(before, after)
+//   for g, changes := range d.Changed {
+//     ...
+//   }
+func Diff(a, b FileMetadata) MetadataDiff {
+	d := MetadataDiff{
+		Added:   map[string]FileMetadataValues{},
+		Removed: map[string]FileMetadataValues{},
+		Changed: map[string][]TagChange{},
+	}
+
+	names := b.groupNames()
+	for _, n := range a.groupNames() {
+		if _, found := b.Groups[n]; !found {
+			names = append(names, n)
+		}
+	}
+
+	for _, n := range names {
+		oldLabels, oldValues := labelValues(a.Groups[n])
+		newLabels, newValues := labelValues(b.Groups[n])
+
+		for _, l := range newLabels {
+			o, found := oldValues[l]
+			switch {
+			case !found:
+				d.Added[n] = append(d.Added[n], FileMetadataValue{Label: l, Value: newValues[l]})
+			case !reflect.DeepEqual(o, newValues[l]):
+				d.Changed[n] = append(d.Changed[n], TagChange{Label: l, Old: o, New: newValues[l]})
+			}
+		}
+		for _, l := range oldLabels {
+			if _, found := newValues[l]; !found {
+				d.Removed[n] = append(d.Removed[n], FileMetadataValue{Label: l, Value: oldValues[l]})
+			}
+		}
+	}
+
+	return d
+}
+
+// labelValues returns the distinct labels of g in order, and their value (a
+// []interface{} if the label is duplicated)
+func labelValues(g FileMetadataValues) ([]string, map[string]interface{}) {
+	var labels []string
+	values := map[string]interface{}{}
+	for _, f := range g {
+		if _, found := values[f.Label]; !found {
+			labels = append(labels, f.Label)
+			values[f.Label] = f.Value
+		}
+	}
+	for _, l := range labels {
+		if all := g.GetAll(l); len(all) > 1 {
+			values[l] = all
+		}
+	}
+	return labels, values
+}
+
+// DiffFiles extracts the metadata of pathA and pathB and compares them, see Diff. If
+// anything went wrong, a non empty error will be returned.
+// Sample :
+//   d, err := e.DiffFiles("original.jpg", "edited.jpg")
+func (e *Exiftool) DiffFiles(pathA, pathB string) (MetadataDiff, error) {
+	fms := e.ExtractMetadata(pathA, pathB)
+	for _, fm := range fms {
+		if fm.Err != nil {
+			return MetadataDiff{}, fmt.Errorf("error while extracting %v: %w", fm.File, fm.Err)
+		}
+	}
+
+	return Diff(fms[0], fms[1]), nil
+}
+
+// WriteDiff writes values to file like Write, then extracts its metadata again and
+// returns what actually changed compared to the metadata extracted before writing, as
+// exiftool may silently adjust or ignore some values. The groups describing the file
+// itself (ie. "File:FileModifyDate") are left out. If anything went wrong, a non empty
+// error will be returned.
+// Sample :
+//   d, err := e.WriteDiff("a.jpg", FileMetadataValues{{"Artist", "me"}})
+func (e *Exiftool) WriteDiff(file string, values FileMetadataValues) (MetadataDiff, error) {
+	before := e.ExtractMetadata(file)[0]
+	if before.Err != nil {
+		return MetadataDiff{}, fmt.Errorf("error while extracting %v: %w", file, before.Err)
+	}
+
+	if err := e.Write(file, values); err != nil {
+		return MetadataDiff{}, err
+	}
+
+	after := e.ExtractMetadata(file)[0]
+	if after.Err != nil {
+		return MetadataDiff{}, fmt.Errorf("error while extracting %v: %w", file, after.Err)
+	}
+
+	return Diff(before, after).withoutFileGroups(), nil
+}
+
+// withoutFileGroups returns d without the groups describing the file itself
+func (d MetadataDiff) withoutFileGroups() MetadataDiff {
+	for _, m := range []map[string]FileMetadataValues{d.Added, d.Removed} {
+		for n := range m {
+			if fileGroup(n) {
+				delete(m, n)
+			}
+		}
+	}
+	for n := range d.Changed {
+		if fileGroup(n) {
+			delete(d.Changed, n)
+		}
+	}
+	return d
+}
+
+// fileGroup returns true if n is a group describing the file itself (its name, size,
+// dates, ...) or the exiftool run rather than its metadata
+func fileGroup(n string) bool {
+	return hasComponent(n, "File") || hasComponent(n, "System") || hasComponent(n, "ExifTool")
+}
@@ -0,0 +1,88 @@
+package exiftool
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	a := FileMetadata{Groups: map[string]FileMetadataValues{
+		"EXIF": {{"Make", "samsung"}, {"Model", "SM-G930F"}, {"ISO", float64(40)}},
+		"GPS":  {{"GPSLatitude", float64(48.8)}},
+		"XMP":  {{"Subject", "a"}, {"Subject", "b"}},
+	}}
+	b := FileMetadata{Groups: map[string]FileMetadataValues{
+		"EXIF": {{"Make", "samsung"}, {"ISO", float64(100)}, {"Artist", "me"}},
+		"IPTC": {{"Keywords", []interface{}{"a"}}},
+		"XMP":  {{"Subject", "a"}, {"Subject", "c"}},
+	}}
+
+	d := Diff(a, b)
+	assert.False(t, d.Empty())
+	assert.Equal(t, map[string]FileMetadataValues{
+		"EXIF": {{"Artist", "me"}},
+		"IPTC": {{"Keywords", []interface{}{"a"}}},
+	}, d.Added)
+	assert.Equal(t, map[string]FileMetadataValues{
+		"EXIF": {{"Model", "SM-G930F"}},
+		"GPS":  {{"GPSLatitude", float64(48.8)}},
+	}, d.Removed)
+	assert.Equal(t, map[string][]TagChange{
+		"EXIF": {{"ISO", float64(40), float64(100)}},
+		"XMP":  {{"Subject", []interface{}{"a", "b"}, []interface{}{"a", "c"}}},
+	}, d.Changed)
+
+	assert.True(t, Diff(a, a).Empty())
+	assert.True(t, Diff(FileMetadata{}, FileMetadata{}).Empty())
+}
+
+func TestDiffFiles(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	d, err := e.DiffFiles("./testdata/20190404_131804.jpg", "./testdata/20190404_131804.jpg")
+	assert.Nil(t, err)
+	assert.True(t, d.Empty())
+
+	_, err = e.DiffFiles("./testdata/20190404_131804.jpg", "./testdata/nonExisting")
+	assert.True(t, errors.Is(err, ErrNotExist))
+}
+
+func TestDiffWithoutFileGroups(t *testing.T) {
+	a := FileMetadata{Groups: map[string]FileMetadataValues{
+		"File":        {{"FileModifyDate", "2019:04:04 13:18:04"}},
+		"System:File": {{"FileSize", "10 kB"}},
+		"EXIF":        {{"Artist", "a"}},
+	}}
+	b := FileMetadata{Groups: map[string]FileMetadataValues{
+		"File":              {{"FileModifyDate", "2020:01:01 00:00:00"}},
+		"EXIF":              {{"Artist", "b"}},
+		"ExifTool:ExifTool": {{"Warning", "w"}},
+	}}
+
+	d := Diff(a, b).withoutFileGroups()
+	assert.Empty(t, d.Added)
+	assert.Empty(t, d.Removed)
+	assert.Equal(t, map[string][]TagChange{"EXIF": {{"Artist", "a", "b"}}}, d.Changed)
+}
+
+func TestWriteDiff(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	d, err := e.WriteDiff(f, FileMetadataValues{{"Artist", "go-exiftool"}, {"Model", "M"}})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]FileMetadataValues{"EXIF": {{"Artist", "go-exiftool"}}}, d.Added)
+	assert.Empty(t, d.Removed)
+	assert.Equal(t, map[string][]TagChange{"EXIF": {{"Model", "SM-G930F", "M"}}}, d.Changed)
+
+	_, err = e.WriteDiff("./testdata/nonExisting", FileMetadataValues{{"Artist", "a"}})
+	assert.True(t, errors.Is(err, ErrNotExist))
+}
@@ -0,0 +1,270 @@
+package exiftool
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DirOption is a configuration function used by ExtractDir
+type DirOption func(*dirConfig) error
+
+type dirConfig struct {
+	includes   []string
+	excludes   []string
+	extensions map[string]bool
+	progress   ProgressFunc
+	conditions []string
+	batch      int
+	fsys       FileSystem
+}
+
+// IncludeGlob only keeps the files whose name, or path relative to the walked directory,
+// matches one of patterns (see https://golang.org/pkg/path/filepath/#Match)
+// Sample :
+//   c, err := e.ExtractDir("photos", IncludeGlob("IMG_*"))
+func IncludeGlob(patterns ...string) DirOption {
+	return func(c *dirConfig) error {
+		if err := checkGlobs(patterns); err != nil {
+			return err
+		}
+		c.includes = append(c.includes, patterns...)
+		return nil
+	}
+}
+
+// ExcludeGlob skips the files and directories whose name, or path relative to the walked
+// directory, matches one of patterns (see https://golang.org/pkg/path/filepath/#Match)
+// Sample :
+//   c, err := e.ExtractDir("photos", ExcludeGlob(".*", "thumbs"))
+func ExcludeGlob(patterns ...string) DirOption {
+	return func(c *dirConfig) error {
+		if err := checkGlobs(patterns); err != nil {
+			return err
+		}
+		c.excludes = append(c.excludes, patterns...)
+		return nil
+	}
+}
+
+// Extensions only keeps the files having one of exts as extension (case insensitive,
+// with or without the leading dot)
+// Sample :
+//   c, err := e.ExtractDir("photos", Extensions("jpg", "cr2"))
+func Extensions(exts ...string) DirOption {
+	return func(c *dirConfig) error {
+		if c.extensions == nil {
+			c.extensions = map[string]bool{}
+		}
+		for _, ext := range exts {
+			ext = strings.ToLower(strings.TrimPrefix(ext, "."))
+			if ext == "" {
+				return fmt.Errorf("empty extension")
+			}
+			c.extensions[ext] = true
+		}
+		return nil
+	}
+}
+
+func checkGlobs(patterns []string) error {
+	for _, p := range patterns {
+		if _, err := filepath.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid pattern (%v): %w", p, err)
+		}
+	}
+	return nil
+}
+
+func newDirConfig(opts []DirOption) (dirConfig, error) {
+	var c dirConfig
+	for _, opt := range opts {
+		if err := opt(&c); err != nil {
+			return c, fmt.Errorf("error when configuring directory extraction: %w", err)
+		}
+	}
+	return c, nil
+}
+
+func matchAny(patterns []string, name, rel string) bool {
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, name); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(p, rel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// walk calls fn with every file of root (a directory of c.fsys, the OSFileSystem if nil)
+// kept by the configuration, or with the errors raised while walking
+func (c dirConfig) walk(root string, fn func(path string, err error)) {
+	walkFS(orOS(c.fsys), root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				err = &FileNotFoundError{File: path}
+			}
+			fn(path, err)
+			return nil
+		}
+
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			rel = path
+		}
+		rel = filepath.ToSlash(rel)
+
+		if info.IsDir() {
+			if path != root && matchAny(c.excludes, info.Name(), rel) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if matchAny(c.excludes, info.Name(), rel) {
+			return nil
+		}
+		if len(c.includes) > 0 && !matchAny(c.includes, info.Name(), rel) {
+			return nil
+		}
+		if c.extensions != nil {
+			ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+			if !c.extensions[ext] {
+				return nil
+			}
+		}
+
+		fn(path, nil)
+		return nil
+	})
+}
+
+// walkChunks calls fn with the files of root kept by the configuration, by chunks of
+// c.batch files (along with their position in the walk), and errFn with the errors
+// raised while walking
+func (c dirConfig) walkChunks(root string, fn func(idx []int, paths []string), errFn func(i int, path string, err error)) {
+	size := c.batch
+	if size < 1 {
+		size = 1
+	}
+
+	var idx []int
+	var paths []string
+	i := 0
+	c.walk(root, func(path string, err error) {
+		defer func() { i++ }()
+		if err != nil {
+			errFn(i, path, err)
+			return
+		}
+		idx = append(idx, i)
+		paths = append(paths, path)
+		if len(paths) >= size {
+			fn(idx, paths)
+			idx, paths = nil, nil
+		}
+	})
+	if len(paths) > 0 {
+		fn(idx, paths)
+	}
+}
+
+// count returns the number of files (and errors) walk calls fn with
+func (c dirConfig) count(root string) int {
+	n := 0
+	c.walk(root, func(string, error) { n++ })
+	return n
+}
+
+// newProgress returns the progress of the extraction of root, nil if it isn't tracked
+func (c dirConfig) newProgress(root string) *progress {
+	if c.progress == nil {
+		return nil
+	}
+	return newProgress(c.progress, c.count(root))
+}
+
+// extractOptions returns the options of the extraction of each file
+func (c dirConfig) extractOptions() []ExtractOption {
+	var opts []ExtractOption
+	for _, cond := range c.conditions {
+		opts = append(opts, Where(cond))
+	}
+	if c.batch > 1 {
+		opts = append(opts, WithBatchSize(c.batch))
+	}
+	return opts
+}
+
+// send sends fm to res unless it has been skipped by the conditions
+func (c dirConfig) send(res chan<- FileMetadata, fm FileMetadata) {
+	if !errors.Is(fm.Err, ErrConditionNotMet) {
+		res <- fm
+	}
+}
+
+// ExtractDir walks the root directory recursively and streams the metadata of the files
+// kept by opts (files skipped by DirWhere excluded). The returned channel is closed once
+// every file has been processed. Errors raised while walking are sent as
+// FileMetadata.Err. If anything went wrong with opts, a non empty error will be returned.
+func (e *Exiftool) ExtractDir(root string, opts ...DirOption) (<-chan FileMetadata, error) {
+	c, err := newDirConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	c.fsys = e.fsys
+
+	res := make(chan FileMetadata)
+	go func() {
+		defer close(res)
+		prog := c.newProgress(root)
+		c.walkChunks(root, func(idx []int, paths []string) {
+			for j, fm := range e.Extract(paths, c.extractOptions()...) {
+				fm.Index = idx[j]
+				c.send(res, fm)
+				prog.done(paths[j])
+			}
+		}, func(i int, path string, err error) {
+			res <- FileMetadata{File: path, Err: err, Index: i}
+			prog.done(path)
+		})
+	}()
+
+	return res, nil
+}
+
+// ExtractDir behaves like Exiftool.ExtractDir, dispatching the files (or the batches of
+// files, see DirBatchSize) across the workers of the pool. Results are streamed as they
+// complete.
+func (p *Pool) ExtractDir(root string, opts ...DirOption) (<-chan FileMetadata, error) {
+	c, err := newDirConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	c.fsys = p.fsys
+
+	res := make(chan FileMetadata, p.size)
+	go func() {
+		defer close(res)
+
+		prog := c.newProgress(root)
+		sem := make(chan struct{}, p.size)
+		var wg sync.WaitGroup
+		c.walkChunks(root, func(idx []int, paths []string) {
+			p.extractAsync(idx, paths, c.extractOptions(), sem, &wg, func(fm FileMetadata) {
+				c.send(res, fm)
+			}, prog)
+		}, func(i int, path string, err error) {
+			res <- FileMetadata{File: path, Err: err, Index: i}
+			prog.done(path)
+		})
+		wg.Wait()
+	}()
+
+	return res, nil
+}
@@ -0,0 +1,123 @@
+package exiftool
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestTree(t *testing.T, files ...string) (string, func()) {
+	dir, err := ioutil.TempDir("", "go-exiftool")
+	assert.Nil(t, err)
+
+	for _, f := range files {
+		p := filepath.Join(dir, filepath.FromSlash(f))
+		assert.Nil(t, os.MkdirAll(filepath.Dir(p), 0755))
+		assert.Nil(t, ioutil.WriteFile(p, nil, 0644))
+	}
+
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func TestDirConfigWalk(t *testing.T) {
+	root, clean := createTestTree(t,
+		"a.jpg", "b.JPG", "c.png", "d.txt",
+		"sub/e.jpg", "sub/f.cr2",
+		".hidden/g.jpg",
+		"thumbs/h.jpg",
+	)
+	defer clean()
+
+	var tcs = []struct {
+		tcID     string
+		inOpts   []DirOption
+		expFiles []string
+	}{
+		{"all", nil, []string{".hidden/g.jpg", "a.jpg", "b.JPG", "c.png", "d.txt", "sub/e.jpg", "sub/f.cr2", "thumbs/h.jpg"}},
+		{"extensions", []DirOption{Extensions("jpg", ".CR2")}, []string{".hidden/g.jpg", "a.jpg", "b.JPG", "sub/e.jpg", "sub/f.cr2", "thumbs/h.jpg"}},
+		{"include", []DirOption{IncludeGlob("*.png", "sub/*")}, []string{"c.png", "sub/e.jpg", "sub/f.cr2"}},
+		{"excludeDirs", []DirOption{ExcludeGlob(".*", "thumbs")}, []string{"a.jpg", "b.JPG", "c.png", "d.txt", "sub/e.jpg", "sub/f.cr2"}},
+		{"excludeFiles", []DirOption{ExcludeGlob("*.txt"), Extensions("txt", "png")}, []string{"c.png"}},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			c, err := newDirConfig(tc.inOpts)
+			assert.Nil(t, err)
+
+			files := []string{}
+			c.walk(root, func(path string, err error) {
+				assert.Nil(t, err)
+				rel, err := filepath.Rel(root, path)
+				assert.Nil(t, err)
+				files = append(files, filepath.ToSlash(rel))
+			})
+			sort.Strings(files)
+			assert.Equal(t, tc.expFiles, files)
+		})
+	}
+}
+
+func TestDirConfigWalkNonExisting(t *testing.T) {
+	c, err := newDirConfig(nil)
+	assert.Nil(t, err)
+
+	var errs []error
+	c.walk("./testdata/nonExisting", func(path string, err error) {
+		errs = append(errs, err)
+	})
+	assert.Equal(t, 1, len(errs))
+	assert.True(t, errors.Is(errs[0], ErrNotExist))
+}
+
+func TestDirOptionsKo(t *testing.T) {
+	_, err := newDirConfig([]DirOption{IncludeGlob("[")})
+	assert.NotNil(t, err)
+	_, err = newDirConfig([]DirOption{ExcludeGlob("[")})
+	assert.NotNil(t, err)
+	_, err = newDirConfig([]DirOption{Extensions("")})
+	assert.NotNil(t, err)
+}
+
+func TestExtractDir(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	_, err = e.ExtractDir("./testdata", IncludeGlob("["))
+	assert.NotNil(t, err)
+
+	c, err := e.ExtractDir("./testdata", Extensions("jpg"))
+	assert.Nil(t, err)
+
+	files := []string{}
+	for fm := range c {
+		assert.Nil(t, fm.Err)
+		assert.Equal(t, len(files), fm.Index)
+		files = append(files, filepath.Base(fm.File))
+	}
+	sort.Strings(files)
+	assert.Equal(t, []string{"20190404_131804.jpg", "empty.jpg"}, files)
+}
+
+func TestPoolExtractDir(t *testing.T) {
+	p, err := NewPool(2)
+	assert.Nil(t, err)
+	defer p.Close()
+
+	c, err := p.ExtractDir("./testdata", ExcludeGlob("*.jpg"))
+	assert.Nil(t, err)
+
+	files := []string{}
+	for fm := range c {
+		assert.Nil(t, fm.Err)
+		files = append(files, filepath.Base(fm.File))
+	}
+	assert.Equal(t, []string{"extractEmbedded.mp4"}, files)
+}
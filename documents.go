@@ -0,0 +1,83 @@
+package exiftool
+
+import "strings"
+
+// documentFamily is the exiftool group family of the document number
+const documentFamily = 3
+
+// MainDocument is the name of the main document of a file, see Documents
+const MainDocument = "Main"
+
+// ExtractEmbeddedDocuments extracts embedded metadata from files (see ExtractEmbedded)
+// and groups it by document (exiftool's group family 3), so that the metadata of each
+// embedded document (ie. a GoPro GPMF sample, an image embedded in a PDF) is kept apart
+// instead of being merged with the main document. Use Documents to get each document.
+// Sample :
+//   e, err := NewExiftool(ExtractEmbeddedDocuments())
+func ExtractEmbeddedDocuments() Option {
+	return func(e *Exiftool) error {
+		e.extraInitArgs = append(e.extraInitArgs, "-ee")
+		e.documents = true
+		return nil
+	}
+}
+
+// Documents splits the metadata of the file by document, keyed by exiftool's document
+// name: MainDocument for the main document, "Doc1", "Doc2", ... for the embedded ones and
+// "Doc1-1", ... for the documents embedded in embedded documents. The group keys of each
+// document don't contain the document name anymore. It requires the group family 3 (see
+// ExtractEmbeddedDocuments), the whole FileMetadata being the main document otherwise.
+// Sample :
+//   for name, doc := range fm.Documents() {
+//     fmt.Println(name, doc.Groups["QuickTime"])
+//   }
+func (fm FileMetadata) Documents() map[string]FileMetadata {
+	idx := -1
+	for i, f := range fm.GroupFamilies {
+		if f == documentFamily {
+			idx = i
+		}
+	}
+	if idx == -1 {
+		return map[string]FileMetadata{MainDocument: fm}
+	}
+
+	fams := make([]int, 0, len(fm.GroupFamilies)-1)
+	fams = append(fams, fm.GroupFamilies[:idx]...)
+	fams = append(fams, fm.GroupFamilies[idx+1:]...)
+
+	ordered := map[string]bool{}
+	for _, n := range fm.GroupOrder {
+		ordered[n] = true
+	}
+
+	docs := map[string]FileMetadata{}
+	for _, n := range fm.orderedGroupNames() {
+		g := fm.Groups[n]
+		parts := strings.Split(n, ":")
+		if len(parts) != len(fm.GroupFamilies) {
+			continue
+		}
+		doc := parts[idx]
+
+		key := strings.Join(append(append([]string{}, parts[:idx]...), parts[idx+1:]...), ":")
+		if key == "" {
+			key = doc
+		}
+
+		d, found := docs[doc]
+		if !found {
+			d = FileMetadata{File: fm.File, Groups: map[string]FileMetadataValues{}, GroupFamilies: fams, NumericValues: fm.NumericValues, DateLayout: fm.DateLayout}
+			if doc == MainDocument {
+				d.Err, d.Warnings, d.Raw = fm.Err, fm.Warnings, fm.Raw
+			}
+		}
+		d.Groups[key] = g
+		if ordered[n] {
+			d.GroupOrder = append(d.GroupOrder, key)
+		}
+		docs[doc] = d
+	}
+
+	return docs
+}
@@ -0,0 +1,73 @@
+package exiftool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractEmbeddedDocuments(t *testing.T) {
+	e := Exiftool{}
+	assert.Nil(t, ExtractEmbeddedDocuments()(&e))
+	assert.Equal(t, []string{"-ee"}, e.extraInitArgs)
+	assert.Equal(t, []int{3, 0}, e.families())
+	assert.Equal(t, []string{"-j", "-g3:0"}, e.extractArgs(e.extractConfig()))
+
+	assert.Nil(t, GroupFamily(1, 3)(&e))
+	assert.Equal(t, []int{1, 3}, e.families())
+	assert.Equal(t, []string{"-j", "-g1:3"}, e.extractArgs(e.extractConfig()))
+}
+
+func TestDocuments(t *testing.T) {
+	fm := FileMetadata{
+		File:          "a.mp4",
+		GroupFamilies: []int{3, 0},
+		Warnings:      []string{"w"},
+		Groups: map[string]FileMetadataValues{
+			"Main:QuickTime": {{"Duration", "1 s"}},
+			"Main:File":      {{"FileName", "a.mp4"}},
+			"Doc1:QuickTime": {{"SampleTime", "0 s"}},
+			"Doc2:QuickTime": {{"SampleTime", "1 s"}},
+			"invalid":        {{"a", "b"}},
+		},
+	}
+
+	docs := fm.Documents()
+	assert.Equal(t, map[string]FileMetadata{
+		"Main": {
+			File:          "a.mp4",
+			GroupFamilies: []int{0},
+			Warnings:      []string{"w"},
+			Groups: map[string]FileMetadataValues{
+				"QuickTime": {{"Duration", "1 s"}},
+				"File":      {{"FileName", "a.mp4"}},
+			},
+		},
+		"Doc1": {File: "a.mp4", GroupFamilies: []int{0}, Groups: map[string]FileMetadataValues{"QuickTime": {{"SampleTime", "0 s"}}}},
+		"Doc2": {File: "a.mp4", GroupFamilies: []int{0}, Groups: map[string]FileMetadataValues{"QuickTime": {{"SampleTime", "1 s"}}}},
+	}, docs)
+
+	fm.GroupOrder = []string{"Main:QuickTime", "Doc1:QuickTime", "Main:File"}
+	docs = fm.Documents()
+	assert.Equal(t, []string{"QuickTime", "File"}, docs["Main"].GroupOrder)
+	assert.Equal(t, []string{"QuickTime"}, docs["Doc1"].GroupOrder)
+	assert.Nil(t, docs["Doc2"].GroupOrder)
+
+	single := FileMetadata{File: "a.jpg", GroupFamilies: []int{0}}
+	assert.Equal(t, map[string]FileMetadata{"Main": single}, single.Documents())
+}
+
+func TestNewExifTool_WithExtractEmbeddedDocuments(t *testing.T) {
+	e, err := NewExiftool(ExtractEmbeddedDocuments())
+	assert.Nil(t, err)
+	defer e.Close()
+
+	metas := e.ExtractMetadata("./testdata/extractEmbedded.mp4")
+	assert.Equal(t, 1, len(metas))
+	assert.Nil(t, metas[0].Err)
+
+	docs := metas[0].Documents()
+	assert.True(t, len(docs) > 1, "no embedded document")
+	_, found := docs[MainDocument].Groups["QuickTime"]
+	assert.True(t, found)
+}
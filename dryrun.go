@@ -0,0 +1,84 @@
+package exiftool
+
+import (
+	"fmt"
+	"sync"
+)
+
+// CommandRecorder records the commands of the write operations run in dry-run mode, see
+// DryRun. It is safe for concurrent use, hence can be shared by the workers of a Pool.
+type CommandRecorder struct {
+	lock     sync.Mutex
+	commands [][]string
+}
+
+// Commands returns the recorded commands, in the order they were requested. Each
+// command is the argv of the equivalent standalone exiftool invocation.
+func (r *CommandRecorder) Commands() [][]string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	cmds := make([][]string, len(r.commands))
+	for i, c := range r.commands {
+		cmds[i] = append([]string{}, c...)
+	}
+	return cmds
+}
+
+// Reset forgets the recorded commands
+func (r *CommandRecorder) Reset() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.commands = nil
+}
+
+func (r *CommandRecorder) record(argv []string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.commands = append(r.commands, argv)
+}
+
+// DryRun makes the write operations (Write, Delete, CopyTags, Rename, ShiftDates,
+// Geotag...) record their command into r instead of executing it, no file being touched.
+// They succeed as if exiftool had updated the files, Rename returning an empty mapping.
+// Sample :
+//   var r CommandRecorder
+//   e, err := NewExiftool(DryRun(&r))
+//   err = e.Write("photo.jpg", FileMetadataValues{{"Artist", "me"}})
+//   fmt.Println(r.Commands())
+func DryRun(r *CommandRecorder) Option {
+	return func(e *Exiftool) error {
+		if r == nil {
+			return fmt.Errorf("nil command recorder")
+		}
+		e.dryRun = r
+		return nil
+	}
+}
+
+// argv returns the argv of the standalone exiftool invocation equivalent to the
+// command made of args
+func (e *Exiftool) argv(args []string) []string {
+	argv := []string{e.binaryPath}
+	if e.perlPath != "" {
+		argv = []string{e.perlPath, e.binaryPath}
+	}
+	argv = append(argv, e.configArgs...)
+	argv = append(argv, e.extraInitArgs...)
+	return append(argv, args...)
+}
+
+// executeWriting sends the writing command made of args to exiftool, or records it in
+// dry-run mode (an empty output being returned). The caller must hold e.lock.
+func (e *Exiftool) executeWriting(args ...string) ([]byte, error) {
+	if e.dryRun != nil {
+		if e.stopping() {
+			return nil, ErrClosed
+		}
+		e.dryRun.record(e.argv(args))
+		return nil, nil
+	}
+	return e.execute(args...)
+}
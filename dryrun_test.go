@@ -0,0 +1,60 @@
+package exiftool
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandRecorder(t *testing.T) {
+	var r CommandRecorder
+	assert.Equal(t, [][]string{}, r.Commands())
+
+	r.record([]string{"exiftool", "a"})
+	r.record([]string{"exiftool", "b"})
+	cmds := r.Commands()
+	assert.Equal(t, [][]string{{"exiftool", "a"}, {"exiftool", "b"}}, cmds)
+	cmds[0][1] = "c"
+	assert.Equal(t, "a", r.Commands()[0][1])
+
+	r.Reset()
+	assert.Equal(t, [][]string{}, r.Commands())
+}
+
+func TestNewExifTool_WithDryRun(t *testing.T) {
+	_, err := NewExiftool(DryRun(nil))
+	assert.NotNil(t, err)
+
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+	before, err := ioutil.ReadFile(f)
+	assert.Nil(t, err)
+
+	var r CommandRecorder
+	e, err := NewExiftool(DryRun(&r))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	assert.Nil(t, e.Write(f, FileMetadataValues{{"Artist", "me"}}))
+	assert.Nil(t, e.Delete(f, "GPS:all"))
+	assert.Nil(t, e.CopyTags("./testdata/20190404_131804.jpg", f, "Make"))
+	m, err := e.Rename([]string{f}, "%Y.%%e")
+	assert.Nil(t, err)
+	assert.Empty(t, m)
+
+	common := append([]string{e.binaryPath}, e.extraInitArgs...)
+	assert.Equal(t, [][]string{
+		append(append([]string{}, common...), "-Artist=me", f),
+		append(append([]string{}, common...), "-GPS:all=", f),
+		append(append([]string{}, common...), "-tagsFromFile", "./testdata/20190404_131804.jpg", "-Make", f),
+		append(append([]string{}, common...), "-v", "-d", "%Y.%%e", "-FileName<DateTimeOriginal", f),
+	}, r.Commands())
+
+	after, err := ioutil.ReadFile(f)
+	assert.Nil(t, err)
+	assert.Equal(t, before, after)
+
+	assert.Nil(t, e.Close())
+	assert.Equal(t, ErrClosed, e.Write(f, FileMetadataValues{{"Artist", "me"}}))
+}
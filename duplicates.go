@@ -0,0 +1,59 @@
+package exiftool
+
+import "strings"
+
+// TagValue is a value along with the group it was found in
+type TagValue struct {
+	Group string
+	Value interface{}
+}
+
+// AllowDuplicates keeps the duplicated tags (activates Exiftool's '-a' parameter), ie.
+// the same tag found in several IFDs, which are otherwise dropped by exiftool. Use
+// GetAll to retrieve every occurrence.
+// Sample :
+//   e, err := NewExiftool(AllowDuplicates(), GroupFamily(1))
+func AllowDuplicates() Option {
+	return func(e *Exiftool) error {
+		e.extraInitArgs = append(e.extraInitArgs, "-a")
+		return nil
+	}
+}
+
+// GetAll returns every value whose label is k, in the exiftool order, the other getters
+// only returning the first one. It is nil if the key can't be found.
+func (g FileMetadataValues) GetAll(k string) []interface{} {
+	var res []interface{}
+	for _, f := range g {
+		if f.Label == k {
+			res = append(res, f.Value)
+		}
+	}
+	return res
+}
+
+// GetAll returns every occurrence of the key k along with its group, groups being
+// scanned in alphabetical order. k can be either "GROUP:LABEL" or "LABEL", see Decode.
+// It is nil if the key can't be found.
+// Sample :
+//   for _, tv := range fm.GetAll("Orientation") {
+//     fmt.Println(tv.Group, tv.Value)
+//   }
+func (fm FileMetadata) GetAll(k string) []TagValue {
+	grp, label := "", k
+	if idx := strings.LastIndex(k, ":"); idx != -1 {
+		grp, label = k[:idx], k[idx+1:]
+	}
+
+	var res []TagValue
+	for _, n := range fm.groupNames() {
+		if grp != "" && n != grp && !hasComponent(n, grp) {
+			continue
+		}
+		for _, v := range fm.Groups[n].GetAll(label) {
+			res = append(res, TagValue{Group: n, Value: v})
+		}
+	}
+
+	return res
+}
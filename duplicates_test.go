@@ -0,0 +1,56 @@
+package exiftool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowDuplicates(t *testing.T) {
+	e := Exiftool{}
+	assert.Nil(t, AllowDuplicates()(&e))
+	assert.Equal(t, []string{"-a"}, e.extraInitArgs)
+}
+
+func TestGetAll(t *testing.T) {
+	var g FileMetadataValues
+	assert.Nil(t, g.UnmarshalJSON([]byte(`{"ImageWidth": 4032, "Orientation": "Rotate 90 CW", "ImageWidth": 512}`)))
+	assert.Equal(t, []interface{}{float64(4032), float64(512)}, g.GetAll("ImageWidth"))
+	assert.Equal(t, []interface{}{"Rotate 90 CW"}, g.GetAll("Orientation"))
+	assert.Nil(t, g.GetAll("unexisting"))
+
+	w, err := g.Index().GetInt("ImageWidth")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4032), w)
+	assert.Equal(t, g.GetAll("ImageWidth"), g.Index().GetAll("ImageWidth"))
+
+	fm := FileMetadata{Groups: map[string]FileMetadataValues{
+		"EXIF:IFD0": {{"Orientation", "Horizontal (normal)"}},
+		"EXIF:IFD1": {{"Orientation", "Rotate 90 CW"}, {"Orientation", "Rotate 180"}},
+		"File":      {{"FileName", "a.jpg"}},
+	}}
+	assert.Equal(t, []TagValue{
+		{"EXIF:IFD0", "Horizontal (normal)"},
+		{"EXIF:IFD1", "Rotate 90 CW"},
+		{"EXIF:IFD1", "Rotate 180"},
+	}, fm.GetAll("Orientation"))
+	assert.Equal(t, []TagValue{{"EXIF:IFD1", "Rotate 90 CW"}, {"EXIF:IFD1", "Rotate 180"}}, fm.GetAll("IFD1:Orientation"))
+	assert.Equal(t, 3, len(fm.GetAll("EXIF:Orientation")))
+	assert.Nil(t, fm.GetAll("File:Orientation"))
+}
+
+func TestNewExifTool_WithAllowDuplicates(t *testing.T) {
+	e, err := NewExiftool(AllowDuplicates(), GroupFamily(1))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	metas := e.ExtractMetadata("./testdata/20190404_131804.jpg")
+	assert.Equal(t, 1, len(metas))
+	assert.Nil(t, metas[0].Err)
+
+	var groups []string
+	for _, tv := range metas[0].GetAll("XResolution") {
+		groups = append(groups, tv.Group)
+	}
+	assert.Equal(t, []string{"IFD0", "IFD1"}, groups)
+}
@@ -0,0 +1,143 @@
+package exiftool
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// FileNotFoundError is the error used when a file to process does not exist. It matches
+// ErrNotExist and os.ErrNotExist with errors.Is.
+type FileNotFoundError struct {
+	File string
+}
+
+func (e *FileNotFoundError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrNotExist, e.File)
+}
+
+// Is reports whether target is ErrNotExist or os.ErrNotExist
+func (e *FileNotFoundError) Is(target error) bool {
+	return target == ErrNotExist || target == os.ErrNotExist
+}
+
+// UnsupportedFormatError is the error used when exiftool can't process a file because
+// of its format. Message is the error reported by exiftool.
+type UnsupportedFormatError struct {
+	File    string
+	Message string
+}
+
+func (e *UnsupportedFormatError) Error() string {
+	return fmt.Sprintf("unsupported format (%v): %v", e.Message, e.File)
+}
+
+// ExiftoolMinorWarning is the error used when exiftool refused to process a file because
+// of a minor problem (ie. a bad MakerNotes block), that can be ignored with exiftool's
+// '-m' parameter. Message is the warning reported by exiftool, without the "[minor]"
+// prefix.
+type ExiftoolMinorWarning struct {
+	File    string
+	Message string
+}
+
+func (e *ExiftoolMinorWarning) Error() string {
+	return fmt.Sprintf("exiftool minor warning (%v): %v", e.Message, e.File)
+}
+
+// WarningError is the error used in strict mode (see StrictMode) when exiftool reported
+// warnings for a file. Warnings are the ones of FileMetadata.Warnings.
+type WarningError struct {
+	File     string
+	Warnings []string
+}
+
+func (e *WarningError) Error() string {
+	return fmt.Sprintf("exiftool warning (%v): %v", strings.Join(e.Warnings, "; "), e.File)
+}
+
+// ProcessCrashedError is the error used when the exiftool process terminated while
+// processing a command. Err is the error raised by the process termination, if any.
+type ProcessCrashedError struct {
+	Err error
+}
+
+func (e *ProcessCrashedError) Error() string {
+	if e.Err == nil {
+		return "exiftool process crashed"
+	}
+	return fmt.Sprintf("exiftool process crashed: %v", e.Err)
+}
+
+// Unwrap returns the error raised by the process termination
+func (e *ProcessCrashedError) Unwrap() error {
+	return e.Err
+}
+
+// FileTimeoutError is the error used when the extraction of a file took longer than the
+// timeout set with FileTimeout or WithTimeout. It matches context.DeadlineExceeded with
+// errors.Is.
+type FileTimeoutError struct {
+	File    string
+	Timeout time.Duration
+}
+
+func (e *FileTimeoutError) Error() string {
+	return fmt.Sprintf("extraction timed out after %v: %v", e.Timeout, e.File)
+}
+
+// Is reports whether target is context.DeadlineExceeded
+func (e *FileTimeoutError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
+
+// UnwritableTagError is the error used when a tag to write is unknown or can't be written
+// by exiftool, see ValidateWrites
+type UnwritableTagError struct {
+	Tag string
+}
+
+func (e *UnwritableTagError) Error() string {
+	return fmt.Sprintf("unknown or unwritable tag: %v", e.Tag)
+}
+
+// FileUnchangedError is the error used when exiftool reported that a write command left
+// the file unchanged (ie. when deleting a tag that doesn't exist). Message is what
+// exiftool printed.
+type FileUnchangedError struct {
+	File    string
+	Message string
+}
+
+func (e *FileUnchangedError) Error() string {
+	return fmt.Sprintf("file unchanged (%v): %v", e.Message, e.File)
+}
+
+const minorPrefix = "[minor] "
+
+var unsupportedFormatMessages = []string{
+	"Unknown file type",
+	"Unsupported file type",
+	"File format error",
+}
+
+// exiftoolError converts an error message reported by exiftool for file (without the
+// "Error: " prefix) into the matching typed error, or returns nil if there is none
+func exiftoolError(file, msg string) error {
+	switch {
+	case strings.HasPrefix(msg, minorPrefix):
+		return &ExiftoolMinorWarning{File: file, Message: strings.TrimPrefix(msg, minorPrefix)}
+	case strings.HasPrefix(msg, "File not found"):
+		return &FileNotFoundError{File: file}
+	}
+
+	for _, m := range unsupportedFormatMessages {
+		if strings.HasPrefix(msg, m) {
+			return &UnsupportedFormatError{File: file, Message: msg}
+		}
+	}
+
+	return nil
+}
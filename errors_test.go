@@ -0,0 +1,104 @@
+package exiftool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileNotFoundError(t *testing.T) {
+	var err error = &FileNotFoundError{File: "a.jpg"}
+	assert.True(t, errors.Is(err, ErrNotExist))
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	assert.True(t, errors.Is(fmt.Errorf("wrapped: %w", err), ErrNotExist))
+
+	var fnf *FileNotFoundError
+	assert.True(t, errors.As(err, &fnf))
+	assert.Equal(t, "a.jpg", fnf.File)
+}
+
+func TestProcessCrashedError(t *testing.T) {
+	cause := errors.New("signal: killed")
+	var err error = &ProcessCrashedError{Err: cause}
+	assert.True(t, errors.Is(err, cause))
+	assert.NotEmpty(t, err.Error())
+	assert.NotEmpty(t, (&ProcessCrashedError{}).Error())
+}
+
+func TestFileTimeoutError(t *testing.T) {
+	var err error = &FileTimeoutError{File: "a.jpg", Timeout: time.Second}
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.False(t, errors.Is(err, context.Canceled))
+	assert.Contains(t, err.Error(), "a.jpg")
+}
+
+func TestWarningError(t *testing.T) {
+	err := &WarningError{File: "a.jpg", Warnings: []string{"w1", "w2"}}
+	assert.Equal(t, "exiftool warning (w1; w2): a.jpg", err.Error())
+}
+
+func TestExiftoolError(t *testing.T) {
+	var tcs = []struct {
+		tcID    string
+		in      string
+		expType interface{}
+	}{
+		{"minor", "[minor] Bad MakerNotes directory", &ExiftoolMinorWarning{}},
+		{"notFound", "File not found", &FileNotFoundError{}},
+		{"unknown", "Unknown file type", &UnsupportedFormatError{}},
+		{"unsupported", "Unsupported file type", &UnsupportedFormatError{}},
+		{"formatError", "File format error", &UnsupportedFormatError{}},
+		{"other", "File is empty", nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			err := exiftoolError("a.jpg", tc.in)
+			if tc.expType == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.IsType(t, tc.expType, err)
+			}
+		})
+	}
+}
+
+func TestFileMetadataExiftoolError(t *testing.T) {
+	fm := FileMetadata{File: "a.txt", Groups: map[string]FileMetadataValues{
+		"ExifTool": {{"Error", "Unknown file type"}},
+	}}
+	var u *UnsupportedFormatError
+	assert.True(t, errors.As(fm.exiftoolError(), &u))
+	assert.Equal(t, "a.txt", u.File)
+
+	fm = FileMetadata{Groups: map[string]FileMetadataValues{
+		"ExifTool": {{"Error", "File is empty"}},
+	}}
+	assert.Nil(t, fm.exiftoolError())
+	assert.Nil(t, FileMetadata{}.exiftoolError())
+}
+
+func TestExtractCrashedProcess(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	assert.Nil(t, e.cmd.Process.Kill())
+	<-e.exited
+
+	fms := e.ExtractMetadata("./testdata/20190404_131804.jpg")
+	assert.Equal(t, 1, len(fms))
+	var pc *ProcessCrashedError
+	assert.True(t, errors.As(fms[0].Err, &pc))
+}
+
+func TestWriteErrors(t *testing.T) {
+	assert.Contains(t, (&UnwritableTagError{Tag: "FileSize"}).Error(), "FileSize")
+	assert.Contains(t, (&FileUnchangedError{File: "a.jpg", Message: "0 image files updated"}).Error(), "a.jpg")
+}
@@ -3,17 +3,21 @@ package exiftool
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"errors"
 )
 
-var binary = "exiftool"
 var executeArg = "-execute"
 var initArgs = []string{"-stay_open", "True", "-@", "-", "-common_args"}
 var extractArgs = []string{"-j", "-g"}
@@ -23,7 +27,20 @@ var readyTokenLen = len(readyToken)
 // ErrNotExist is a sentinel error for non existing file
 var ErrNotExist = errors.New("file does not exist")
 
-// Exiftool is the exiftool utility wrapper
+// ErrClosed is a sentinel error used when a command is requested on a closed Exiftool
+var ErrClosed = errors.New("exiftool is closed")
+
+// DecoderFunc decodes raw, the JSON object printed by exiftool for fm.File, into fm
+// (usually into fm.Groups)
+type DecoderFunc func(raw json.RawMessage, fm *FileMetadata) error
+
+// Option is a configuration function applied by NewExiftool (and NewPool on each of its
+// workers)
+type Option func(*Exiftool) error
+
+// Exiftool is the exiftool utility wrapper. It is safe for concurrent use by multiple
+// goroutines: the commands sent to the underlying exiftool process are serialized, each
+// call waiting for the running one to complete. Use a Pool to run them in parallel.
 type Exiftool struct {
 	lock          sync.Mutex
 	stdin         io.WriteCloser
@@ -33,12 +50,60 @@ type Exiftool struct {
 	buffer        []byte
 	bufferMaxSize int
 	extraInitArgs []string
+	exited        chan struct{}
+	cmd           *exec.Cmd
+	binaryPath    string
+	perlPath      string
+	groupFamilies []int
+	closed        bool
+	restartTries  int
+	restartDelay  time.Duration
+	keepRaw       bool
+	decoder       DecoderFunc
+	fast          int
+	fileTimeout   time.Duration
+	logger        Logger
+	metrics       Metrics
+	configArgs    []string
+	validUTF8     bool
+	cache         Cache
+	validate      bool
+	writable      map[string]bool
+	documents     bool
+	dateLayout    string
+	dryRun        *CommandRecorder
+	policy        WritePolicy
+	nice          *int
+	ulimits       []string
+	maxOutput     int64
+	spillAt       int64
+	spillDir      string
+	lastChunk     bool
+	retry         *RetryPolicy
+	dedup         bool
+	dedups        int64
+	argFileAt     int
+	argFiles      bool
+	strict        bool
+	tagDB         *TagDatabase
+	fsys          FileSystem
+	draining      int32
+	process       atomic.Value
+	langAlt       bool
+	mwg           bool
+	transformers  []valueTransformer
+	counters      counters
+	stripped      []tagMatcher
+	hashes        []HashAlgorithm
 }
 
 // NewExiftool instanciates a new Exiftool with configuration functions. If anything went
 // wrong, a non empty error will be returned.
-func NewExiftool(opts ...func(*Exiftool) error) (*Exiftool, error) {
-	e := Exiftool{}
+func NewExiftool(opts ...Option) (*Exiftool, error) {
+	e := Exiftool{
+		binaryPath:    lookupBinary(),
+		extraInitArgs: append([]string{}, platformInitArgs...),
+	}
 
 	for _, opt := range opts {
 		if err := opt(&e); err != nil {
@@ -46,8 +111,45 @@ func NewExiftool(opts ...func(*Exiftool) error) (*Exiftool, error) {
 		}
 	}
 
-	args := append(initArgs, e.extraInitArgs...)
-	cmd := exec.Command(binary, args...)
+	if err := e.start(); err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+// lookupBinary returns the path of the first exiftool binary found in the PATH, or the
+// default binary name if none can be found
+func lookupBinary() string {
+	for _, b := range binaries {
+		if p, err := exec.LookPath(b); err == nil {
+			return p
+		}
+	}
+	return binaries[0]
+}
+
+// command returns the command running the exiftool binary (through the Perl interpreter
+// if any) with args, preceded by the -config parameter if needed since exiftool requires
+// it to come first
+func (e *Exiftool) command(args ...string) *exec.Cmd {
+	if e.configArgs != nil {
+		args = append(append([]string{}, e.configArgs...), args...)
+	}
+	name := e.binaryPath
+	if e.perlPath != "" {
+		name, args = e.perlPath, append([]string{e.binaryPath}, args...)
+	}
+	cmd := e.limitCommand(exec.Command(name, args...))
+	configureCommand(cmd)
+	return cmd
+}
+
+// start starts the exiftool process
+func (e *Exiftool) start() error {
+	args := append([]string{}, initArgs...)
+	args = append(args, e.extraInitArgs...)
+	cmd := e.command(args...)
 	r, w := io.Pipe()
 	e.stdMergedOut = r
 
@@ -56,20 +158,106 @@ func NewExiftool(opts ...func(*Exiftool) error) (*Exiftool, error) {
 
 	var err error
 	if e.stdin, err = cmd.StdinPipe(); err != nil {
-		return nil, fmt.Errorf("error when piping stdin: %w", err)
+		return fmt.Errorf("error when piping stdin: %w", err)
 	}
 
 	e.scanMergedOut = bufio.NewScanner(r)
-	if e.bufferSet {
-		e.scanMergedOut.Buffer(e.buffer, e.bufferMaxSize)
+	if e.chunked() {
+		e.chunkedScanner(e.scanMergedOut)
+	} else {
+		if e.bufferSet {
+			e.scanMergedOut.Buffer(e.buffer, e.bufferMaxSize)
+		}
+		e.scanMergedOut.Split(splitReadyToken)
 	}
-	e.scanMergedOut.Split(splitReadyToken)
 
-	if err = cmd.Start(); err != nil {
-		return nil, fmt.Errorf("error when executing commande: %w", err)
+	if err = e.startCommand(cmd); err != nil {
+		return fmt.Errorf("error when executing commande: %w", err)
 	}
 
-	return &e, nil
+	exited := make(chan struct{})
+	e.cmd = cmd
+	e.exited = exited
+	e.process.Store(process{cmd: cmd, exited: exited, startedAt: time.Now()})
+	go func() {
+		err := cmd.Wait()
+		if err == nil {
+			err = io.EOF
+		}
+		close(exited)
+		w.CloseWithError(err)
+	}()
+
+	return nil
+}
+
+// restart starts a new exiftool process if the current one terminated and AutoRestart
+// is enabled. The caller must hold e.lock.
+func (e *Exiftool) restart() error {
+	if e.alive() || e.restartTries < 1 || e.stopping() {
+		return nil
+	}
+
+	return e.respawn(e.restartTries)
+}
+
+// respawn starts a new exiftool process, trying at most tries times, once the current
+// one terminated. The caller must hold e.lock.
+func (e *Exiftool) respawn(tries int) error {
+	e.stdin.Close()
+	e.stdMergedOut.Close()
+
+	var err error
+	for i := 0; i < tries; i++ {
+		if i > 0 {
+			time.Sleep(e.restartDelay)
+		}
+		err = e.start()
+		e.log("restart", "attempt", i+1, "err", err)
+		if err == nil {
+			atomic.AddInt64(&e.counters.restarts, 1)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("error while restarting exiftool: %w", err)
+}
+
+// Healthy returns true if the exiftool process is running
+func (e *Exiftool) Healthy() bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	return e.alive() && !e.stopping()
+}
+
+// Ping checks that the exiftool process answers to commands, restarting it beforehand
+// if it terminated and AutoRestart is enabled. If anything went wrong, a non empty error
+// will be returned.
+func (e *Exiftool) Ping() error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	out, err := e.execute("-ver")
+	if err != nil {
+		return err
+	}
+
+	if _, err := strconv.ParseFloat(strings.TrimSpace(string(out)), 64); err != nil {
+		return fmt.Errorf("unexpected answer to ping (%v)", string(out))
+	}
+
+	return nil
+}
+
+// alive returns false once the underlying exiftool process has terminated
+func (e *Exiftool) alive() bool {
+	select {
+	case <-e.exited:
+		return false
+	default:
+		return true
+	}
 }
 
 // Close closes exiftool. If anything went wrong, a non empty error will be returned
@@ -77,6 +265,8 @@ func (e *Exiftool) Close() error {
 	e.lock.Lock()
 	defer e.lock.Unlock()
 
+	e.closed = true
+
 	for _, v := range closeArgs {
 		_, err := fmt.Fprintln(e.stdin, v)
 		if err != nil {
@@ -102,59 +292,327 @@ func (e *Exiftool) Close() error {
 
 // ExtractMetadata extracts metadata from files
 func (e *Exiftool) ExtractMetadata(files ...string) []FileMetadata {
-	e.lock.Lock()
-	defer e.lock.Unlock()
+	return e.ExtractMetadataContext(context.Background(), files...)
+}
 
-	fms := make([]FileMetadata, len(files))
+// ExtractMetadataContext extracts metadata from files, aborting when ctx is done: files
+// that have not been extracted yet get ctx.Err() as error. If ctx is done while exiftool
+// is processing a file, the exiftool process is killed and the Exiftool can not be used
+// anymore (Pool restarts it transparently).
+func (e *Exiftool) ExtractMetadataContext(ctx context.Context, files ...string) []FileMetadata {
+	return e.ExtractContext(ctx, files)
+}
 
-	for i, f := range files {
-		fms[i].File = f
+// decode fills fm from the JSON output of the extraction of fm.File, messages being
+// what exiftool printed on stderr
+func (e *Exiftool) decode(fm *FileMetadata, out []byte, messages []string) {
+	fm.GroupFamilies = e.families()
 
-		if _, err := os.Stat(f); err != nil {
-			if os.IsNotExist(err) {
-				fms[i].Err = ErrNotExist
-				continue
-			}
+	var raws []json.RawMessage
+	if err := json.Unmarshal(out, &raws); err != nil {
+		fm.Err = fmt.Errorf("error during unmarshaling (%v): %w)", string(out), err)
+		e.decodeFailed(fm)
+		return
+	}
+	if len(raws) == 0 {
+		fm.Err = fmt.Errorf("no metadata in output (%v)", string(out))
+		e.decodeFailed(fm)
+		return
+	}
 
-			fms[i].Err = err
+	e.decodeRaw(fm, raws[0], messages)
+}
+
+// decodeRaw fills fm from raw, the JSON object printed by exiftool for fm.File,
+// messages being what exiftool printed on stderr
+func (e *Exiftool) decodeRaw(fm *FileMetadata, raw json.RawMessage, messages []string) {
+	fm.GroupFamilies = e.families()
+	fm.NumericValues = e.numeric()
+	fm.DateLayout = e.dateLayout
+	atomic.AddInt64(&e.counters.decodedBytes, int64(len(raw)))
+
+	if e.keepRaw {
+		fm.Raw = raw
+	}
+
+	d := e.decoder
+	if d == nil {
+		d = decodeGroups
+	}
+	if err := d(raw, fm); err != nil {
+		fm.Err = err
+		e.decodeFailed(fm)
+		return
+	}
+
+	if e.validUTF8 {
+		for _, g := range fm.Groups {
+			toValidUTF8(g)
+		}
+	}
+	if len(e.stripped) > 0 {
+		e.stripTags(fm)
+	}
+	if len(e.transformers) > 0 {
+		if err := e.transformValues(fm); err != nil {
+			fm.Err = err
+			e.decodeFailed(fm)
+			return
+		}
+	}
+	if e.langAlt {
+		for n, g := range fm.Groups {
+			fm.Groups[n] = mergeLangAlt(g)
+		}
+	}
 
+	fm.Warnings = fm.collectWarnings(messages)
+	fm.Err = fm.exiftoolError()
+	if fm.Err == nil && e.strict && len(fm.Warnings) > 0 {
+		fm.Err = &WarningError{File: fm.File, Warnings: fm.Warnings}
+	}
+}
+
+// decodeGroups is the default DecoderFunc
+func decodeGroups(raw json.RawMessage, fm *FileMetadata) error {
+	var grps map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &grps); err != nil {
+		return fmt.Errorf("error during unmarshaling (%v): %w)", string(raw), err)
+	}
+
+	fm.Groups = map[string]FileMetadataValues{}
+	for n, gf := range grps {
+		var gv FileMetadataValues
+		if err := json.Unmarshal(gf, &gv); err != nil {
+			//fms[i].Err = fmt.Errorf("unmarshal(%v) failed: %w", string(gf), err)
 			continue
 		}
+		fm.Groups[n] = gv
+	}
 
-		for _, curA := range extractArgs {
-			fmt.Fprintln(e.stdin, curA)
+	keys, _ := objectKeys(raw)
+	fm.GroupOrder = nil
+	for _, k := range keys {
+		if _, found := fm.Groups[k]; found {
+			fm.GroupOrder = append(fm.GroupOrder, k)
 		}
+	}
 
-		fmt.Fprintln(e.stdin, f)
-		fmt.Fprintln(e.stdin, executeArg)
+	return nil
+}
 
-		if !e.scanMergedOut.Scan() {
-			fms[i].Err = fmt.Errorf("nothing on stdMergedOut")
-			continue
+// splitMessages splits the merged output of an extraction into the JSON array printed
+// on stdout and the non empty lines printed on stderr before or after it
+func splitMessages(out []byte) ([]byte, []string) {
+	start := 0
+	if !bytes.HasPrefix(out, []byte("[")) {
+		start = bytes.Index(out, []byte("\n["))
+		if start == -1 {
+			return out, nil
 		}
+		start++
+	}
+	end := bytes.LastIndex(out, []byte("}]")) + 2
+	if end < start+2 {
+		end = start + bytes.IndexByte(out[start:], ']') + 1
+	}
 
-		if e.scanMergedOut.Err() != nil {
-			fms[i].Err = fmt.Errorf("error while reading stdMergedOut: %w", e.scanMergedOut.Err())
-			continue
+	var messages []string
+	for _, b := range [][]byte{out[:start], out[end:]} {
+		for _, l := range strings.Split(string(b), "\n") {
+			if l = strings.TrimSpace(l); l != "" {
+				messages = append(messages, l)
+			}
 		}
+	}
 
-		var grps []map[string]json.RawMessage
-		if err := json.Unmarshal(e.scanMergedOut.Bytes(), &grps); err != nil {
-			fms[i].Err = fmt.Errorf("error during unmarshaling (%v): %w)", string(e.scanMergedOut.Bytes()), err)
-			continue
+	return out[start:end], messages
+}
+
+// ExtractReader extracts metadata from the content read from r, which is piped to a
+// dedicated exiftool process (exiftool -fast -, unless a higher level is set with Fast)
+// since the stay_open process already reads its commands from stdin. hintFilename is
+// only used to fill FileMetadata.File.
+func (e *Exiftool) ExtractReader(r io.Reader, hintFilename string) FileMetadata {
+	fm := FileMetadata{File: hintFilename}
+
+	cfg := e.extractConfig()
+	if cfg.fast == 0 {
+		cfg.fast = 1
+	}
+
+	args := append([]string{}, e.extraInitArgs...)
+	args = append(args, e.extractArgs(cfg)...)
+	args = append(args, "-")
+
+	var stdout, stderr bytes.Buffer
+	cmd := e.command(args...)
+	cmd.Stdin = r
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := e.startCommand(cmd)
+	if err == nil {
+		err = cmd.Wait()
+	}
+	if err != nil {
+		fm.Err = fmt.Errorf("error when executing command (%v): %w", strings.TrimSpace(stderr.String()), err)
+		return fm
+	}
+
+	_, messages := splitMessages(stderr.Bytes())
+	e.decode(&fm, stdout.Bytes(), messages)
+
+	return fm
+}
+
+// extractArgs returns the arguments of an extraction command configured by cfg, files
+// excluded
+func (e *Exiftool) extractArgs(cfg extractConfig) []string {
+	args := append([]string{}, extractArgs...)
+	if len(e.groupFamilies) > 0 || e.documents {
+		fams := e.families()
+		fs := make([]string, len(fams))
+		for i, f := range fams {
+			fs[i] = strconv.Itoa(f)
+		}
+		args[len(args)-1] += strings.Join(fs, ":")
+	}
+	if cfg.fast > 0 {
+		args = append(args, fastArg(cfg.fast))
+	}
+	for _, t := range cfg.tags {
+		args = append(args, "-"+t)
+	}
+	return append(args, conditionArgs(cfg.conditions)...)
+}
+
+// numeric returns true if values are extracted without print conversion
+func (e *Exiftool) numeric() bool {
+	for _, a := range e.extraInitArgs {
+		if a == "-n" || a == "--printConv" {
+			return true
 		}
-		fms[i].Groups = map[string]FileMetadataValues{}
-		for n, gf := range grps[0] {
-			var gv FileMetadataValues
-			if err := json.Unmarshal(gf, &gv); err != nil {
-				//fms[i].Err = fmt.Errorf("unmarshal(%v) failed: %w", string(gf), err)
-				continue
+	}
+	return false
+}
+
+// families returns the group families used to group extracted metadata
+func (e *Exiftool) families() []int {
+	fams := []int{0}
+	if len(e.groupFamilies) > 0 {
+		fams = append([]int{}, e.groupFamilies...)
+	}
+	if e.documents && !containsInt(fams, documentFamily) {
+		fams = append([]int{documentFamily}, fams...)
+	}
+	return fams
+}
+
+func containsInt(is []int, i int) bool {
+	for _, v := range is {
+		if v == i {
+			return true
+		}
+	}
+	return false
+}
+
+// execute sends args to exiftool followed by the execute token and returns what exiftool
+// printed until it was ready again. The caller must hold e.lock.
+func (e *Exiftool) execute(args ...string) ([]byte, error) {
+	start := time.Now()
+	out, err := e.send(args...)
+	d := time.Since(start)
+	e.observeCommand(d)
+	e.log("command", "args", args, "duration", d, "err", err)
+	return out, err
+}
+
+// send writes args and the -execute argument to the exiftool process and returns its
+// answer. The caller must hold e.lock.
+func (e *Exiftool) send(args ...string) ([]byte, error) {
+	if e.stopping() {
+		return nil, ErrClosed
+	}
+	if err := e.restart(); err != nil {
+		return nil, err
+	}
+
+	if e.useArgFile(args) {
+		f, err := writeArgFile(e.fileSystem(), args)
+		if err != nil {
+			return nil, err
+		}
+		defer e.fileSystem().Remove(f)
+		args = []string{"-@", f}
+	}
+
+	for _, a := range args {
+		if _, err := fmt.Fprintln(e.stdin, a); err != nil {
+			return nil, e.crashed(fmt.Errorf("error while writing to stdin: %w", err))
+		}
+	}
+
+	if _, err := fmt.Fprintln(e.stdin, executeArg); err != nil {
+		return nil, e.crashed(fmt.Errorf("error while writing to stdin: %w", err))
+	}
+
+	if e.chunked() {
+		return e.readChunks()
+	}
+
+	if !e.scanMergedOut.Scan() {
+		if err := e.scanMergedOut.Err(); err != nil {
+			return nil, e.crashed(fmt.Errorf("error while reading stdMergedOut: %w", err))
+		}
+		return nil, e.crashed(fmt.Errorf("nothing on stdMergedOut"))
+	}
+
+	return e.scanMergedOut.Bytes(), nil
+}
+
+// crashed wraps err into a ProcessCrashedError if the exiftool process terminated
+func (e *Exiftool) crashed(err error) error {
+	if e.alive() {
+		return err
+	}
+	return &ProcessCrashedError{Err: err}
+}
+
+// executeContext behaves like execute but kills the exiftool process if ctx is done
+// before exiftool answered, in which case ctx.Err() is returned. The caller must hold
+// e.lock.
+func (e *Exiftool) executeContext(ctx context.Context, args ...string) ([]byte, error) {
+	if ctx.Done() == nil || e.cmd == nil {
+		return e.execute(args...)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	stop := make(chan struct{})
+	killed := make(chan bool)
+	go func() {
+		select {
+		case <-ctx.Done():
+			// e.cmd may be replaced meanwhile by a restart, the running process is loaded
+			if p, ok := e.process.Load().(process); ok && p.cmd != nil && p.cmd.Process != nil {
+				p.cmd.Process.Kill()
 			}
-			fms[i].Groups[n] = gv
+			killed <- true
+		case <-stop:
+			killed <- false
 		}
+	}()
+
+	out, err := e.execute(args...)
+	close(stop)
+	if <-killed {
+		return nil, ctx.Err()
 	}
 
-	return fms
+	return out, err
 }
 
 func splitReadyToken(data []byte, atEOF bool) (int, []byte, error) {
@@ -174,7 +632,7 @@ func splitReadyToken(data []byte, atEOF bool) (int, []byte, error) {
 // Sample :
 //  buf := make([]byte, 128*1000)
 //  e, err := NewExiftool(Buffer(buf, 64*1000))
-func Buffer(buf []byte, max int) func(*Exiftool) error {
+func Buffer(buf []byte, max int) Option {
 	return func(e *Exiftool) error {
 		e.bufferSet = true
 		e.buffer = buf
@@ -186,7 +644,7 @@ func Buffer(buf []byte, max int) func(*Exiftool) error {
 // Charset defines the -charset value to pass to Exiftool, see https://exiftool.org/faq.html#Q10 and https://exiftool.org/faq.html#Q18
 // Sample :
 //   e, err := NewExiftool(Charset("filename=utf8"))
-func Charset(charset string) func(*Exiftool) error {
+func Charset(charset string) Option {
 	return func(e *Exiftool) error {
 		e.extraInitArgs = append(e.extraInitArgs, "-charset", charset)
 		return nil
@@ -196,19 +654,214 @@ func Charset(charset string) func(*Exiftool) error {
 // NoPrintConversion enables 'No print conversion' mode, see https://exiftool.org/exiftool_pod.html.
 // Sample :
 //   e, err := NewExiftool(NoPrintConversion())
-func NoPrintConversion() func(*Exiftool) error {
+func NoPrintConversion() Option {
 	return func(e *Exiftool) error {
 		e.extraInitArgs = append(e.extraInitArgs, "-n")
 		return nil
 	}
 }
 
+// Struct extracts structured information as nested values instead of flattened tags
+// (activates Exiftool's '-struct' parameter), see FileMetadataValues.GetStruct
+// Sample :
+//   e, err := NewExiftool(Struct())
+func Struct() Option {
+	return func(e *Exiftool) error {
+		e.extraInitArgs = append(e.extraInitArgs, "-struct")
+		return nil
+	}
+}
+
 // ExtractEmbedded extracts embedded metadata from files (activates Exiftool's '-ee' paramater)
 // Sample :
 //   e, err := NewExiftool(ExtractEmbedded())
-func ExtractEmbedded() func(*Exiftool) error {
+func ExtractEmbedded() Option {
 	return func(e *Exiftool) error {
 		e.extraInitArgs = append(e.extraInitArgs, "-ee")
 		return nil
 	}
 }
+
+// SetExiftoolBinaryPath sets the path of the exiftool binary to use instead of looking for
+// "exiftool" in the PATH
+// Sample :
+//   e, err := NewExiftool(SetExiftoolBinaryPath("/usr/local/bin/exiftool"))
+func SetExiftoolBinaryPath(p string) Option {
+	return func(e *Exiftool) error {
+		if _, err := os.Stat(p); err != nil {
+			return fmt.Errorf("error while checking if path '%v' exists: %w", p, err)
+		}
+		e.binaryPath = p
+		return nil
+	}
+}
+
+// SetPerlInterpreter makes the exiftool binary (then the exiftool Perl script, ie. the
+// one of the Image-ExifTool distribution) be run by the Perl interpreter p instead of
+// relying on its shebang
+// Sample :
+//   e, err := NewExiftool(SetExiftoolBinaryPath("/opt/Image-ExifTool/exiftool"), SetPerlInterpreter("/usr/bin/perl"))
+func SetPerlInterpreter(p string) Option {
+	return func(e *Exiftool) error {
+		if _, err := os.Stat(p); err != nil {
+			return fmt.Errorf("error while checking if path '%v' exists: %w", p, err)
+		}
+		e.perlPath = p
+		return nil
+	}
+}
+
+// API sets an exiftool API option (activates Exiftool's '-api' parameter), see
+// https://exiftool.org/exiftool_pod.html#api-OPT-VAL. An empty value only passes the key.
+// Sample :
+//   e, err := NewExiftool(API("LargeFileSupport", "1"))
+func API(key, value string) Option {
+	return func(e *Exiftool) error {
+		if key == "" {
+			return fmt.Errorf("empty api option key")
+		}
+		opt := key
+		if value != "" {
+			opt += "=" + value
+		}
+		e.extraInitArgs = append(e.extraInitArgs, "-api", opt)
+		return nil
+	}
+}
+
+// ConfigFile makes exiftool load the configuration file p instead of ~/.ExifTool_config
+// (activates Exiftool's '-config' parameter), so that the user defined tags and composite
+// tags it declares are extracted, see https://exiftool.org/config.html and
+// WriteTempConfig. An empty p disables the loading of any configuration file.
+// Sample :
+//   e, err := NewExiftool(ConfigFile("/etc/exiftool/custom.config"))
+func ConfigFile(p string) Option {
+	return func(e *Exiftool) error {
+		if p != "" {
+			if _, err := os.Stat(p); err != nil {
+				return fmt.Errorf("error while checking if path '%v' exists: %w", p, err)
+			}
+		}
+		e.configArgs = []string{"-config", p}
+		return nil
+	}
+}
+
+// ExtraInitArgs appends raw arguments to the common arguments passed to exiftool for every
+// command, for the parameters that have no dedicated option
+// Sample :
+//   e, err := NewExiftool(ExtraInitArgs("-m", "-q"))
+func ExtraInitArgs(args ...string) Option {
+	return func(e *Exiftool) error {
+		e.extraInitArgs = append(e.extraInitArgs, args...)
+		return nil
+	}
+}
+
+// GroupFamily defines the exiftool group families used to group metadata (activates
+// Exiftool's '-g' parameter with families, see https://exiftool.org/exiftool_pod.html#g-NUM-:NUM...).
+// When several families are provided, group keys combine them (ie. "EXIF:IFD0" for
+// families 0 and 1), see FileMetadata.GroupComponents.
+// Sample :
+//   e, err := NewExiftool(GroupFamily(0, 1))
+func GroupFamily(families ...int) Option {
+	return func(e *Exiftool) error {
+		if len(families) == 0 {
+			return fmt.Errorf("no group family provided")
+		}
+		for _, f := range families {
+			if f < 0 || f > 7 {
+				return fmt.Errorf("invalid group family (%v)", f)
+			}
+		}
+		e.groupFamilies = append([]int{}, families...)
+		return nil
+	}
+}
+
+// AutoRestart transparently restarts the exiftool process before the next command when
+// it terminated (killed, crashed, ...). Up to attempts restarts are tried, waiting delay
+// between each of them. The command being processed when the process terminated is not
+// replayed.
+// Sample :
+//   e, err := NewExiftool(AutoRestart(3, time.Second))
+func AutoRestart(attempts int, delay time.Duration) Option {
+	return func(e *Exiftool) error {
+		if attempts < 1 {
+			return fmt.Errorf("invalid restart attempts (%v)", attempts)
+		}
+		if delay < 0 {
+			return fmt.Errorf("invalid restart delay (%v)", delay)
+		}
+		e.restartTries = attempts
+		e.restartDelay = delay
+		return nil
+	}
+}
+
+// KeepRawJSON keeps the JSON object printed by exiftool for each file in FileMetadata.Raw
+// Sample :
+//   e, err := NewExiftool(KeepRawJSON())
+func KeepRawJSON() Option {
+	return func(e *Exiftool) error {
+		e.keepRaw = true
+		return nil
+	}
+}
+
+// StrictMode makes the extractions fail with a WarningError for files exiftool reported
+// warnings for (see FileMetadata.Warnings), the metadata being extracted anyway
+// Sample :
+//   e, err := NewExiftool(StrictMode())
+func StrictMode() Option {
+	return func(e *Exiftool) error {
+		e.strict = true
+		return nil
+	}
+}
+
+// CustomDecoder replaces the decoder used to convert the JSON object printed by exiftool
+// for each file into FileMetadata.Groups
+// Sample :
+//   e, err := NewExiftool(CustomDecoder(func(raw json.RawMessage, fm *FileMetadata) error {
+//     ...
+//   }))
+func CustomDecoder(d DecoderFunc) Option {
+	return func(e *Exiftool) error {
+		if d == nil {
+			return fmt.Errorf("nil decoder")
+		}
+		e.decoder = d
+		return nil
+	}
+}
+
+// Fast makes every extraction skip the slow parts of the files (activates Exiftool's
+// '-fast', '-fast2', ... parameters according to level, see https://exiftool.org/exiftool_pod.html#fast-NUM).
+// Level 0 disables it. It can be overridden for a single extraction with WithFast.
+// Sample :
+//   e, err := NewExiftool(Fast(2))
+func Fast(level int) Option {
+	return func(e *Exiftool) error {
+		if err := checkFastLevel(level); err != nil {
+			return err
+		}
+		e.fast = level
+		return nil
+	}
+}
+
+// FileTimeout aborts the extraction of a file taking longer than d, which then gets a
+// FileTimeoutError while the following files are extracted by a new exiftool process.
+// It can be overridden for a single extraction with WithTimeout.
+// Sample :
+//   e, err := NewExiftool(FileTimeout(30 * time.Second))
+func FileTimeout(d time.Duration) Option {
+	return func(e *Exiftool) error {
+		if d < 0 {
+			return fmt.Errorf("invalid timeout (%v)", d)
+		}
+		e.fileTimeout = d
+		return nil
+	}
+}
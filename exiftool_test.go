@@ -2,9 +2,18 @@ package exiftool
 
 import (
 	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -97,6 +106,98 @@ func TestMultiExtract(t *testing.T) {
 	assert.Nil(t, f[0].Err)
 }
 
+func TestExtractMetadataContextCanceled(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nilf(t, err, "error not nil: %v", err)
+	defer e.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	fms := e.ExtractMetadataContext(ctx, "./testdata/20190404_131804.jpg", "./testdata/20190404_131804.jpg")
+	assert.Equal(t, 2, len(fms))
+	for _, fm := range fms {
+		assert.Equal(t, context.Canceled, fm.Err)
+	}
+
+	fms = e.ExtractMetadataContext(context.Background(), "./testdata/20190404_131804.jpg")
+	assert.Equal(t, 1, len(fms))
+	assert.Nil(t, fms[0].Err)
+}
+
+func TestExtractMetadataContextRestart(t *testing.T) {
+	e, err := NewExiftool(AutoRestart(1, 0))
+	assert.Nilf(t, err, "error not nil: %v", err)
+	defer e.Close()
+
+	// canceled while the process is being restarted
+	for i := 0; i < 20; i++ {
+		e.kill()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(i)*100*time.Microsecond)
+		e.ExtractMetadataContext(ctx, "./testdata/20190404_131804.jpg")
+		cancel()
+	}
+
+	e.kill()
+	fms := e.ExtractMetadataContext(context.Background(), "./testdata/20190404_131804.jpg")
+	assert.Equal(t, 1, len(fms))
+	assert.Nil(t, fms[0].Err)
+}
+
+func TestExtractMetadataContextDeadline(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nilf(t, err, "error not nil: %v", err)
+	defer e.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	fms := e.ExtractMetadataContext(ctx, "./testdata/20190404_131804.jpg")
+	assert.Equal(t, 1, len(fms))
+	assert.Nil(t, fms[0].Err)
+}
+
+func TestExtractReader(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nilf(t, err, "error not nil: %v", err)
+	defer e.Close()
+
+	f, err := os.Open("./testdata/20190404_131804.jpg")
+	assert.Nil(t, err)
+	defer f.Close()
+
+	fm := e.ExtractReader(f, "upload.jpg")
+	assert.Equal(t, "upload.jpg", fm.File)
+	assert.Nil(t, fm.Err)
+	mimeType, err := fm.Groups["File"].GetString("MIMEType")
+	assert.Nil(t, err)
+	assert.Equal(t, "image/jpeg", mimeType)
+}
+
+func TestSplitMessages(t *testing.T) {
+	var tcs = []struct {
+		tcID        string
+		in          string
+		expOut      string
+		expMessages []string
+	}{
+		{"jsonOnly", "[{\n  \"a\": 1\n}]\n", "[{\n  \"a\": 1\n}]", nil},
+		{"before", "Warning: Bad IFD0 directory - a.jpg\n[{\n}]\n", "[{\n}]", []string{"Warning: Bad IFD0 directory - a.jpg"}},
+		{"after", "[{\n}]\nWarning: [minor] Bad MakerNotes\r\n", "[{\n}]", []string{"Warning: [minor] Bad MakerNotes"}},
+		{"emptyArray", "Warning: w\n[]\n", "[]", []string{"Warning: w"}},
+		{"noJSON", "Error: e\n", "Error: e\n", nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			out, messages := splitMessages([]byte(tc.in))
+			assert.Equal(t, tc.expOut, string(out))
+			assert.Equal(t, tc.expMessages, messages)
+		})
+	}
+}
+
 func TestSplitReadyToken(t *testing.T) {
 	rt := string(readyToken)
 
@@ -251,6 +352,51 @@ func TestNoPrintConversion(t *testing.T) {
 		expProgram, err := meta.Groups["EXIF"].GetInt("ExposureProgram")
 		assert.Nil(t, err)
 		assert.Equal(t, int64(2), expProgram)
+		assert.True(t, meta.NumericValues)
+	}
+}
+
+func TestNumericValues(t *testing.T) {
+	var tcs = []struct {
+		tcID   string
+		inOpts []Option
+		exp    bool
+	}{
+		{"default", nil, false},
+		{"noPrintConversion", []Option{NoPrintConversion()}, true},
+		{"extraInitArgs", []Option{ExtraInitArgs("--printConv")}, true},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			e := Exiftool{}
+			for _, opt := range tc.inOpts {
+				assert.Nil(t, opt(&e))
+			}
+			var fm FileMetadata
+			e.decode(&fm, []byte(`[{"SourceFile":"a.jpg"}]`), nil)
+			assert.Equal(t, tc.exp, fm.NumericValues)
+		})
+	}
+}
+
+func TestExposureTimeInBothModes(t *testing.T) {
+	for _, opts := range [][]Option{nil, {NoPrintConversion()}} {
+		e, err := NewExiftool(opts...)
+		assert.Nil(t, err)
+
+		metas := e.ExtractMetadata("./testdata/20190404_131804.jpg")
+		assert.Equal(t, 1, len(metas))
+		assert.Nil(t, metas[0].Err)
+		et, err := metas[0].Groups["EXIF"].GetFloat("ExposureTime")
+		assert.Nil(t, err)
+		assert.True(t, et > 0 && et < 1, "unexpected exposure time %v", et)
+		fl, err := metas[0].Groups["EXIF"].GetFloat("FocalLength")
+		assert.Nil(t, err)
+		assert.InDelta(t, 4.2, fl, 1e-6)
+
+		assert.Nil(t, e.Close())
 	}
 }
 
@@ -275,3 +421,330 @@ func TestExtractEmbedded(t *testing.T) {
 	assert.Equal(t, "HERO4 Silver", osn)
 
 }
+
+func TestStruct(t *testing.T) {
+	e := Exiftool{}
+	assert.Nil(t, Struct()(&e))
+	assert.Equal(t, []string{"-struct"}, e.extraInitArgs)
+}
+
+func TestSetExiftoolBinaryPath(t *testing.T) {
+	e := Exiftool{}
+	assert.NotNil(t, SetExiftoolBinaryPath("./testdata/nonExisting")(&e))
+	assert.Equal(t, "", e.binaryPath)
+
+	assert.Nil(t, SetExiftoolBinaryPath("./testdata/empty.jpg")(&e))
+	assert.Equal(t, "./testdata/empty.jpg", e.binaryPath)
+}
+
+func TestSetPerlInterpreter(t *testing.T) {
+	e := Exiftool{binaryPath: "exiftool"}
+	assert.NotNil(t, SetPerlInterpreter("./testdata/nonExisting")(&e))
+	assert.Equal(t, "", e.perlPath)
+
+	assert.Nil(t, SetPerlInterpreter("./testdata/empty.jpg")(&e))
+	assert.Equal(t, []string{"./testdata/empty.jpg", "exiftool", "-ver"}, e.command("-ver").Args)
+	assert.Equal(t, []string{"./testdata/empty.jpg", "exiftool", "-ver"}, e.argv([]string{"-ver"}))
+}
+
+func TestConfigFile(t *testing.T) {
+	e := Exiftool{binaryPath: "exiftool"}
+	assert.Equal(t, []string{"exiftool", "-ver"}, e.command("-ver").Args)
+
+	assert.NotNil(t, ConfigFile("./testdata/nonExisting")(&e))
+	assert.Nil(t, ConfigFile("./testdata/empty.jpg")(&e))
+	assert.Equal(t, []string{"exiftool", "-config", "./testdata/empty.jpg", "-ver"}, e.command("-ver").Args)
+	assert.Nil(t, ConfigFile("")(&e))
+	assert.Equal(t, []string{"exiftool", "-config", "", "-ver"}, e.command("-ver").Args)
+}
+
+func TestLookupBinary(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-exiftool")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := os.Getenv("PATH")
+	defer os.Setenv("PATH", path)
+
+	assert.Nil(t, os.Setenv("PATH", dir))
+	assert.Equal(t, binaries[0], lookupBinary())
+
+	b := filepath.Join(dir, binaries[0])
+	assert.Nil(t, ioutil.WriteFile(b, []byte{}, 0755))
+	assert.Equal(t, b, lookupBinary())
+}
+
+func TestNewExifTool_WithBinaryPath(t *testing.T) {
+	p, err := exec.LookPath("exiftool")
+	assert.Nil(t, err)
+
+	e, err := NewExiftool(SetExiftoolBinaryPath(p))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	metas := e.ExtractMetadata("./testdata/20190404_131804.jpg")
+	assert.Equal(t, 1, len(metas))
+	assert.Nil(t, metas[0].Err)
+}
+
+func TestAPI(t *testing.T) {
+	var tcs = []struct {
+		tcID    string
+		inKey   string
+		inValue string
+		expOk   bool
+		expArgs []string
+	}{
+		{"keyValue", "LargeFileSupport", "1", true, []string{"-api", "LargeFileSupport=1"}},
+		{"keyOnly", "QuickTimeUTC", "", true, []string{"-api", "QuickTimeUTC"}},
+		{"emptyKey", "", "1", false, nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			e := Exiftool{}
+			err := API(tc.inKey, tc.inValue)(&e)
+			assert.Equal(t, tc.expOk, err == nil)
+			assert.Equal(t, tc.expArgs, e.extraInitArgs)
+		})
+	}
+}
+
+func TestExtraInitArgs(t *testing.T) {
+	e := Exiftool{}
+	assert.Nil(t, ExtraInitArgs("-m", "-q")(&e))
+	assert.Nil(t, ExtraInitArgs("-fast")(&e))
+	assert.Equal(t, []string{"-m", "-q", "-fast"}, e.extraInitArgs)
+}
+
+func TestGroupFamily(t *testing.T) {
+	var tcs = []struct {
+		tcID        string
+		inFamilies  []int
+		expOk       bool
+		expArgs     []string
+		expFamilies []int
+	}{
+		{"default", nil, true, []string{"-j", "-g"}, []int{0}},
+		{"mono", []int{1}, true, []string{"-j", "-g1"}, []int{1}},
+		{"multi", []int{0, 1, 2}, true, []string{"-j", "-g0:1:2"}, []int{0, 1, 2}},
+		{"invalid", []int{8}, false, nil, nil},
+		{"negative", []int{-1}, false, nil, nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			e := Exiftool{}
+			if tc.inFamilies != nil {
+				err := GroupFamily(tc.inFamilies...)(&e)
+				assert.Equal(t, tc.expOk, err == nil)
+			}
+			if tc.expOk {
+				assert.Equal(t, tc.expArgs, e.extractArgs(e.extractConfig()))
+				assert.Equal(t, tc.expFamilies, e.families())
+			}
+		})
+	}
+
+	assert.NotNil(t, GroupFamily()(&Exiftool{}))
+}
+
+func TestNewExifTool_WithGroupFamily(t *testing.T) {
+	e, err := NewExiftool(GroupFamily(0, 1))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	metas := e.ExtractMetadata("./testdata/20190404_131804.jpg")
+	assert.Equal(t, 1, len(metas))
+	assert.Nil(t, metas[0].Err)
+	assert.Equal(t, []int{0, 1}, metas[0].GroupFamilies)
+
+	mk, err := metas[0].Groups["EXIF:IFD0"].GetString("Make")
+	assert.Nil(t, err)
+	assert.Equal(t, "samsung", mk)
+	assert.Equal(t, map[int]string{0: "EXIF", 1: "IFD0"}, metas[0].GroupComponents("EXIF:IFD0"))
+}
+
+func TestAutoRestart(t *testing.T) {
+	e := Exiftool{}
+	assert.NotNil(t, AutoRestart(0, time.Second)(&e))
+	assert.NotNil(t, AutoRestart(1, -time.Second)(&e))
+	assert.Nil(t, AutoRestart(3, time.Millisecond)(&e))
+	assert.Equal(t, 3, e.restartTries)
+	assert.Equal(t, time.Millisecond, e.restartDelay)
+}
+
+func TestHealthyAndPing(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	assert.True(t, e.Healthy())
+	assert.Nil(t, e.Ping())
+
+	assert.Nil(t, e.cmd.Process.Kill())
+	<-e.exited
+	assert.False(t, e.Healthy())
+	assert.NotNil(t, e.Ping())
+}
+
+func TestClosed(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	assert.Nil(t, e.Close())
+
+	assert.Equal(t, ErrClosed, e.Ping())
+	fms := e.ExtractMetadata("./testdata/20190404_131804.jpg")
+	assert.Equal(t, 1, len(fms))
+	assert.Equal(t, ErrClosed, fms[0].Err)
+	assert.False(t, e.Healthy())
+}
+
+// TestConcurrentUse is meant to be run with the race detector (go test -race)
+func TestConcurrentUse(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+
+	calls := []func(){
+		func() {
+			fms := e.ExtractMetadata("./testdata/20190404_131804.jpg", "./testdata/nonExisting")
+			assert.Equal(t, 2, len(fms))
+			assert.Nil(t, fms[0].Err)
+			assert.Equal(t, "./testdata/20190404_131804.jpg", fms[0].File)
+			assert.NotNil(t, fms[1].Err)
+		},
+		func() {
+			fms := e.Extract([]string{"./testdata/empty.jpg"}, WithFast(1))
+			assert.Equal(t, 1, len(fms))
+			assert.Nil(t, fms[0].Err)
+			assert.Equal(t, "./testdata/empty.jpg", fms[0].File)
+		},
+		func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+			fms := e.ExtractMetadataContext(ctx, "./testdata/20190404_131804.jpg")
+			assert.Equal(t, 1, len(fms))
+			assert.Nil(t, fms[0].Err)
+		},
+		func() { assert.Nil(t, e.Write(f, FileMetadataValues{{"Artist", "go-exiftool"}})) },
+		func() { assert.Nil(t, e.Ping()) },
+		func() { assert.True(t, e.Healthy()) },
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		for _, c := range calls {
+			wg.Add(1)
+			go func(c func()) {
+				defer wg.Done()
+				c()
+			}(c)
+		}
+	}
+	wg.Wait()
+}
+
+func TestNewExifTool_WithAutoRestart(t *testing.T) {
+	e, err := NewExiftool(AutoRestart(2, time.Millisecond))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	assert.Nil(t, e.cmd.Process.Kill())
+	<-e.exited
+	assert.False(t, e.Healthy())
+
+	assert.Nil(t, e.Ping())
+	assert.True(t, e.Healthy())
+
+	assert.Nil(t, e.cmd.Process.Kill())
+	<-e.exited
+
+	metas := e.ExtractMetadata("./testdata/20190404_131804.jpg")
+	assert.Equal(t, 1, len(metas))
+	assert.Nil(t, metas[0].Err)
+}
+
+func TestExiftoolDecode(t *testing.T) {
+	var tcs = []struct {
+		tcID      string
+		inOpts    []Option
+		inOut     string
+		expOk     bool
+		expRaw    string
+		expGroups map[string]FileMetadataValues
+	}{
+		{"default", nil, `[{"SourceFile":"a.jpg","File":{"FileName":"a.jpg"}}]`, true, "",
+			map[string]FileMetadataValues{"File": {{"FileName", "a.jpg"}}}},
+		{"raw", []Option{KeepRawJSON()}, `[{"SourceFile":"a.jpg","File":{"FileName":"a.jpg"}}]`, true, `{"SourceFile":"a.jpg","File":{"FileName":"a.jpg"}}`,
+			map[string]FileMetadataValues{"File": {{"FileName", "a.jpg"}}}},
+		{"custom", []Option{CustomDecoder(func(raw json.RawMessage, fm *FileMetadata) error {
+			fm.Groups = map[string]FileMetadataValues{"custom": {{"len", len(raw)}}}
+			return nil
+		})}, `[{"a":1}]`, true, "", map[string]FileMetadataValues{"custom": {{"len", 7}}}},
+		{"customKo", []Option{CustomDecoder(func(raw json.RawMessage, fm *FileMetadata) error {
+			return fmt.Errorf("err")
+		})}, `[{"a":1}]`, false, "", nil},
+		{"invalid", nil, `[{`, false, "", nil},
+		{"empty", nil, `[]`, false, "", nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			e := Exiftool{}
+			for _, opt := range tc.inOpts {
+				assert.Nil(t, opt(&e))
+			}
+			fm := FileMetadata{File: "a.jpg"}
+			e.decode(&fm, []byte(tc.inOut), nil)
+			assert.Equal(t, tc.expOk, fm.Err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.expRaw, string(fm.Raw))
+				assert.Equal(t, tc.expGroups, fm.Groups)
+			}
+		})
+	}
+
+	assert.NotNil(t, CustomDecoder(nil)(&Exiftool{}))
+}
+
+func TestStrictMode(t *testing.T) {
+	var tcs = []struct {
+		tcID     string
+		strict   bool
+		inOut    string
+		messages []string
+		expOk    bool
+	}{
+		{"noWarning", true, `[{"SourceFile":"a.jpg","File":{"FileName":"a.jpg"}}]`, nil, true},
+		{"warningTag", true, `[{"SourceFile":"a.jpg","ExifTool":{"Warning":"Bad IFD0 directory"}}]`, nil, false},
+		{"warningMessage", true, `[{"SourceFile":"a.jpg","File":{"FileName":"a.jpg"}}]`, []string{"Warning: Bad IFD0 directory - a.jpg"}, false},
+		{"notStrict", false, `[{"SourceFile":"a.jpg","ExifTool":{"Warning":"Bad IFD0 directory"}}]`, nil, true},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			e := Exiftool{}
+			if tc.strict {
+				assert.Nil(t, StrictMode()(&e))
+			}
+			fm := FileMetadata{File: "a.jpg"}
+			e.decode(&fm, []byte(tc.inOut), tc.messages)
+			assert.Equal(t, tc.expOk, fm.Err == nil)
+			assert.NotNil(t, fm.Groups)
+			if !tc.expOk {
+				var w *WarningError
+				assert.True(t, errors.As(fm.Err, &w))
+				assert.Equal(t, "a.jpg", w.File)
+				assert.Equal(t, []string{"Bad IFD0 directory"}, w.Warnings)
+			}
+		})
+	}
+}
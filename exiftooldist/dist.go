@@ -0,0 +1,467 @@
+// Package exiftooldist installs an exiftool distribution (ie. the Image-ExifTool tarball
+// or the Windows executable archive published on https://exiftool.org) shipped with a Go
+// binary or downloaded at runtime, so that go-exiftool can be used without a
+// preinstalled exiftool.
+package exiftooldist
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/barasher/go-exiftool"
+)
+
+// ErrExecutableNotFound is returned when an archive doesn't contain any exiftool
+// executable
+var ErrExecutableNotFound = errors.New("no exiftool executable in distribution")
+
+// executables are the names of the exiftool executables looked for in a distribution,
+// by order of preference
+var executables = []string{"exiftool", "exiftool.exe"}
+
+// keyPressExecutable is the name of the Windows executable as distributed, which waits
+// for a key press before exiting and is renamed to "exiftool.exe" on install, see
+// https://exiftool.org/install.html#Windows
+const keyPressExecutable = "exiftool(-k).exe"
+
+// ChecksumError is returned when the SHA-256 checksum of an archive doesn't match the
+// expected one
+type ChecksumError struct {
+	Expected string
+	Actual   string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("checksum mismatch (expected %v, got %v)", e.Expected, e.Actual)
+}
+
+// Distribution is an exiftool distribution installed on the disk
+type Distribution struct {
+	// Dir is the directory the distribution was extracted to
+	Dir string
+	// Path is the path of the exiftool executable (the Perl script or the Windows
+	// executable)
+	Path string
+	// Perl is the path of the Perl interpreter running Path, empty if Path is run directly
+	Perl string
+}
+
+// Option is a configuration function applied by Install, InstallFS and Download
+type Option func(*config) error
+
+type config struct {
+	perl   string
+	noPerl bool
+	client *http.Client
+	lookup func(string) (string, error)
+}
+
+// Perl sets the path of the Perl interpreter running the exiftool script, instead of
+// looking for "perl" in the PATH
+// Sample :
+//   d, err := Install(dir, r, checksum, Perl("/usr/local/bin/perl"))
+func Perl(p string) Option {
+	return func(c *config) error {
+		if _, err := os.Stat(p); err != nil {
+			return fmt.Errorf("error while checking if path '%v' exists: %w", p, err)
+		}
+		c.perl = p
+		return nil
+	}
+}
+
+// NoPerl makes the exiftool script be run directly, relying on its shebang
+// Sample :
+//   d, err := Install(dir, r, checksum, NoPerl())
+func NoPerl() Option {
+	return func(c *config) error {
+		c.noPerl = true
+		return nil
+	}
+}
+
+// HTTPClient sets the client used by Download (http.DefaultClient by default)
+// Sample :
+//   d, err := Download(ctx, url, dir, checksum, HTTPClient(&http.Client{Timeout: time.Minute}))
+func HTTPClient(cl *http.Client) Option {
+	return func(c *config) error {
+		if cl == nil {
+			return errors.New("nil HTTP client")
+		}
+		c.client = cl
+		return nil
+	}
+}
+
+func newConfig(opts []Option) (config, error) {
+	c := config{client: http.DefaultClient, lookup: exec.LookPath}
+	for _, opt := range opts {
+		if err := opt(&c); err != nil {
+			return c, fmt.Errorf("error when configuring distribution: %w", err)
+		}
+	}
+	return c, nil
+}
+
+// Install verifies that the SHA-256 checksum (hexadecimal) of the distribution archive
+// read from r (zip, tar or gzipped tar) is checksum, extracts it to a subdirectory of dir
+// named after the checksum and locates its exiftool executable. An already installed
+// distribution is reused without being extracted again. If anything went wrong, a non
+// empty error will be returned.
+// Sample :
+//   f, err := os.Open("Image-ExifTool-12.40.tar.gz")
+//   d, err := Install("/var/cache/myapp", f, "5b6cf1c5...")
+//   e, err := d.NewExiftool()
+func Install(dir string, r io.Reader, checksum string, opts ...Option) (*Distribution, error) {
+	c, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	target, err := targetDir(dir, checksum)
+	if err != nil {
+		return nil, err
+	}
+
+	if d, err := c.open(target); err == nil {
+		return d, nil
+	}
+	if err := install(target, r, checksum); err != nil {
+		return nil, err
+	}
+	return c.open(target)
+}
+
+// Download behaves like Install, the archive being downloaded from url if the
+// distribution isn't installed yet
+// Sample :
+//   d, err := Download(ctx, "https://exiftool.org/Image-ExifTool-12.40.tar.gz", "/var/cache/myapp", "5b6cf1c5...")
+func Download(ctx context.Context, url, dir, checksum string, opts ...Option) (*Distribution, error) {
+	c, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	target, err := targetDir(dir, checksum)
+	if err != nil {
+		return nil, err
+	}
+
+	if d, err := c.open(target); err == nil {
+		return d, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error when building request: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error when downloading distribution: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error when downloading distribution: unexpected status %v", resp.Status)
+	}
+
+	if err := install(target, resp.Body, checksum); err != nil {
+		return nil, err
+	}
+	return c.open(target)
+}
+
+// Option returns the exiftool configuration functions running the distribution
+// Sample :
+//   e, err := exiftool.NewExiftool(d.Option(), exiftool.Charset("utf8"))
+func (d *Distribution) Option() exiftool.Option {
+	return func(e *exiftool.Exiftool) error {
+		if err := exiftool.SetExiftoolBinaryPath(d.Path)(e); err != nil {
+			return err
+		}
+		if d.Perl == "" {
+			return nil
+		}
+		return exiftool.SetPerlInterpreter(d.Perl)(e)
+	}
+}
+
+// NewExiftool instanciates an Exiftool running the distribution, opts being applied
+// afterwards
+// Sample :
+//   e, err := d.NewExiftool(exiftool.Charset("utf8"))
+func (d *Distribution) NewExiftool(opts ...exiftool.Option) (*exiftool.Exiftool, error) {
+	return exiftool.NewExiftool(append([]exiftool.Option{d.Option()}, opts...)...)
+}
+
+// targetDir returns the directory of dir the distribution of checksum is extracted to
+func targetDir(dir, checksum string) (string, error) {
+	b, err := hex.DecodeString(checksum)
+	if err != nil || len(b) != sha256.Size {
+		return "", fmt.Errorf("invalid SHA-256 checksum (%v)", checksum)
+	}
+	return filepath.Join(dir, "exiftool-"+hex.EncodeToString(b)[:16]), nil
+}
+
+// open returns the distribution installed in target
+func (c config) open(target string) (*Distribution, error) {
+	p, err := findExecutable(target)
+	if err != nil {
+		return nil, err
+	}
+
+	d := Distribution{Dir: target, Path: p}
+	if strings.EqualFold(filepath.Ext(p), ".exe") || c.noPerl {
+		return &d, nil
+	}
+	d.Perl = c.perl
+	if d.Perl == "" {
+		// no interpreter found: relying on the shebang
+		d.Perl, _ = c.lookup("perl")
+	}
+	return &d, nil
+}
+
+// findExecutable returns the path of the least nested exiftool executable of dir
+func findExecutable(dir string) (string, error) {
+	var found []string
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		for _, n := range executables {
+			if strings.EqualFold(info.Name(), n) {
+				found = append(found, p)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("error when looking for exiftool executable: %w", err)
+	}
+	if len(found) == 0 {
+		return "", ErrExecutableNotFound
+	}
+
+	rank := func(p string) int {
+		for i, n := range executables {
+			if strings.EqualFold(filepath.Base(p), n) {
+				return i
+			}
+		}
+		return len(executables)
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		di, dj := strings.Count(found[i], string(filepath.Separator)), strings.Count(found[j], string(filepath.Separator))
+		if di != dj {
+			return di < dj
+		}
+		return rank(found[i]) < rank(found[j])
+	})
+	return found[0], nil
+}
+
+// install verifies the checksum of the archive read from r and extracts it to target,
+// through a temporary directory renamed once complete so that an interrupted
+// installation is never reused
+func install(target string, r io.Reader, checksum string) error {
+	parent := filepath.Dir(target)
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return fmt.Errorf("error when creating directory: %w", err)
+	}
+
+	archive, err := ioutil.TempFile(parent, "archive-*")
+	if err != nil {
+		return fmt.Errorf("error when creating archive: %w", err)
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(archive, h), r)
+	if err != nil {
+		return fmt.Errorf("error when reading archive: %w", err)
+	}
+	if actual := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(actual, checksum) {
+		return &ChecksumError{Expected: strings.ToLower(checksum), Actual: actual}
+	}
+
+	tmp, err := ioutil.TempDir(parent, "install-*")
+	if err != nil {
+		return fmt.Errorf("error when creating directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := extract(tmp, archive, size); err != nil {
+		return err
+	}
+	if err := renameKeyPress(tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		if _, serr := os.Stat(target); serr == nil {
+			// installed concurrently
+			return nil
+		}
+		return fmt.Errorf("error when installing distribution: %w", err)
+	}
+	return nil
+}
+
+// renameKeyPress renames the keyPressExecutable files of dir to "exiftool.exe", next to
+// their exiftool_files directory, unless an "exiftool.exe" already exists
+func renameKeyPress(dir string) error {
+	var found []string
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() && strings.EqualFold(info.Name(), keyPressExecutable) {
+			found = append(found, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error when looking for exiftool executable: %w", err)
+	}
+
+	for _, p := range found {
+		dst := filepath.Join(filepath.Dir(p), "exiftool.exe")
+		if _, err := os.Stat(dst); err == nil {
+			continue
+		}
+		if err := os.Rename(p, dst); err != nil {
+			return fmt.Errorf("error when renaming exiftool executable: %w", err)
+		}
+	}
+	return nil
+}
+
+// extract extracts the zip, tar or gzipped tar archive f of size bytes to dir
+func extract(dir string, f *os.File, size int64) error {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("error when reading archive: %w", err)
+	}
+	br := bufio.NewReader(f)
+	header, _ := br.Peek(4)
+
+	switch {
+	case bytes.HasPrefix(header, []byte("PK\x03\x04")):
+		zr, err := zip.NewReader(f, size)
+		if err != nil {
+			return fmt.Errorf("error when reading zip archive: %w", err)
+		}
+		return extractZip(dir, zr)
+	case bytes.HasPrefix(header, []byte{0x1f, 0x8b}):
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return fmt.Errorf("error when reading gzip archive: %w", err)
+		}
+		defer gr.Close()
+		return extractTar(dir, tar.NewReader(gr))
+	default:
+		return extractTar(dir, tar.NewReader(br))
+	}
+}
+
+func extractZip(dir string, zr *zip.Reader) error {
+	for _, zf := range zr.File {
+		p, err := entryPath(dir, zf.Name)
+		if err != nil {
+			return err
+		}
+		if zf.FileInfo().IsDir() {
+			if err := os.MkdirAll(p, 0755); err != nil {
+				return fmt.Errorf("error when creating directory: %w", err)
+			}
+			continue
+		}
+		if !zf.Mode().IsRegular() {
+			continue
+		}
+
+		rc, err := zf.Open()
+		if err != nil {
+			return fmt.Errorf("error when reading %v: %w", zf.Name, err)
+		}
+		err = writeFile(p, rc, zf.Mode())
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractTar(dir string, tr *tar.Reader) error {
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error when reading tar archive: %w", err)
+		}
+
+		p, err := entryPath(dir, hdr.Name)
+		if err != nil {
+			return err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(p, 0755); err != nil {
+				return fmt.Errorf("error when creating directory: %w", err)
+			}
+		case tar.TypeReg:
+			if err := writeFile(p, tr, hdr.FileInfo().Mode()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// entryPath returns the path of the archive entry name extracted to dir, refusing the
+// entries escaping dir
+func entryPath(dir, name string) (string, error) {
+	p := filepath.Join(dir, filepath.FromSlash(name))
+	if p != dir && !strings.HasPrefix(p, dir+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid archive entry (%v)", name)
+	}
+	return p, nil
+}
+
+// writeFile writes the content read from r to the file p, keeping the executable bits
+// of mode
+func writeFile(p string, r io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return fmt.Errorf("error when creating directory: %w", err)
+	}
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644|(mode&0111))
+	if err != nil {
+		return fmt.Errorf("error when creating %v: %w", p, err)
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("error when writing %v: %w", p, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("error when writing %v: %w", p, err)
+	}
+	return nil
+}
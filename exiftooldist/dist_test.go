@@ -0,0 +1,266 @@
+package exiftooldist
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// script runs the exiftool of the PATH
+const script = "#!/bin/sh\nexec exiftool \"$@\"\n"
+
+type entry struct {
+	name string
+	data string
+	mode int64
+}
+
+var distEntries = []entry{
+	{"Image-ExifTool-12.40/lib/Image/ExifTool.pm", "1;\n", 0644},
+	{"Image-ExifTool-12.40/t/exiftool", "", 0644},
+	{"Image-ExifTool-12.40/exiftool", script, 0755},
+}
+
+func tarGz(t *testing.T, gz bool, entries ...entry) []byte {
+	var b bytes.Buffer
+	var tw *tar.Writer
+	var gw *gzip.Writer
+	if gz {
+		gw = gzip.NewWriter(&b)
+		tw = tar.NewWriter(gw)
+	} else {
+		tw = tar.NewWriter(&b)
+	}
+	for _, e := range entries {
+		assert.Nil(t, tw.WriteHeader(&tar.Header{Name: e.name, Mode: e.mode, Size: int64(len(e.data)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(e.data))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, tw.Close())
+	if gz {
+		assert.Nil(t, gw.Close())
+	}
+	return b.Bytes()
+}
+
+func zipArchive(t *testing.T, entries ...entry) []byte {
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	for _, e := range entries {
+		w, err := zw.Create(e.name)
+		assert.Nil(t, err)
+		_, err = w.Write([]byte(e.data))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, zw.Close())
+	return b.Bytes()
+}
+
+func sum(b []byte) string {
+	s := sha256.Sum256(b)
+	return hex.EncodeToString(s[:])
+}
+
+func TestInstall(t *testing.T) {
+	var tcs = []struct {
+		tcID    string
+		archive func(t *testing.T) []byte
+		expPath string
+	}{
+		{"tarGz", func(t *testing.T) []byte { return tarGz(t, true, distEntries...) }, "Image-ExifTool-12.40/exiftool"},
+		{"tar", func(t *testing.T) []byte { return tarGz(t, false, distEntries...) }, "Image-ExifTool-12.40/exiftool"},
+		{"zip", func(t *testing.T) []byte {
+			return zipArchive(t, entry{name: "exiftool-12.40/exiftool_files/perl.exe"}, entry{name: "exiftool-12.40/exiftool(-k).exe"})
+		}, "exiftool-12.40/exiftool.exe"},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "exiftooldist")
+			assert.Nil(t, err)
+			defer os.RemoveAll(dir)
+
+			b := tc.archive(t)
+			d, err := Install(dir, bytes.NewReader(b), strings.ToUpper(sum(b)), NoPerl())
+			assert.Nil(t, err)
+			assert.Equal(t, filepath.Join(dir, "exiftool-"+sum(b)[:16]), d.Dir)
+			assert.Equal(t, filepath.Join(d.Dir, filepath.FromSlash(tc.expPath)), d.Path)
+			assert.Equal(t, "", d.Perl)
+
+			// the installed distribution is reused
+			d2, err := Install(dir, bytes.NewReader(nil), sum(b), NoPerl())
+			assert.Nil(t, err)
+			assert.Equal(t, d, d2)
+
+			// only the installed distribution remains
+			infos, err := ioutil.ReadDir(dir)
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(infos))
+		})
+	}
+}
+
+func TestRenameKeyPress(t *testing.T) {
+	dir, err := ioutil.TempDir("", "exiftooldist")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, p := range []string{"a/exiftool(-k).exe", "b/exiftool(-k).exe", "b/exiftool.exe"} {
+		p = filepath.Join(dir, filepath.FromSlash(p))
+		assert.Nil(t, os.MkdirAll(filepath.Dir(p), 0755))
+		assert.Nil(t, ioutil.WriteFile(p, []byte(p), 0755))
+	}
+
+	assert.Nil(t, renameKeyPress(dir))
+	b, err := ioutil.ReadFile(filepath.Join(dir, "a", "exiftool.exe"))
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(dir, "a", "exiftool(-k).exe"), string(b))
+	_, err = os.Stat(filepath.Join(dir, "a", "exiftool(-k).exe"))
+	assert.True(t, os.IsNotExist(err))
+	b, err = ioutil.ReadFile(filepath.Join(dir, "b", "exiftool.exe"))
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(dir, "b", "exiftool.exe"), string(b))
+
+	assert.NotNil(t, renameKeyPress(filepath.Join(dir, "nonExisting")))
+}
+
+func TestInstallKo(t *testing.T) {
+	valid := tarGz(t, true, distEntries...)
+	noExec := tarGz(t, true, entry{"Image-ExifTool-12.40/lib/Image/ExifTool.pm", "1;\n", 0644})
+	escaping := tarGz(t, false, entry{"../exiftool", script, 0755})
+
+	var tcs = []struct {
+		tcID     string
+		archive  []byte
+		checksum string
+		expErr   func(error) bool
+	}{
+		{"invalidChecksum", valid, "abc", nil},
+		{"checksumMismatch", valid, sum(noExec), func(err error) bool {
+			var ce *ChecksumError
+			return errors.As(err, &ce) && ce.Expected == sum(noExec) && ce.Actual == sum(valid)
+		}},
+		{"noExecutable", noExec, sum(noExec), func(err error) bool { return errors.Is(err, ErrExecutableNotFound) }},
+		{"escaping", escaping, sum(escaping), nil},
+		{"corrupted", valid[:20], sum(valid[:20]), nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "exiftooldist")
+			assert.Nil(t, err)
+			defer os.RemoveAll(dir)
+
+			_, err = Install(dir, bytes.NewReader(tc.archive), tc.checksum)
+			assert.NotNil(t, err)
+			if tc.expErr != nil {
+				assert.True(t, tc.expErr(err), err)
+			}
+
+			infos, err := ioutil.ReadDir(dir)
+			assert.Nil(t, err)
+			if tc.tcID != "noExecutable" {
+				assert.Equal(t, 0, len(infos))
+			}
+		})
+	}
+}
+
+func TestPerl(t *testing.T) {
+	assert.NotNil(t, Perl("./nonExisting")(&config{}))
+
+	c, err := newConfig(nil)
+	assert.Nil(t, err)
+	c.lookup = func(string) (string, error) { return "/usr/bin/perl", nil }
+
+	dir, err := ioutil.TempDir("", "exiftooldist")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "exiftool"), []byte(script), 0755))
+
+	d, err := c.open(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, "/usr/bin/perl", d.Perl)
+
+	assert.Nil(t, Perl(filepath.Join(dir, "exiftool"))(&c))
+	d, err = c.open(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(dir, "exiftool"), d.Perl)
+
+	assert.Nil(t, NoPerl()(&c))
+	d, err = c.open(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, "", d.Perl)
+}
+
+func TestDownload(t *testing.T) {
+	b := tarGz(t, true, distEntries...)
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/Image-ExifTool-12.40.tar.gz" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(b)
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "exiftooldist")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = Download(context.Background(), srv.URL+"/nonExisting", dir, sum(b), NoPerl())
+	assert.NotNil(t, err)
+
+	d, err := Download(context.Background(), srv.URL+"/Image-ExifTool-12.40.tar.gz", dir, sum(b), NoPerl(), HTTPClient(srv.Client()))
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(d.Dir, "Image-ExifTool-12.40", "exiftool"), d.Path)
+	assert.Equal(t, 2, calls)
+
+	// nothing is downloaded once installed
+	_, err = Download(context.Background(), srv.URL+"/Image-ExifTool-12.40.tar.gz", dir, sum(b), NoPerl())
+	assert.Nil(t, err)
+	assert.Equal(t, 2, calls)
+
+	assert.NotNil(t, HTTPClient(nil)(&config{}))
+}
+
+func TestDistributionNewExiftool(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script distribution")
+	}
+
+	dir, err := ioutil.TempDir("", "exiftooldist")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	b := tarGz(t, true, distEntries...)
+	d, err := Install(dir, bytes.NewReader(b), sum(b), NoPerl())
+	assert.Nil(t, err)
+
+	e, err := d.NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	fms := e.ExtractMetadata("../testdata/20190404_131804.jpg")
+	assert.Equal(t, 1, len(fms))
+	assert.Nil(t, fms[0].Err)
+}
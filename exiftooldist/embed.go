@@ -0,0 +1,26 @@
+//go:build go1.16
+// +build go1.16
+
+package exiftooldist
+
+import (
+	"fmt"
+	"io/fs"
+)
+
+// InstallFS behaves like Install, the archive being the file name of fsys (ie. an
+// embed.FS shipping the distribution within the Go binary)
+// Sample :
+//   //go:embed Image-ExifTool-12.40.tar.gz
+//   var dist embed.FS
+//
+//   d, err := InstallFS(dist, "Image-ExifTool-12.40.tar.gz", "/var/cache/myapp", "5b6cf1c5...")
+func InstallFS(fsys fs.FS, name, dir, checksum string, opts ...Option) (*Distribution, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("error when opening archive: %w", err)
+	}
+	defer f.Close()
+
+	return Install(dir, f, checksum, opts...)
+}
@@ -0,0 +1,30 @@
+//go:build go1.16
+// +build go1.16
+
+package exiftooldist
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstallFS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "exiftooldist")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	b := tarGz(t, true, distEntries...)
+	fsys := fstest.MapFS{"dist/Image-ExifTool-12.40.tar.gz": {Data: b}}
+
+	d, err := InstallFS(fsys, "dist/Image-ExifTool-12.40.tar.gz", dir, sum(b), NoPerl())
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(d.Dir, "Image-ExifTool-12.40", "exiftool"), d.Path)
+
+	_, err = InstallFS(fsys, "nonExisting.tar.gz", dir, sum(b))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,287 @@
+// Package exiftooltest provides a fake exiftool.Extractor, so that the code relying on
+// go-exiftool can be tested without an exiftool binary.
+package exiftooltest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/barasher/go-exiftool"
+)
+
+// defaultGroup is the group of the written tags whose group isn't provided and that
+// don't exist yet
+const defaultGroup = "XMP"
+
+// Write is a write received by a Fake, Delete being recorded as a write of nil values
+type Write struct {
+	File   string
+	Values exiftool.FileMetadataValues
+}
+
+// Fake is an in-memory exiftool.Extractor serving fixtures. Extracting a file without
+// fixture returns an exiftool.FileNotFoundError, and the fixtures having an Err return
+// it. Writes are recorded and applied to the fixtures: "GROUP:LABEL" values are written
+// to the GROUP group, "LABEL" values to every group holding LABEL (the XMP group if
+// none), nil values deleting the tags. The ExtractOptions are ignored. Fake is safe for
+// concurrent use.
+type Fake struct {
+	lock     sync.Mutex
+	fixtures map[string]exiftool.FileMetadata
+	writes   []Write
+	closed   bool
+}
+
+var _ exiftool.Extractor = (*Fake)(nil)
+
+// NewFake instanciates a Fake serving fixtures, identified by their File
+// Sample :
+//   f := NewFake(exiftool.FileMetadata{File: "a.jpg", Groups: map[string]exiftool.FileMetadataValues{
+//     "EXIF": {{"Make", "Canon"}},
+//   }})
+func NewFake(fixtures ...exiftool.FileMetadata) *Fake {
+	f := Fake{fixtures: map[string]exiftool.FileMetadata{}}
+	f.Add(fixtures...)
+	return &f
+}
+
+// NewFakeFromJSON instanciates a Fake serving the fixtures read from r, a JSON array as
+// printed by exiftool ('-j -g' parameters), identified by their SourceFile. If anything
+// went wrong, a non empty error will be returned.
+// Sample :
+//   f, err := NewFakeFromJSON(strings.NewReader(`[{"SourceFile":"a.jpg","EXIF":{"Make":"Canon"}}]`))
+func NewFakeFromJSON(r io.Reader) (*Fake, error) {
+	f := NewFake()
+	d := exiftool.NewStreamDecoder(r)
+	for {
+		fm, err := d.Next()
+		if err == io.EOF {
+			return f, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error while reading fixtures: %w", err)
+		}
+		f.Add(fm)
+	}
+}
+
+// LoadFake instanciates a Fake serving the fixtures of the JSON files paths (ie. the
+// output of 'exiftool -j -g FILES > fixtures.json'), see NewFakeFromJSON
+// Sample :
+//   f, err := LoadFake("testdata/fixtures.json")
+func LoadFake(paths ...string) (*Fake, error) {
+	f := NewFake()
+	for _, p := range paths {
+		r, err := os.Open(p)
+		if err != nil {
+			return nil, fmt.Errorf("error while opening fixtures: %w", err)
+		}
+		lf, err := NewFakeFromJSON(r)
+		r.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error while loading %v: %w", p, err)
+		}
+		for _, fm := range lf.fixtures {
+			f.Add(fm)
+		}
+	}
+	return f, nil
+}
+
+// Add adds fixtures, replacing the existing ones of the same File
+func (f *Fake) Add(fixtures ...exiftool.FileMetadata) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, fm := range fixtures {
+		f.fixtures[fm.File] = clone(fm)
+	}
+}
+
+// Writes returns the writes received so far, in order
+func (f *Fake) Writes() []Write {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	ws := make([]Write, len(f.writes))
+	copy(ws, f.writes)
+	return ws
+}
+
+// ExtractMetadata returns the fixtures of files, in the same order as files
+func (f *Fake) ExtractMetadata(files ...string) []exiftool.FileMetadata {
+	return f.ExtractContext(context.Background(), files)
+}
+
+// ExtractMetadataContext behaves like ExtractMetadata, the files being returned with
+// ctx.Err() once ctx is done
+func (f *Fake) ExtractMetadataContext(ctx context.Context, files ...string) []exiftool.FileMetadata {
+	return f.ExtractContext(ctx, files)
+}
+
+// Extract behaves like ExtractMetadata, opts being ignored
+func (f *Fake) Extract(files []string, opts ...exiftool.ExtractOption) []exiftool.FileMetadata {
+	return f.ExtractContext(context.Background(), files, opts...)
+}
+
+// ExtractContext behaves like ExtractMetadataContext, opts being ignored
+func (f *Fake) ExtractContext(ctx context.Context, files []string, opts ...exiftool.ExtractOption) []exiftool.FileMetadata {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	fms := make([]exiftool.FileMetadata, len(files))
+	for i, file := range files {
+		switch fm, found := f.fixtures[file]; {
+		case f.closed:
+			fms[i] = exiftool.FileMetadata{File: file, Err: exiftool.ErrClosed}
+		case ctx.Err() != nil:
+			fms[i] = exiftool.FileMetadata{File: file, Err: ctx.Err()}
+		case !found:
+			fms[i] = exiftool.FileMetadata{File: file, Err: &exiftool.FileNotFoundError{File: file}}
+		default:
+			fms[i] = clone(fm)
+		}
+		fms[i].Index = i
+	}
+	return fms
+}
+
+// Write records the write of values to file and applies it to its fixture. As with
+// exiftool, deleting "GROUP:all" removes the whole group and deleting "all" every group.
+func (f *Fake) Write(file string, values exiftool.FileMetadataValues) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.closed {
+		return exiftool.ErrClosed
+	}
+	fm, found := f.fixtures[file]
+	if !found {
+		return &exiftool.FileNotFoundError{File: file}
+	}
+
+	f.writes = append(f.writes, Write{File: file, Values: append(exiftool.FileMetadataValues{}, values...)})
+
+	if fm.Groups == nil {
+		fm.Groups = map[string]exiftool.FileMetadataValues{}
+	}
+	for _, v := range values {
+		if v.Value == nil && strings.EqualFold(tagLabel(v.Label), "all") {
+			deleteGroups(fm, v.Label)
+			continue
+		}
+		for _, g := range targetGroups(fm, v.Label) {
+			fm.Groups[g] = setValue(fm.Groups[g], tagLabel(v.Label), v.Value)
+			if len(fm.Groups[g]) == 0 {
+				delete(fm.Groups, g)
+			}
+		}
+	}
+	f.fixtures[file] = fm
+
+	return nil
+}
+
+// Delete records the deletion of tags from file and applies it to its fixture
+func (f *Fake) Delete(file string, tags ...string) error {
+	if len(tags) == 0 {
+		return fmt.Errorf("no tag to delete")
+	}
+
+	values := make(exiftool.FileMetadataValues, len(tags))
+	for i, t := range tags {
+		values[i] = exiftool.FileMetadataValue{Label: t}
+	}
+
+	return f.Write(file, values)
+}
+
+// Close makes the following calls fail with exiftool.ErrClosed
+func (f *Fake) Close() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.closed = true
+	return nil
+}
+
+// targetGroups returns the groups written by label in fm, sorted alphabetically
+func targetGroups(fm exiftool.FileMetadata, label string) []string {
+	if idx := strings.LastIndex(label, ":"); idx != -1 {
+		return []string{label[:idx]}
+	}
+
+	var grps []string
+	l := tagLabel(label)
+	for n, g := range fm.Groups {
+		for _, v := range g {
+			if v.Label == l {
+				grps = append(grps, n)
+				break
+			}
+		}
+	}
+	if len(grps) == 0 {
+		return []string{defaultGroup}
+	}
+	sort.Strings(grps)
+	return grps
+}
+
+// deleteGroups removes from fm the group of label ("GROUP:all"), case insensitively, or
+// every group if label has no group ("all")
+func deleteGroups(fm exiftool.FileMetadata, label string) {
+	idx := strings.LastIndex(label, ":")
+	for n := range fm.Groups {
+		if idx == -1 || strings.EqualFold(n, label[:idx]) {
+			delete(fm.Groups, n)
+		}
+	}
+}
+
+// tagLabel returns the label of the tag written by label, without its group nor its
+// print conversion suffix ("#")
+func tagLabel(label string) string {
+	if idx := strings.LastIndex(label, ":"); idx != -1 {
+		label = label[idx+1:]
+	}
+	return strings.TrimSuffix(label, "#")
+}
+
+// setValue returns g where the value of label is v, label being removed if v is nil
+func setValue(g exiftool.FileMetadataValues, label string, v interface{}) exiftool.FileMetadataValues {
+	var res exiftool.FileMetadataValues
+	set := false
+	for _, f := range g {
+		if f.Label != label {
+			res = append(res, f)
+			continue
+		}
+		if v != nil && !set {
+			res = append(res, exiftool.FileMetadataValue{Label: label, Value: v})
+			set = true
+		}
+	}
+	if v != nil && !set {
+		res = append(res, exiftool.FileMetadataValue{Label: label, Value: v})
+	}
+	return res
+}
+
+// clone returns a copy of fm whose groups can be modified without altering fm
+func clone(fm exiftool.FileMetadata) exiftool.FileMetadata {
+	if fm.Groups == nil {
+		return fm
+	}
+	grps := make(map[string]exiftool.FileMetadataValues, len(fm.Groups))
+	for n, g := range fm.Groups {
+		grps[n] = append(exiftool.FileMetadataValues{}, g...)
+	}
+	fm.Groups = grps
+	return fm
+}
@@ -0,0 +1,195 @@
+package exiftooltest
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/barasher/go-exiftool"
+	"github.com/stretchr/testify/assert"
+)
+
+const fixtures = `[{
+  "SourceFile": "a.jpg",
+  "EXIF": {"Make": "Canon", "ISO": 100},
+  "XMP": {"Artist": "me"}
+},{
+  "SourceFile": "b.jpg",
+  "File": {"FileType": "JPEG"}
+}]`
+
+func TestNewFakeFromJSON(t *testing.T) {
+	f, err := NewFakeFromJSON(strings.NewReader(fixtures))
+	assert.Nil(t, err)
+
+	fms := f.ExtractMetadata("b.jpg", "a.jpg", "c.jpg")
+	assert.Equal(t, 3, len(fms))
+	for i, fm := range fms {
+		assert.Equal(t, i, fm.Index)
+	}
+
+	assert.Nil(t, fms[0].Err)
+	ft, err := fms[0].Groups["File"].GetString("FileType")
+	assert.Nil(t, err)
+	assert.Equal(t, "JPEG", ft)
+
+	assert.Nil(t, fms[1].Err)
+	mk, err := fms[1].Groups["EXIF"].GetString("Make")
+	assert.Nil(t, err)
+	assert.Equal(t, "Canon", mk)
+	iso, err := fms[1].Groups["EXIF"].GetInt("ISO")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100), iso)
+
+	var fnfe *exiftool.FileNotFoundError
+	assert.True(t, errors.As(fms[2].Err, &fnfe))
+	assert.Equal(t, "c.jpg", fms[2].File)
+
+	_, err = NewFakeFromJSON(strings.NewReader(`[{"SourceFile": `))
+	assert.NotNil(t, err)
+}
+
+func TestLoadFake(t *testing.T) {
+	tmp, err := ioutil.TempFile("", "fixtures-*.json")
+	assert.Nil(t, err)
+	defer os.Remove(tmp.Name())
+	_, err = tmp.WriteString(fixtures)
+	assert.Nil(t, err)
+	tmp.Close()
+
+	f, err := LoadFake(tmp.Name())
+	assert.Nil(t, err)
+	fms := f.ExtractMetadata("a.jpg", "b.jpg")
+	assert.Nil(t, fms[0].Err)
+	assert.Nil(t, fms[1].Err)
+
+	_, err = LoadFake("nonExisting.json")
+	assert.NotNil(t, err)
+}
+
+func TestFakeExtract(t *testing.T) {
+	expErr := errors.New("corrupted")
+	f := NewFake(
+		exiftool.FileMetadata{File: "a.jpg", Groups: map[string]exiftool.FileMetadataValues{"EXIF": {{Label: "Make", Value: "Canon"}}}},
+		exiftool.FileMetadata{File: "ko.jpg", Err: expErr},
+	)
+
+	fms := f.Extract([]string{"a.jpg", "ko.jpg"})
+	assert.Nil(t, fms[0].Err)
+	assert.Equal(t, expErr, fms[1].Err)
+
+	// the returned metadata can be modified without altering the fixtures
+	fms[0].Groups["EXIF"][0].Value = "Nikon"
+	mk, err := f.ExtractMetadata("a.jpg")[0].Groups["EXIF"].GetString("Make")
+	assert.Nil(t, err)
+	assert.Equal(t, "Canon", mk)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	fms = f.ExtractMetadataContext(ctx, "a.jpg")
+	assert.Equal(t, context.Canceled, fms[0].Err)
+}
+
+func TestFakeWrite(t *testing.T) {
+	var tcs = []struct {
+		tcID      string
+		in        exiftool.FileMetadataValues
+		expGroups map[string]exiftool.FileMetadataValues
+	}{
+		{"update", exiftool.FileMetadataValues{{Label: "Artist", Value: "you"}},
+			map[string]exiftool.FileMetadataValues{
+				"EXIF": {{Label: "Make", Value: "Canon"}, {Label: "Artist", Value: "you"}},
+				"XMP":  {{Label: "Artist", Value: "you"}},
+			}},
+		{"group", exiftool.FileMetadataValues{{Label: "EXIF:Artist#", Value: "you"}},
+			map[string]exiftool.FileMetadataValues{
+				"EXIF": {{Label: "Make", Value: "Canon"}, {Label: "Artist", Value: "you"}},
+				"XMP":  {{Label: "Artist", Value: "me"}},
+			}},
+		{"new", exiftool.FileMetadataValues{{Label: "Copyright", Value: "c"}},
+			map[string]exiftool.FileMetadataValues{
+				"EXIF": {{Label: "Make", Value: "Canon"}, {Label: "Artist", Value: "me"}},
+				"XMP":  {{Label: "Artist", Value: "me"}, {Label: "Copyright", Value: "c"}},
+			}},
+		{"newGroup", exiftool.FileMetadataValues{{Label: "IPTC:Keywords", Value: "k"}},
+			map[string]exiftool.FileMetadataValues{
+				"EXIF": {{Label: "Make", Value: "Canon"}, {Label: "Artist", Value: "me"}},
+				"XMP":  {{Label: "Artist", Value: "me"}},
+				"IPTC": {{Label: "Keywords", Value: "k"}},
+			}},
+		{"delete", exiftool.FileMetadataValues{{Label: "Artist"}},
+			map[string]exiftool.FileMetadataValues{
+				"EXIF": {{Label: "Make", Value: "Canon"}},
+			}},
+		{"deleteGroup", exiftool.FileMetadataValues{{Label: "exif:all"}},
+			map[string]exiftool.FileMetadataValues{
+				"XMP": {{Label: "Artist", Value: "me"}},
+			}},
+		{"deleteAll", exiftool.FileMetadataValues{{Label: "all"}},
+			map[string]exiftool.FileMetadataValues{}},
+		{"deleteAllThenWrite", exiftool.FileMetadataValues{{Label: "all"}, {Label: "XMP:Artist", Value: "you"}},
+			map[string]exiftool.FileMetadataValues{
+				"XMP": {{Label: "Artist", Value: "you"}},
+			}},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			f := NewFake(exiftool.FileMetadata{File: "a.jpg", Groups: map[string]exiftool.FileMetadataValues{
+				"EXIF": {{Label: "Make", Value: "Canon"}, {Label: "Artist", Value: "me"}},
+				"XMP":  {{Label: "Artist", Value: "me"}},
+			}})
+			assert.Nil(t, f.Write("a.jpg", tc.in))
+			assert.Equal(t, tc.expGroups, f.ExtractMetadata("a.jpg")[0].Groups)
+			assert.Equal(t, []Write{{File: "a.jpg", Values: tc.in}}, f.Writes())
+		})
+	}
+}
+
+func TestFakeDelete(t *testing.T) {
+	f := NewFake(exiftool.FileMetadata{File: "a.jpg", Groups: map[string]exiftool.FileMetadataValues{
+		"EXIF": {{Label: "Make", Value: "Canon"}, {Label: "Artist", Value: "me"}},
+	}})
+
+	assert.Nil(t, f.Delete("a.jpg", "Artist"))
+	assert.Equal(t, exiftool.FileMetadataValues{{Label: "Make", Value: "Canon"}}, f.ExtractMetadata("a.jpg")[0].Groups["EXIF"])
+	assert.Equal(t, []Write{{File: "a.jpg", Values: exiftool.FileMetadataValues{{Label: "Artist"}}}}, f.Writes())
+
+	assert.Nil(t, f.Delete("a.jpg", "EXIF:all"))
+	assert.Nil(t, f.ExtractMetadata("a.jpg")[0].Groups["EXIF"])
+
+	assert.NotNil(t, f.Delete("a.jpg"))
+	var fnfe *exiftool.FileNotFoundError
+	assert.True(t, errors.As(f.Delete("b.jpg", "Artist"), &fnfe))
+}
+
+func TestFakeClose(t *testing.T) {
+	f := NewFake(exiftool.FileMetadata{File: "a.jpg"})
+	assert.Nil(t, f.Close())
+
+	assert.Equal(t, exiftool.ErrClosed, f.ExtractMetadata("a.jpg")[0].Err)
+	assert.Equal(t, exiftool.ErrClosed, f.Write("a.jpg", exiftool.FileMetadataValues{{Label: "Artist", Value: "me"}}))
+}
+
+// consumer is a sample of code relying on go-exiftool
+func consumer(e exiftool.Extractor, file string) (string, error) {
+	fm := e.ExtractMetadata(file)[0]
+	if fm.Err != nil {
+		return "", fm.Err
+	}
+	return fm.Groups["EXIF"].GetString("Make")
+}
+
+func TestFakeExtractor(t *testing.T) {
+	f := NewFake(exiftool.FileMetadata{File: "a.jpg", Groups: map[string]exiftool.FileMetadataValues{
+		"EXIF": {{Label: "Make", Value: "Canon"}},
+	}})
+
+	mk, err := consumer(f, "a.jpg")
+	assert.Nil(t, err)
+	assert.Equal(t, "Canon", mk)
+}
@@ -0,0 +1,226 @@
+package exiftool
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MeteringMode is the EXIF metering mode of the camera
+type MeteringMode int
+
+// MeteringMode values, as defined by the EXIF specification
+const (
+	MeteringUnknown MeteringMode = iota
+	MeteringAverage
+	MeteringCenterWeightedAverage
+	MeteringSpot
+	MeteringMultiSpot
+	MeteringMultiSegment
+	MeteringPartial
+	MeteringOther MeteringMode = 255
+)
+
+// meteringModeNames are the values printed by exiftool
+var meteringModeNames = map[int]string{
+	int(MeteringUnknown):               "Unknown",
+	int(MeteringAverage):               "Average",
+	int(MeteringCenterWeightedAverage): "Center-weighted average",
+	int(MeteringSpot):                  "Spot",
+	int(MeteringMultiSpot):             "Multi-spot",
+	int(MeteringMultiSegment):          "Multi-segment",
+	int(MeteringPartial):               "Partial",
+	int(MeteringOther):                 "Other",
+}
+
+func (m MeteringMode) String() string {
+	return enumName(meteringModeNames, int(m))
+}
+
+// ExposureMode is the EXIF exposure mode of the camera
+type ExposureMode int
+
+// ExposureMode values, as defined by the EXIF specification
+const (
+	ExposureAuto ExposureMode = iota
+	ExposureManual
+	ExposureAutoBracket
+)
+
+// exposureModeNames are the values printed by exiftool
+var exposureModeNames = map[int]string{
+	int(ExposureAuto):        "Auto",
+	int(ExposureManual):      "Manual",
+	int(ExposureAutoBracket): "Auto bracket",
+}
+
+func (m ExposureMode) String() string {
+	return enumName(exposureModeNames, int(m))
+}
+
+// ExposureProgram is the EXIF exposure program of the camera
+type ExposureProgram int
+
+// ExposureProgram values, as defined by the EXIF specification
+const (
+	ProgramNotDefined ExposureProgram = iota
+	ProgramManual
+	ProgramNormal
+	ProgramAperturePriority
+	ProgramShutterPriority
+	ProgramCreative
+	ProgramAction
+	ProgramPortrait
+	ProgramLandscape
+	ProgramBulb
+)
+
+// exposureProgramNames are the values printed by exiftool
+var exposureProgramNames = map[int]string{
+	int(ProgramNotDefined):       "Not Defined",
+	int(ProgramManual):           "Manual",
+	int(ProgramNormal):           "Program AE",
+	int(ProgramAperturePriority): "Aperture-priority AE",
+	int(ProgramShutterPriority):  "Shutter speed priority AE",
+	int(ProgramCreative):         "Creative (Slow speed)",
+	int(ProgramAction):           "Action (High speed)",
+	int(ProgramPortrait):         "Portrait",
+	int(ProgramLandscape):        "Landscape",
+	int(ProgramBulb):             "Bulb",
+}
+
+func (p ExposureProgram) String() string {
+	return enumName(exposureProgramNames, int(p))
+}
+
+// enumName returns the name of the value v of an enum
+func enumName(names map[int]string, v int) string {
+	if n, found := names[v]; found {
+		return n
+	}
+	return fmt.Sprintf("Unknown (%d)", v)
+}
+
+// parseEnum parses a value of an enum printed by exiftool, either numeric (-n) or not
+func parseEnum(names map[int]string, s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if i, err := strconv.Atoi(s); err == nil {
+		if _, found := names[i]; found {
+			return i, nil
+		}
+		return 0, fmt.Errorf("invalid value (%v)", s)
+	}
+
+	for i, n := range names {
+		if strings.EqualFold(n, s) {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("invalid value (%v)", s)
+}
+
+// Exposure describes how a picture was exposed. Fields are zero when the corresponding
+// tag can't be found.
+type Exposure struct {
+	ShutterSpeed time.Duration
+	// ShutterSpeedAPEX is the APEX time value of ShutterSpeed (Tv = -log2(seconds))
+	ShutterSpeedAPEX float64
+	// Aperture is the f-number
+	Aperture float64
+	ISO      int
+	// Compensation is the exposure compensation, in EV
+	Compensation float64
+	MeteringMode MeteringMode
+	ExposureMode ExposureMode
+	Program      ExposureProgram
+}
+
+// Keys of the exposure tags, from the standard EXIF and XMP tags to their APEX and
+// MakerNotes equivalents. ShutterSpeedValue and ApertureValue are APEX values that
+// exiftool converts to seconds and f-numbers.
+var (
+	exposureShutterKeys      = append(append([]string{}, cameraShutterSpeedKeys...), "EXIF:ShutterSpeedValue", "XMP:ShutterSpeedValue")
+	exposureApertureKeys     = append(append([]string{}, cameraApertureKeys...), "EXIF:ApertureValue", "XMP:ApertureValue")
+	exposureISOKeys          = append(append([]string{}, cameraISOKeys...), "EXIF:ISOSpeed", "EXIF:RecommendedExposureIndex", "EXIF:StandardOutputSensitivity", "XMP:ISOSpeedRatings")
+	exposureCompensationKeys = []string{"EXIF:ExposureCompensation", "MakerNotes:ExposureCompensation", "XMP:ExposureCompensation", "XMP:ExposureBiasValue", "ExposureCompensation"}
+	exposureMeteringKeys     = []string{"EXIF:MeteringMode", "XMP:MeteringMode", "MeteringMode"}
+	exposureModeKeys         = []string{"EXIF:ExposureMode", "XMP:ExposureMode", "ExposureMode"}
+	exposureProgramKeys      = []string{"EXIF:ExposureProgram", "XMP:ExposureProgram", "ExposureProgram"}
+)
+
+// Exposure returns the exposure settings of the picture (shutter speed, aperture and
+// ISO, along with the compensation and the metering and exposure modes), whatever the
+// tags used by the manufacturer. ErrKeyNotFound will be returned if none of them can be
+// found.
+func (fm FileMetadata) Exposure() (Exposure, error) {
+	var ex Exposure
+	found := false
+
+	if s, ok := fm.lookupString(exposureShutterKeys...); ok {
+		secs, err := toFloatFallback(s)
+		if err != nil {
+			return Exposure{}, fmt.Errorf("ExposureTime parsing error: %w", err)
+		}
+		ex.ShutterSpeed, found = secondsToDuration(secs), true
+		if secs > 0 {
+			ex.ShutterSpeedAPEX = -math.Log2(secs)
+		}
+	}
+
+	for _, i := range []struct {
+		keys []string
+		dst  *float64
+		name string
+	}{
+		{exposureApertureKeys, &ex.Aperture, "FNumber"},
+		{exposureCompensationKeys, &ex.Compensation, "ExposureCompensation"},
+	} {
+		s, ok := fm.lookupString(i.keys...)
+		if !ok {
+			continue
+		}
+		f, err := toFloatFallback(s)
+		if err != nil {
+			return Exposure{}, fmt.Errorf("%v parsing error: %w", i.name, err)
+		}
+		*i.dst, found = f, true
+	}
+
+	iso, err := fm.lookupInt(exposureISOKeys...)
+	if err == nil {
+		ex.ISO, found = int(iso), true
+	} else if err != ErrKeyNotFound {
+		return Exposure{}, fmt.Errorf("ISO parsing error: %w", err)
+	}
+
+	for _, i := range []struct {
+		keys  []string
+		names map[int]string
+		set   func(int)
+		name  string
+	}{
+		{exposureMeteringKeys, meteringModeNames, func(v int) { ex.MeteringMode = MeteringMode(v) }, "MeteringMode"},
+		{exposureModeKeys, exposureModeNames, func(v int) { ex.ExposureMode = ExposureMode(v) }, "ExposureMode"},
+		{exposureProgramKeys, exposureProgramNames, func(v int) { ex.Program = ExposureProgram(v) }, "ExposureProgram"},
+	} {
+		s, ok := fm.lookupString(i.keys...)
+		if !ok {
+			continue
+		}
+		v, err := parseEnum(i.names, s)
+		if err != nil {
+			return Exposure{}, fmt.Errorf("%v parsing error: %w", i.name, err)
+		}
+		i.set(v)
+		found = true
+	}
+
+	if !found {
+		return Exposure{}, ErrKeyNotFound
+	}
+
+	return ex, nil
+}
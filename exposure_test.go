@@ -0,0 +1,132 @@
+package exiftool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEnum(t *testing.T) {
+	var tcs = []struct {
+		tcID  string
+		in    string
+		expOk bool
+		expV  int
+	}{
+		{"numeric", "5", true, int(MeteringMultiSegment)},
+		{"name", "Center-weighted average", true, int(MeteringCenterWeightedAverage)},
+		{"nameCase", " multi-SEGMENT ", true, int(MeteringMultiSegment)},
+		{"other", "255", true, int(MeteringOther)},
+		{"invalidNumeric", "42", false, 0},
+		{"invalidName", "Matrix", false, 0},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			v, err := parseEnum(meteringModeNames, tc.in)
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.expV, v)
+			}
+		})
+	}
+}
+
+func TestExposureEnumsString(t *testing.T) {
+	assert.Equal(t, "Spot", MeteringSpot.String())
+	assert.Equal(t, "Unknown (42)", MeteringMode(42).String())
+	assert.Equal(t, "Auto bracket", ExposureAutoBracket.String())
+	assert.Equal(t, "Aperture-priority AE", ProgramAperturePriority.String())
+}
+
+func TestExposure(t *testing.T) {
+	var tcs = []struct {
+		tcID  string
+		in    map[string]FileMetadataValues
+		expOk bool
+		expEx Exposure
+	}{
+		{"exif", map[string]FileMetadataValues{"EXIF": {
+			{"ExposureTime", "1/250"},
+			{"FNumber", float64(1.7)},
+			{"ISO", float64(40)},
+			{"ExposureCompensation", "-2/3"},
+			{"MeteringMode", "Center-weighted average"},
+			{"ExposureMode", "Auto"},
+			{"ExposureProgram", "Program AE"},
+		}}, true, Exposure{
+			ShutterSpeed:     4 * time.Millisecond,
+			ShutterSpeedAPEX: 7.965784284662087,
+			Aperture:         1.7,
+			ISO:              40,
+			Compensation:     -2.0 / 3,
+			MeteringMode:     MeteringCenterWeightedAverage,
+			ExposureMode:     ExposureAuto,
+			Program:          ProgramNormal,
+		}},
+		{"numeric", map[string]FileMetadataValues{"EXIF": {
+			{"ExposureTime", float64(2)},
+			{"FNumber", float64(8)},
+			{"ISO", float64(100)},
+			{"ExposureCompensation", float64(0)},
+			{"MeteringMode", float64(3)},
+			{"ExposureMode", float64(1)},
+			{"ExposureProgram", float64(1)},
+		}}, true, Exposure{
+			ShutterSpeed:     2 * time.Second,
+			ShutterSpeedAPEX: -1,
+			Aperture:         8,
+			ISO:              100,
+			MeteringMode:     MeteringSpot,
+			ExposureMode:     ExposureManual,
+			Program:          ProgramManual,
+		}},
+		{"apex", map[string]FileMetadataValues{"EXIF": {
+			{"ShutterSpeedValue", "1/8"},
+			{"ApertureValue", "4.0"},
+			{"RecommendedExposureIndex", float64(200)},
+		}}, true, Exposure{
+			ShutterSpeed:     125 * time.Millisecond,
+			ShutterSpeedAPEX: 3,
+			Aperture:         4,
+			ISO:              200,
+		}},
+		{"xmp", map[string]FileMetadataValues{"XMP": {
+			{"ExposureTime", "1/60"},
+			{"ISOSpeedRatings", float64(400)},
+			{"ExposureBiasValue", "+1/3"},
+			{"MeteringMode", "Multi-segment"},
+		}}, true, Exposure{
+			ShutterSpeed:     16666667 * time.Nanosecond,
+			ShutterSpeedAPEX: 5.906890595608519,
+			ISO:              400,
+			Compensation:     1.0 / 3,
+			MeteringMode:     MeteringMultiSegment,
+		}},
+		{"invalidShutterSpeed", map[string]FileMetadataValues{"EXIF": {{"ExposureTime", "Bulb"}}}, false, Exposure{}},
+		{"invalidCompensation", map[string]FileMetadataValues{"EXIF": {{"ExposureCompensation", "a lot"}}}, false, Exposure{}},
+		{"invalidISO", map[string]FileMetadataValues{"EXIF": {{"ISO", "Auto"}}}, false, Exposure{}},
+		{"invalidMeteringMode", map[string]FileMetadataValues{"EXIF": {{"MeteringMode", "Matrix"}}}, false, Exposure{}},
+		{"video", map[string]FileMetadataValues{"QuickTime": {{"Duration", "1 s"}}}, false, Exposure{}},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			ex, err := FileMetadata{Groups: tc.in}.Exposure()
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.InDelta(t, tc.expEx.ShutterSpeedAPEX, ex.ShutterSpeedAPEX, 1e-9)
+				assert.InDelta(t, tc.expEx.Compensation, ex.Compensation, 1e-9)
+				ex.ShutterSpeedAPEX, tc.expEx.ShutterSpeedAPEX = 0, 0
+				ex.Compensation, tc.expEx.Compensation = 0, 0
+				assert.Equal(t, tc.expEx, ex)
+			}
+		})
+	}
+
+	_, err := FileMetadata{}.Exposure()
+	assert.Equal(t, ErrKeyNotFound, err)
+}
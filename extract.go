@@ -0,0 +1,283 @@
+package exiftool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ExtractOption is a configuration function applied to a single extraction, overriding
+// the configuration of the Exiftool
+type ExtractOption func(*extractConfig) error
+
+type extractConfig struct {
+	fast       int
+	tags       []string
+	timeout    time.Duration
+	progress   ProgressFunc
+	retry      *RetryPolicy
+	ordered    bool
+	dedup      bool
+	conditions []string
+	batch      int
+	refresh    bool
+}
+
+// extractConfig returns the default configuration of the extractions
+func (e *Exiftool) extractConfig() extractConfig {
+	return extractConfig{fast: e.fast, timeout: e.fileTimeout, retry: e.retry, dedup: e.dedup, batch: 1}
+}
+
+func (e *Exiftool) newExtractConfig(opts []ExtractOption) (extractConfig, error) {
+	cfg := e.extractConfig()
+	for _, opt := range opts {
+		if err := opt(&cfg); err != nil {
+			return cfg, fmt.Errorf("error when configuring extraction: %w", err)
+		}
+	}
+	return cfg, nil
+}
+
+// WithFast overrides the level set with Fast for a single extraction, level 0 disabling
+// it
+// Sample :
+//   fms := e.Extract(files, WithFast(3))
+func WithFast(level int) ExtractOption {
+	return func(c *extractConfig) error {
+		if err := checkFastLevel(level); err != nil {
+			return err
+		}
+		c.fast = level
+		return nil
+	}
+}
+
+// refreshCache makes the extraction ignore the cached extractions, its results being
+// cached as usual
+func refreshCache() ExtractOption {
+	return func(c *extractConfig) error {
+		c.refresh = true
+		return nil
+	}
+}
+
+// WithTags only extracts tags (-TAG) instead of every tag, drastically reducing the
+// output size when only a few values are needed. Tags can be prefixed by a group, and
+// wildcards are supported by exiftool (ie. "GPS:all", "*Date*"). Calling it several times
+// adds up the tags.
+// Sample :
+//   fms := e.Extract(files, WithTags("EXIF:DateTimeOriginal", "ImageWidth"))
+func WithTags(tags ...string) ExtractOption {
+	return func(c *extractConfig) error {
+		if len(tags) == 0 {
+			return fmt.Errorf("no tag provided")
+		}
+		for _, t := range tags {
+			if t == "" || strings.ContainsAny(t, "\r\n") {
+				return fmt.Errorf("invalid tag (%q)", t)
+			}
+		}
+		c.tags = append(append([]string{}, c.tags...), tags...)
+		return nil
+	}
+}
+
+// WithTimeout overrides the timeout set with FileTimeout for a single extraction, 0
+// disabling it
+// Sample :
+//   fms := e.Extract(files, WithTimeout(10*time.Second))
+func WithTimeout(d time.Duration) ExtractOption {
+	return func(c *extractConfig) error {
+		if d < 0 {
+			return fmt.Errorf("invalid timeout (%v)", d)
+		}
+		c.timeout = d
+		return nil
+	}
+}
+
+// PreserveOrder makes the streaming extractions (Pool.ExtractAll) send the results in
+// the order of the files instead of as they complete, at most concurrency results
+// being kept while waiting for a slower file. The other extractions always return the
+// results in the order of the files.
+// Sample :
+//   for fm := range p.ExtractAll(files, 4, PreserveOrder()) {
+//     ...
+//   }
+func PreserveOrder() ExtractOption {
+	return func(c *extractConfig) error {
+		c.ordered = true
+		return nil
+	}
+}
+
+func checkFastLevel(level int) error {
+	if level < 0 || level > 5 {
+		return fmt.Errorf("invalid fast level (%v)", level)
+	}
+	return nil
+}
+
+func fastArg(level int) string {
+	if level == 1 {
+		return "-fast"
+	}
+	return fmt.Sprintf("-fast%v", level)
+}
+
+// Extract extracts metadata from files, configured by opts. If anything went wrong with
+// opts, every FileMetadata gets the error.
+func (e *Exiftool) Extract(files []string, opts ...ExtractOption) []FileMetadata {
+	return e.ExtractContext(context.Background(), files, opts...)
+}
+
+// ExtractContext behaves like Extract but aborts when ctx is done, see
+// ExtractMetadataContext.
+func (e *Exiftool) ExtractContext(ctx context.Context, files []string, opts ...ExtractOption) []FileMetadata {
+	fms := make([]FileMetadata, len(files))
+
+	cfg, err := e.newExtractConfig(opts)
+	if err != nil {
+		for i, f := range files {
+			fms[i] = FileMetadata{File: f, Err: err, Index: i}
+		}
+		return fms
+	}
+
+	prog := newProgress(cfg.progress, len(files))
+
+	var first []int
+	if cfg.dedup {
+		first = firstOccurrences(files)
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	// the files are extracted by chunks of cfg.batch files (one by one by default)
+	var chunk []int
+	flush := func() {
+		if len(chunk) == 0 {
+			return
+		}
+		cfiles := make([]string, len(chunk))
+		for j, i := range chunk {
+			cfiles[j] = files[i]
+		}
+
+		start := time.Now()
+		keys, hashed := e.hashCacheKeys(cfg, cfiles)
+		hashes := e.startHashing(cfiles, hashed)
+		cfms := e.extractChunk(ctx, cfg, cfiles)
+		fhs := hashes()
+		d := time.Since(start) / time.Duration(len(chunk))
+		for j, i := range chunk {
+			fms[i] = cfms[j]
+			attachHashes(&fms[i], fhs[j])
+			e.cacheHashes(keys[j], fms[i], fhs[j])
+			fms[i].Index = i
+			e.observeExtraction(fms[i], d)
+			prog.done(files[i])
+		}
+		chunk = chunk[:0]
+	}
+
+	for i, f := range files {
+		if first != nil && first[i] != i {
+			if len(chunk) > 0 && first[i] >= chunk[0] {
+				flush()
+			}
+			fms[i] = fms[first[i]]
+			fms[i].Index = i
+			atomic.AddInt64(&e.dedups, 1)
+			prog.done(f)
+			continue
+		}
+
+		chunk = append(chunk, i)
+		if len(chunk) >= cfg.batch {
+			flush()
+		}
+	}
+	flush()
+
+	return fms
+}
+
+// extractFile extracts f configured by cfg. The caller must hold e.lock.
+func (e *Exiftool) extractFile(ctx context.Context, cfg extractConfig, f string) FileMetadata {
+	fm := FileMetadata{File: f}
+
+	if err := ctx.Err(); err != nil {
+		fm.Err = err
+		return fm
+	}
+
+	if err := e.checkFile(f); err != nil {
+		fm.Err = err
+		return fm
+	}
+
+	args := e.extractArgs(cfg)
+
+	var key CacheKey
+	if e.cache != nil {
+		var err error
+		if key, err = cacheKey(e.fileSystem(), f, append(append([]string{}, e.extraInitArgs...), args...)); err == nil {
+			if cached, found := e.cache.Get(key); found && !cfg.refresh {
+				return cached
+			}
+		}
+	}
+
+	args = append(args, f)
+
+	out, err := e.executeFile(ctx, cfg.timeout, f, args...)
+	if err != nil {
+		fm.Err = err
+		return fm
+	}
+	if len(cfg.conditions) > 0 && failedCondition(out) {
+		fm.Err = ErrConditionNotMet
+		return fm
+	}
+
+	out, messages := splitMessages(out)
+	e.decode(&fm, out, messages)
+
+	if e.cache != nil && key.File != "" && fm.Err == nil {
+		e.cache.Set(key, fm)
+	}
+
+	return fm
+}
+
+// executeFile behaves like executeContext, the command being aborted if it takes longer
+// than timeout (if > 0). In that case a FileTimeoutError is returned and, if the exiftool
+// process had to be killed, a new one is started for the next commands. The caller must hold e.lock.
+func (e *Exiftool) executeFile(ctx context.Context, timeout time.Duration, f string, args ...string) ([]byte, error) {
+	if timeout <= 0 {
+		return e.executeContext(ctx, args...)
+	}
+
+	fctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	out, err := e.executeContext(fctx, args...)
+	if err == nil || ctx.Err() != nil || fctx.Err() != context.DeadlineExceeded {
+		return out, err
+	}
+
+	if !e.alive() && !e.stopping() {
+		tries := e.restartTries
+		if tries < 1 {
+			tries = 1
+		}
+		// a failure is reported by the next commands
+		e.respawn(tries)
+	}
+
+	return nil, &FileTimeoutError{File: f, Timeout: timeout}
+}
@@ -0,0 +1,148 @@
+package exiftool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFast(t *testing.T) {
+	e := Exiftool{}
+	assert.NotNil(t, Fast(-1)(&e))
+	assert.NotNil(t, Fast(6)(&e))
+	assert.Nil(t, Fast(2)(&e))
+	assert.Equal(t, 2, e.fast)
+}
+
+func TestWithFast(t *testing.T) {
+	var tcs = []struct {
+		tcID    string
+		inFast  int
+		inOpts  []ExtractOption
+		expOk   bool
+		expArgs []string
+	}{
+		{"none", 0, nil, true, []string{"-j", "-g"}},
+		{"default", 1, nil, true, []string{"-j", "-g", "-fast"}},
+		{"override", 1, []ExtractOption{WithFast(3)}, true, []string{"-j", "-g", "-fast3"}},
+		{"disable", 2, []ExtractOption{WithFast(0)}, true, []string{"-j", "-g"}},
+		{"invalid", 0, []ExtractOption{WithFast(6)}, false, nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			e := Exiftool{fast: tc.inFast}
+			cfg, err := e.newExtractConfig(tc.inOpts)
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.expArgs, e.extractArgs(cfg))
+			}
+		})
+	}
+}
+
+func TestExtractOptionKo(t *testing.T) {
+	e := Exiftool{}
+	fms := e.Extract([]string{"a.jpg", "b.jpg"}, WithFast(-1))
+	assert.Equal(t, 2, len(fms))
+	for i, fm := range fms {
+		assert.NotNil(t, fm.Err)
+		assert.Equal(t, i, fm.Index)
+	}
+	assert.Equal(t, "b.jpg", fms[1].File)
+}
+
+func TestNewExifTool_WithFast(t *testing.T) {
+	e, err := NewExiftool(Fast(1))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	for _, opts := range [][]ExtractOption{nil, {WithFast(2)}, {WithFast(0)}} {
+		fms := e.Extract([]string{"./testdata/20190404_131804.jpg"}, opts...)
+		assert.Equal(t, 1, len(fms))
+		assert.Nil(t, fms[0].Err)
+		mk, err := fms[0].Groups["EXIF"].GetString("Make")
+		assert.Nil(t, err)
+		assert.Equal(t, "samsung", mk)
+	}
+}
+
+func TestWithTags(t *testing.T) {
+	var tcs = []struct {
+		tcID    string
+		inOpts  []ExtractOption
+		expOk   bool
+		expArgs []string
+	}{
+		{"mono", []ExtractOption{WithTags("ImageWidth")}, true, []string{"-j", "-g", "-ImageWidth"}},
+		{"multi", []ExtractOption{WithTags("EXIF:DateTimeOriginal", "ImageWidth")}, true, []string{"-j", "-g", "-EXIF:DateTimeOriginal", "-ImageWidth"}},
+		{"cumulative", []ExtractOption{WithTags("a"), WithTags("b"), WithFast(1)}, true, []string{"-j", "-g", "-fast", "-a", "-b"}},
+		{"none", []ExtractOption{WithTags()}, false, nil},
+		{"empty", []ExtractOption{WithTags("")}, false, nil},
+		{"lineBreak", []ExtractOption{WithTags("a\nb")}, false, nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			e := Exiftool{}
+			cfg, err := e.newExtractConfig(tc.inOpts)
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.expArgs, e.extractArgs(cfg))
+			}
+		})
+	}
+}
+
+func TestNewExifTool_WithTags(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	fms := e.Extract([]string{"./testdata/20190404_131804.jpg"}, WithTags("EXIF:Make"))
+	assert.Equal(t, 1, len(fms))
+	assert.Nil(t, fms[0].Err)
+	assert.Equal(t, map[string]FileMetadataValues{"EXIF": {{"Make", "samsung"}}}, fms[0].Groups)
+}
+
+func TestWithTimeout(t *testing.T) {
+	e := Exiftool{}
+	assert.NotNil(t, FileTimeout(-time.Second)(&e))
+	assert.Nil(t, FileTimeout(time.Second)(&e))
+	assert.Equal(t, time.Second, e.fileTimeout)
+
+	cfg, err := e.newExtractConfig(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Second, cfg.timeout)
+	cfg, err = e.newExtractConfig([]ExtractOption{WithTimeout(0)})
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(0), cfg.timeout)
+	_, err = e.newExtractConfig([]ExtractOption{WithTimeout(-time.Second)})
+	assert.NotNil(t, err)
+}
+
+func TestNewExifTool_WithFileTimeout(t *testing.T) {
+	e, err := NewExiftool(FileTimeout(time.Nanosecond))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	files := []string{"./testdata/20190404_131804.jpg", "./testdata/20190404_131804.jpg"}
+	fms := e.Extract(files)
+	assert.Equal(t, 2, len(fms))
+	for _, fm := range fms {
+		var te *FileTimeoutError
+		assert.True(t, errors.As(fm.Err, &te))
+		assert.True(t, errors.Is(fm.Err, context.DeadlineExceeded))
+	}
+
+	fms = e.Extract(files, WithTimeout(0))
+	assert.Equal(t, 2, len(fms))
+	for _, fm := range fms {
+		assert.Nil(t, fm.Err)
+	}
+}
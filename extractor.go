@@ -0,0 +1,17 @@
+package exiftool
+
+import "context"
+
+// Extractor is implemented by Exiftool, so that the code relying on it can be tested
+// with fakes instead of an exiftool binary (see the exiftooltest package)
+type Extractor interface {
+	ExtractMetadata(files ...string) []FileMetadata
+	ExtractMetadataContext(ctx context.Context, files ...string) []FileMetadata
+	Extract(files []string, opts ...ExtractOption) []FileMetadata
+	ExtractContext(ctx context.Context, files []string, opts ...ExtractOption) []FileMetadata
+	Write(file string, values FileMetadataValues) error
+	Delete(file string, tags ...string) error
+	Close() error
+}
+
+var _ Extractor = (*Exiftool)(nil)
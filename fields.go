@@ -0,0 +1,124 @@
+package exiftool
+
+import "sort"
+
+// CollisionPolicy defines how FieldsWith merges a label found in several groups
+type CollisionPolicy int
+
+const (
+	// KeepFirst keeps the value of the first group, in alphabetical order
+	KeepFirst CollisionPolicy = iota
+	// KeepLast keeps the value of the last group, in alphabetical order
+	KeepLast
+	// QualifyCollisions keys every colliding value by "GROUP:LABEL", labels found in a
+	// single group being keyed by "LABEL"
+	QualifyCollisions
+)
+
+// Fields returns the values of every group merged into a single map keyed by label, the
+// first group (in alphabetical order) winning when a label is found in several groups,
+// see FieldsWith
+func (fm FileMetadata) Fields() map[string]interface{} {
+	return fm.FieldsWith(KeepFirst)
+}
+
+// FieldsWith returns the values of every group merged into a single map keyed by label,
+// p defining how labels found in several groups are merged. When a label appears several
+// times in a group, the first value is kept.
+// Sample :
+//   fields := fm.FieldsWith(QualifyCollisions)
+func (fm FileMetadata) FieldsWith(p CollisionPolicy) map[string]interface{} {
+	names := fm.groupNames()
+
+	groups := map[string][]string{}
+	for _, n := range names {
+		seen := map[string]bool{}
+		for _, f := range fm.Groups[n] {
+			if !seen[f.Label] {
+				seen[f.Label] = true
+				groups[f.Label] = append(groups[f.Label], n)
+			}
+		}
+	}
+
+	res := make(map[string]interface{}, len(groups))
+	for label, gs := range groups {
+		switch {
+		case len(gs) > 1 && p == QualifyCollisions:
+			for _, n := range gs {
+				v, _ := fm.Groups[n].field(label)
+				res[n+":"+label] = v
+			}
+		case p == KeepLast:
+			res[label], _ = fm.Groups[gs[len(gs)-1]].field(label)
+		default:
+			res[label], _ = fm.Groups[gs[0]].field(label)
+		}
+	}
+
+	return res
+}
+
+// AllKeys returns the "GROUP:LABEL" keys of every value, sorted alphabetically
+func (fm FileMetadata) AllKeys() []string {
+	var keys []string
+	for n, g := range fm.Groups {
+		seen := map[string]bool{}
+		for _, f := range g {
+			if !seen[f.Label] {
+				seen[f.Label] = true
+				keys = append(keys, n+":"+f.Label)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Walk calls fn with every value of every group, in the order printed by exiftool (see
+// FileMetadata.GroupOrder), the groups missing from GroupOrder being walked last in
+// alphabetical order. Walking stops as soon as fn returns false.
+// Sample :
+//   fm.Walk(func(group, label string, value interface{}) bool {
+//     fmt.Printf("%v:%v = %v\n", group, label, value)
+//     return true
+//   })
+func (fm FileMetadata) Walk(fn func(group, label string, value interface{}) bool) {
+	for _, n := range fm.orderedGroupNames() {
+		for _, v := range fm.Groups[n] {
+			if !fn(n, v.Label, v.Value) {
+				return
+			}
+		}
+	}
+}
+
+// orderedGroupNames returns the names of the groups in the order of GroupOrder, followed
+// by the ones missing from it sorted alphabetically
+func (fm FileMetadata) orderedGroupNames() []string {
+	names := make([]string, 0, len(fm.Groups))
+	seen := map[string]bool{}
+	for _, n := range fm.GroupOrder {
+		if _, found := fm.Groups[n]; found && !seen[n] {
+			seen[n] = true
+			names = append(names, n)
+		}
+	}
+	for _, n := range fm.groupNames() {
+		if !seen[n] {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+// groupNames returns the names of the groups, sorted alphabetically
+func (fm FileMetadata) groupNames() []string {
+	names := make([]string, 0, len(fm.Groups))
+	for n := range fm.Groups {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,97 @@
+package exiftool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getCollidingFileMetadata() FileMetadata {
+	return FileMetadata{Groups: map[string]FileMetadataValues{
+		"EXIF":      {{"Make", "samsung"}, {"ISO", float64(100)}, {"ISO", float64(200)}},
+		"MakerNote": {{"Make", "Samsung"}},
+		"File":      {{"FileName", "a.jpg"}},
+	}}
+}
+
+func TestFields(t *testing.T) {
+	var tcs = []struct {
+		tcID      string
+		inPolicy  CollisionPolicy
+		expFields map[string]interface{}
+	}{
+		{"first", KeepFirst, map[string]interface{}{"Make": "samsung", "ISO": float64(100), "FileName": "a.jpg"}},
+		{"last", KeepLast, map[string]interface{}{"Make": "Samsung", "ISO": float64(100), "FileName": "a.jpg"}},
+		{"qualify", QualifyCollisions, map[string]interface{}{
+			"EXIF:Make":      "samsung",
+			"MakerNote:Make": "Samsung",
+			"ISO":            float64(100),
+			"FileName":       "a.jpg",
+		}},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			assert.Equal(t, tc.expFields, getCollidingFileMetadata().FieldsWith(tc.inPolicy))
+		})
+	}
+
+	assert.Equal(t, getCollidingFileMetadata().FieldsWith(KeepFirst), getCollidingFileMetadata().Fields())
+	assert.Equal(t, map[string]interface{}{}, FileMetadata{}.Fields())
+}
+
+func TestAllKeys(t *testing.T) {
+	assert.Equal(t, []string{"EXIF:ISO", "EXIF:Make", "File:FileName", "MakerNote:Make"}, getCollidingFileMetadata().AllKeys())
+	assert.Nil(t, FileMetadata{}.AllKeys())
+}
+
+func TestWalk(t *testing.T) {
+	type visit struct {
+		group string
+		label string
+		value interface{}
+	}
+
+	var tcs = []struct {
+		tcID     string
+		inOrder  []string
+		inStopAt int
+		exp      []visit
+	}{
+		{"alphabetical", nil, -1, []visit{
+			{"EXIF", "Make", "samsung"}, {"EXIF", "ISO", float64(100)}, {"EXIF", "ISO", float64(200)},
+			{"File", "FileName", "a.jpg"},
+			{"MakerNote", "Make", "Samsung"},
+		}},
+		{"ordered", []string{"File", "MakerNote", "EXIF"}, -1, []visit{
+			{"File", "FileName", "a.jpg"},
+			{"MakerNote", "Make", "Samsung"},
+			{"EXIF", "Make", "samsung"}, {"EXIF", "ISO", float64(100)}, {"EXIF", "ISO", float64(200)},
+		}},
+		{"partialOrder", []string{"MakerNote", "unknown", "MakerNote"}, -1, []visit{
+			{"MakerNote", "Make", "Samsung"},
+			{"EXIF", "Make", "samsung"}, {"EXIF", "ISO", float64(100)}, {"EXIF", "ISO", float64(200)},
+			{"File", "FileName", "a.jpg"},
+		}},
+		{"stop", []string{"File", "MakerNote", "EXIF"}, 2, []visit{
+			{"File", "FileName", "a.jpg"},
+			{"MakerNote", "Make", "Samsung"},
+		}},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			fm := getCollidingFileMetadata()
+			fm.GroupOrder = tc.inOrder
+
+			var visits []visit
+			fm.Walk(func(group, label string, value interface{}) bool {
+				visits = append(visits, visit{group, label, value})
+				return len(visits) != tc.inStopAt
+			})
+			assert.Equal(t, tc.exp, visits)
+		})
+	}
+}
@@ -2,10 +2,16 @@ package exiftool
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"math/big"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
 )
 
 const (
@@ -14,9 +20,28 @@ const (
 	defaultInt    = int64(0)
 )
 
+// numberWithUnitRegexp matches a print converted number followed by its unit (ie.
+// "4.2 mm", "1/250 s")
+var numberWithUnitRegexp = regexp.MustCompile(`^\s*([-+]?\d+(?:\.\d+)?(?:/\d+)?)\s+[^\d\s]`)
+
 // ErrKeyNotFound is a sentinel error used when a queried key does not exist
 var ErrKeyNotFound = errors.New("key not found")
 
+// dateLayouts lists the date formats printed by exiftool, from the most to the least
+// precise. Subseconds are handled by time.Parse even if the layout does not mention them.
+var dateLayouts = []string{
+	"2006:01:02 15:04:05Z07:00",
+	"2006:01:02 15:04:05Z0700",
+	"2006:01:02 15:04:05",
+	"2006:01:02 15:04Z07:00",
+	"2006:01:02 15:04",
+	"2006:01:02",
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
 // FileMetadataValue ...
 type FileMetadataValue struct {
 	Label string
@@ -28,14 +53,96 @@ type FileMetadataValues []FileMetadataValue
 
 // FileMetadata is a structure that represents an exiftool extraction. File contains the
 // filename that had to be extracted. If anything went wrong, Err will not be nil. Fields
-// stores extracted fields.
+// stores extracted fields. GroupFamilies lists the group families used to build the
+// keys of Groups. Warnings contains the warnings reported by exiftool, even if the
+// extraction succeeded. Raw contains the JSON object printed by exiftool when the
+// KeepRawJSON option is used. NumericValues is true when values were extracted without
+// print conversion (NoPrintConversion option), ie. 0.004 instead of "1/250". DateLayout
+// is the Go layout of the dates provided to the DateFormat option. GroupOrder lists the
+// keys of Groups in the order printed by exiftool. Index is the position of File in the
+// list of files of the batch extraction, or in the walk of a directory. Hashes contains
+// the hexadecimal hashes of the file indexed by algorithm name when the ComputeHashes
+// option is used.
 type FileMetadata struct {
-	File   string
-	Groups map[string]FileMetadataValues
-	Err    error
+	File          string
+	Groups        map[string]FileMetadataValues
+	Err           error
+	GroupFamilies []int
+	Warnings      []string
+	Raw           json.RawMessage
+	NumericValues bool
+	DateLayout    string
+	GroupOrder    []string
+	Index         int
+	Hashes        map[string]string
+}
+
+const warningPrefix = "Warning:"
+const sourceFileLabel = "SourceFile"
+
+// collectWarnings returns the warnings printed by exiftool on stderr (messages) and the
+// ones stored in the ExifTool:Warning tag, without duplicates
+func (fm FileMetadata) collectWarnings(messages []string) []string {
+	var ws []string
+	seen := map[string]bool{}
+	add := func(w string) {
+		w = strings.TrimSpace(strings.TrimSuffix(w, " - "+fm.File))
+		if w != "" && !seen[w] {
+			seen[w] = true
+			ws = append(ws, w)
+		}
+	}
+
+	for _, m := range messages {
+		if strings.HasPrefix(m, warningPrefix) {
+			add(strings.TrimPrefix(m, warningPrefix))
+		}
+	}
+
+	if g, label, found := fm.lookup("ExifTool:Warning"); found {
+		vs, _ := g.GetStrings(label)
+		for _, w := range vs {
+			add(w)
+		}
+	}
+
+	return ws
 }
 
-// UnmarshalJSON decodes the JSON encoding of FileMetadataValues.
+// exiftoolError returns the typed error matching the error reported by exiftool in the
+// ExifTool:Error tag, if any
+func (fm FileMetadata) exiftoolError() error {
+	g, label, found := fm.lookup("ExifTool:Error")
+	if !found {
+		return nil
+	}
+	msg, _ := g.GetString(label)
+	if err, ok := exiftoolError(fm.File, msg).(*UnsupportedFormatError); ok {
+		return err
+	}
+	return nil
+}
+
+// GroupComponents splits a group key of Groups into its components, indexed by group
+// family (ie. {0: "EXIF", 1: "IFD0"} for "EXIF:IFD0" extracted with families 0 and 1).
+// Nil is returned if key does not match GroupFamilies.
+func (fm FileMetadata) GroupComponents(key string) map[int]string {
+	parts := strings.Split(key, ":")
+	if len(parts) != len(fm.GroupFamilies) {
+		return nil
+	}
+
+	res := make(map[int]string, len(parts))
+	for i, f := range fm.GroupFamilies {
+		res[f] = parts[i]
+	}
+
+	return res
+}
+
+// UnmarshalJSON decodes the JSON encoding of FileMetadataValues. Every JSON value is
+// supported: strings, numbers (float64), booleans, null (nil), arrays ([]interface{})
+// and objects (nested FileMetadataValues, ie. with exiftool's -struct parameter).
 func (g *FileMetadataValues) UnmarshalJSON(data []byte) error {
 	l := len(data)
 	if l == 0 || l <= 2 {
@@ -48,53 +155,168 @@ func (g *FileMetadataValues) UnmarshalJSON(data []byte) error {
 	} else if t != json.Delim('{') {
 		return errors.New("expected {")
 	}
+	vs, err := decodeObject(dec)
+	if err != nil {
+		return err
+	}
+	*g = append(*g, vs...)
+	return nil
+}
+
+// objectKeys returns the keys of the JSON object data, in order
+func objectKeys(data []byte) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil {
+		return nil, err
+	} else if t != json.Delim('{') {
+		return nil, errors.New("expected {")
+	}
+
+	var keys []string
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("read key: %w", err)
+		}
+		k, ok := t.(string)
+		if !ok {
+			return nil, errors.New("expected string")
+		}
+		var v json.RawMessage
+		if err := dec.Decode(&v); err != nil {
+			return nil, fmt.Errorf("read value of %v: %w", k, err)
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// decodeObject decodes the members of a JSON object whose opening delimiter has already
+// been read
+func decodeObject(dec *json.Decoder) (FileMetadataValues, error) {
+	vs := FileMetadataValues{}
 	for {
 		var l string
 		if t, err := dec.Token(); err != nil {
-			return fmt.Errorf("read label: %w", err)
+			return nil, fmt.Errorf("read label: %w", err)
 		} else if t == json.Delim('}') {
 			break
 		} else if s, ok := t.(string); ok {
 			l = s
 		} else {
-			return errors.New("expected string")
+			return nil, errors.New("expected string")
 		}
-		var v interface{}
-		if t, err := dec.Token(); err != nil {
-			return fmt.Errorf("read value: %w", err)
-		} else if t == json.Delim('[') {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("read value: %w", err)
+		}
+		v, err := decodeValue(dec, t)
+		if err != nil {
+			return nil, fmt.Errorf("read value of %v: %w", l, err)
+		}
+		vs = append(vs, FileMetadataValue{l, v})
+	}
+	return vs, nil
+}
+
+// decodeValue decodes the JSON value starting with token t
+func decodeValue(dec *json.Decoder, t json.Token) (interface{}, error) {
+	switch t := t.(type) {
+	case json.Delim:
+		switch t {
+		case '[':
 			a := []interface{}{}
 			for {
-				// TODO(bg): Support all types
-				if t, err := dec.Token(); err != nil {
-					return fmt.Errorf("read array value: %w", err)
+				t, err := dec.Token()
+				if err != nil {
+					return nil, fmt.Errorf("read array value: %w", err)
 				} else if t == json.Delim(']') {
 					break
-				} else if s, ok := t.(string); ok {
-					a = append(a, s)
 				}
+				v, err := decodeValue(dec, t)
+				if err != nil {
+					return nil, err
+				}
+				a = append(a, v)
 			}
-			v = a
-		} else if s, ok := t.(bool); ok {
-			v = s
-		} else if s, ok := t.(float64); ok {
-			v = s
-		} else if s, ok := t.(json.Number); ok {
-			if f, err := s.Float64(); err == nil {
-				v = f
-			} else if i, err := s.Int64(); err == nil {
-				v = i
-			} else {
-				v = s.String()
-			}
-		} else if s, ok := t.(string); ok {
-			v = s
-		} else {
-			return fmt.Errorf("unexpected token %v", t)
+			return a, nil
+		case '{':
+			return decodeObject(dec)
+		default:
+			return nil, fmt.Errorf("unexpected delimiter %v", t)
 		}
-		*g = append(*g, FileMetadataValue{l, v})
+	case bool, float64, string, nil:
+		return t, nil
+	case json.Number:
+		if f, err := t.Float64(); err == nil {
+			return f, nil
+		} else if i, err := t.Int64(); err == nil {
+			return i, nil
+		}
+		return t.String(), nil
+	default:
+		return nil, fmt.Errorf("unexpected token %v", t)
 	}
-	return nil
+}
+
+// MarshalJSON encodes FileMetadataValues as a JSON object, keeping the order of the
+// values, like exiftool does.
+func (g FileMetadataValues) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range g {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		l, err := json.Marshal(f.Label)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(f.Value)
+		if err != nil {
+			return nil, fmt.Errorf("error while marshaling %v: %w", f.Label, err)
+		}
+		buf.Write(l)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// MarshalJSON encodes FileMetadata like exiftool does for a file with its '-j -g'
+// parameters: File is encoded as SourceFile and groups are sorted by name. Err,
+// GroupFamilies, Warnings, Raw, NumericValues, DateLayout, GroupOrder and Hashes are not
+// encoded. A []FileMetadata is hence encoded like the whole exiftool output.
+func (fm FileMetadata) MarshalJSON() ([]byte, error) {
+	names := fm.groupNames()
+
+	g := make(FileMetadataValues, 0, len(names)+1)
+	g = append(g, FileMetadataValue{sourceFileLabel, fm.File})
+	for _, n := range names {
+		g = append(g, FileMetadataValue{n, fm.Groups[n]})
+	}
+
+	return g.MarshalJSON()
+}
+
+// UnmarshalJSON decodes a JSON object printed by exiftool for a file with its '-j -g'
+// parameters, or encoded by FileMetadata.MarshalJSON.
+func (fm *FileMetadata) UnmarshalJSON(data []byte) error {
+	var sf struct {
+		SourceFile string
+	}
+	if err := json.Unmarshal(data, &sf); err != nil {
+		return err
+	}
+	fm.File = sf.SourceFile
+
+	return decodeGroups(data, fm)
+}
+
+// fielder is implemented by the field containers supporting the typed getters
+type fielder interface {
+	field(k string) (interface{}, bool)
 }
 
 func (g FileMetadataValues) field(k string) (interface{}, bool) {
@@ -109,6 +331,10 @@ func (g FileMetadataValues) field(k string) (interface{}, bool) {
 // GetString returns a field value as string and an error if one occurred.
 // KeyNotFoundError will be returned if the key can't be found
 func (g FileMetadataValues) GetString(k string) (string, error) {
+	return getString(g, k)
+}
+
+func getString(g fielder, k string) (string, error) {
 	v, found := g.field(k)
 	if !found {
 		return defaultString, ErrKeyNotFound
@@ -133,6 +359,10 @@ func toString(v interface{}) string {
 // GetFloat returns a field value as float64 and an error if one occurred.
 // KeyNotFoundError will be returned if the key can't be found.
 func (g FileMetadataValues) GetFloat(k string) (float64, error) {
+	return getFloat(g, k)
+}
+
+func getFloat(g fielder, k string) (float64, error) {
 	v, found := g.field(k)
 	if !found {
 		return defaultFloat, ErrKeyNotFound
@@ -151,19 +381,34 @@ func (g FileMetadataValues) GetFloat(k string) (float64, error) {
 	}
 }
 
+// toFloatFallback parses str, which can also be a print converted value: a rational
+// ("1/250") or a number followed by a unit ("4.2 mm")
 func toFloatFallback(str string) (float64, error) {
 	f, err := strconv.ParseFloat(str, -1)
-	if err != nil {
-		return defaultFloat, fmt.Errorf("float64 parsing error (%v): %w", str, err)
+	if err == nil {
+		return f, nil
 	}
 
-	return f, nil
+	n := str
+	if m := numberWithUnitRegexp.FindStringSubmatch(str); m != nil {
+		n = m[1]
+	}
+	if r, ok := new(big.Rat).SetString(n); ok {
+		f, _ := r.Float64()
+		return f, nil
+	}
+
+	return defaultFloat, fmt.Errorf("float64 parsing error (%v): %w", str, err)
 }
 
 // GetInt returns a field value as int64 and an error if one occurred.
 // KeyNotFoundError will be returned if the key can't be found, ParseError if
 // a parsing error occurs.
 func (g FileMetadataValues) GetInt(k string) (int64, error) {
+	return getInt(g, k)
+}
+
+func getInt(g fielder, k string) (int64, error) {
 	v, found := g.field(k)
 	if !found {
 		return defaultInt, ErrKeyNotFound
@@ -182,18 +427,69 @@ func (g FileMetadataValues) GetInt(k string) (int64, error) {
 	}
 }
 
+// toIntFallback parses str, which can also be a print converted value (see
+// toFloatFallback) as long as it is an integer ("26 mm", "42.0")
 func toIntFallback(str string) (int64, error) {
-	f, err := strconv.ParseInt(str, 10, 64)
-	if err != nil {
-		return defaultInt, fmt.Errorf("int64 parsing error (%v): %w", str, err)
+	i, err := strconv.ParseInt(str, 10, 64)
+	if err == nil {
+		return i, nil
 	}
 
-	return f, nil
+	if f, ferr := toFloatFallback(str); ferr == nil && f == math.Trunc(f) {
+		return int64(f), nil
+	}
+
+	return defaultInt, fmt.Errorf("int64 parsing error (%v): %w", str, err)
+}
+
+// GetBool returns a field value as bool and an error if one occurred. The boolean-ish
+// values printed by exiftool are supported, case insensitively: "True"/"False",
+// "Yes"/"No", "On"/"Off" and numbers (0 being false). KeyNotFoundError will be returned
+// if the key can't be found.
+func (g FileMetadataValues) GetBool(k string) (bool, error) {
+	return getBool(g, k)
+}
+
+func getBool(g fielder, k string) (bool, error) {
+	v, found := g.field(k)
+	if !found {
+		return false, ErrKeyNotFound
+	}
+
+	switch v := v.(type) {
+	case bool:
+		return v, nil
+	case float64:
+		return v != 0, nil
+	case int64:
+		return v != 0, nil
+	default:
+		return toBool(toString(v))
+	}
+}
+
+func toBool(str string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(str)) {
+	case "true", "yes", "on":
+		return true, nil
+	case "false", "no", "off":
+		return false, nil
+	}
+
+	if f, err := strconv.ParseFloat(strings.TrimSpace(str), 64); err == nil {
+		return f != 0, nil
+	}
+
+	return false, fmt.Errorf("bool parsing error (%v)", str)
 }
 
 // GetStrings returns a field value as []string and an error if one occurred.
 // KeyNotFoundError will be returned if the key can't be found.
 func (g FileMetadataValues) GetStrings(k string) ([]string, error) {
+	return getStrings(g, k)
+}
+
+func getStrings(g fielder, k string) ([]string, error) {
 	v, found := g.field(k)
 	if !found {
 		return []string{}, ErrKeyNotFound
@@ -213,3 +509,281 @@ func (g FileMetadataValues) GetStrings(k string) ([]string, error) {
 		return []string{toString(v)}, nil
 	}
 }
+
+// GetInts returns a field value as []int64 and an error if one occurred. Besides lists,
+// numeric array tags printed as a single string of numbers separated by spaces or
+// commas (ie. BitsPerSample "8 8 8") are supported. The error mentions the index of the
+// element that can't be converted. KeyNotFoundError will be returned if the key can't
+// be found.
+func (g FileMetadataValues) GetInts(k string) ([]int64, error) {
+	return getInts(g, k)
+}
+
+func getInts(g fielder, k string) ([]int64, error) {
+	vs, err := numericElements(g, k)
+	if err != nil {
+		return []int64{}, err
+	}
+
+	res := make([]int64, len(vs))
+	for i, v := range vs {
+		if res[i], err = getInt(FileMetadataValues{{Value: v}}, ""); err != nil {
+			return []int64{}, fmt.Errorf("element #%v: %w", i, err)
+		}
+	}
+
+	return res, nil
+}
+
+// GetFloats returns a field value as []float64 and an error if one occurred, see
+// GetInts.
+func (g FileMetadataValues) GetFloats(k string) ([]float64, error) {
+	return getFloats(g, k)
+}
+
+func getFloats(g fielder, k string) ([]float64, error) {
+	vs, err := numericElements(g, k)
+	if err != nil {
+		return []float64{}, err
+	}
+
+	res := make([]float64, len(vs))
+	for i, v := range vs {
+		if res[i], err = getFloat(FileMetadataValues{{Value: v}}, ""); err != nil {
+			return []float64{}, fmt.Errorf("element #%v: %w", i, err)
+		}
+	}
+
+	return res, nil
+}
+
+// numericElements returns the elements of a numeric array field: the items of a list,
+// or the numbers of a string which isn't a single (possibly print converted) number
+func numericElements(g fielder, k string) ([]interface{}, error) {
+	v, found := g.field(k)
+	if !found {
+		return nil, ErrKeyNotFound
+	}
+
+	switch v := v.(type) {
+	case []interface{}:
+		return v, nil
+	case string:
+		if _, err := toFloatFallback(v); err == nil {
+			return []interface{}{v}, nil
+		}
+		fields := strings.FieldsFunc(v, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		res := make([]interface{}, len(fields))
+		for i, f := range fields {
+			res[i] = f
+		}
+		return res, nil
+	default:
+		return []interface{}{v}, nil
+	}
+}
+
+// GetDate returns a field value as time.Time and an error if one occurred.
+// The usual exiftool formats are supported ("2006:01:02 15:04:05" with optional
+// subseconds and timezone offset, date only, RFC3339). Dates without timezone are
+// returned in UTC. KeyNotFoundError will be returned if the key can't be found.
+func (g FileMetadataValues) GetDate(k string) (time.Time, error) {
+	return getDate(g, k)
+}
+
+func getDate(g fielder, k string) (time.Time, error) {
+	v, found := g.field(k)
+	if !found {
+		return time.Time{}, ErrKeyNotFound
+	}
+
+	return toDate(toString(v))
+}
+
+func toDate(str string) (time.Time, error) {
+	str = strings.TrimSpace(str)
+	for _, l := range dateLayouts {
+		if t, err := time.Parse(l, str); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("date parsing error (%v)", str)
+}
+
+// GetDuration returns a field value as time.Duration and an error if one occurred.
+// Numbers are seconds, and the formats printed by exiftool are supported ("12.34 s",
+// "0:02:35", "1:02:03.456", with an optional "(approx)" suffix). KeyNotFoundError will
+// be returned if the key can't be found.
+func (g FileMetadataValues) GetDuration(k string) (time.Duration, error) {
+	return getDuration(g, k)
+}
+
+func getDuration(g fielder, k string) (time.Duration, error) {
+	v, found := g.field(k)
+	if !found {
+		return 0, ErrKeyNotFound
+	}
+
+	switch v := v.(type) {
+	case float64:
+		return secondsToDuration(v), nil
+	case int64:
+		return time.Duration(v) * time.Second, nil
+	default:
+		return parseDuration(toString(v))
+	}
+}
+
+// GetFraction returns a field value as *big.Rat and an error if one occurred. Both
+// rational strings ("1/250") and numbers (0.004) are supported.
+// KeyNotFoundError will be returned if the key can't be found.
+func (g FileMetadataValues) GetFraction(k string) (*big.Rat, error) {
+	return getFraction(g, k)
+}
+
+func getFraction(g fielder, k string) (*big.Rat, error) {
+	v, found := g.field(k)
+	if !found {
+		return nil, ErrKeyNotFound
+	}
+
+	str := strings.TrimSpace(toString(v))
+	r, ok := new(big.Rat).SetString(str)
+	if !ok {
+		return nil, fmt.Errorf("rational parsing error (%v)", str)
+	}
+
+	return r, nil
+}
+
+// GetRational returns a field value as a reduced numerator / denominator pair and an
+// error if one occurred, see GetFraction. KeyNotFoundError will be returned if the key
+// can't be found.
+func (g FileMetadataValues) GetRational(k string) (int64, int64, error) {
+	return getRational(g, k)
+}
+
+func getRational(g fielder, k string) (int64, int64, error) {
+	r, err := getFraction(g, k)
+	if err != nil {
+		return defaultInt, defaultInt, err
+	}
+
+	if !r.Num().IsInt64() || !r.Denom().IsInt64() {
+		return defaultInt, defaultInt, fmt.Errorf("rational overflows int64 (%v)", r)
+	}
+
+	return r.Num().Int64(), r.Denom().Int64(), nil
+}
+
+// GetStruct returns a field value as a structure and an error if one occurred, which
+// requires the Struct option. KeyNotFoundError will be returned if the key can't be
+// found.
+func (g FileMetadataValues) GetStruct(k string) (FileMetadataValues, error) {
+	return getStruct(g, k)
+}
+
+func getStruct(g fielder, k string) (FileMetadataValues, error) {
+	v, found := g.field(k)
+	if !found {
+		return nil, ErrKeyNotFound
+	}
+
+	s, ok := v.(FileMetadataValues)
+	if !ok {
+		return nil, fmt.Errorf("not a structure (%v)", v)
+	}
+
+	return s, nil
+}
+
+// GetStructs returns a field value as a list of structures (ie. XMP RegionList) and an
+// error if one occurred, a single structure being returned as a one item list. It
+// requires the Struct option. KeyNotFoundError will be returned if the key can't be
+// found.
+func (g FileMetadataValues) GetStructs(k string) ([]FileMetadataValues, error) {
+	return getStructs(g, k)
+}
+
+func getStructs(g fielder, k string) ([]FileMetadataValues, error) {
+	v, found := g.field(k)
+	if !found {
+		return nil, ErrKeyNotFound
+	}
+
+	switch v := v.(type) {
+	case FileMetadataValues:
+		return []FileMetadataValues{v}, nil
+	case []interface{}:
+		res := make([]FileMetadataValues, len(v))
+		for i, item := range v {
+			s, ok := item.(FileMetadataValues)
+			if !ok {
+				return nil, fmt.Errorf("not a structure (%v)", item)
+			}
+			res[i] = s
+		}
+		return res, nil
+	default:
+		return nil, fmt.Errorf("not a list of structures (%v)", v)
+	}
+}
+
+// GetBytes returns a field value as []byte and an error if one occurred, the binary
+// values extracted with BinaryValues ("base64:" prefixed) being decoded, the other ones
+// being returned as is. ErrBinaryNotExtracted will be returned if the binary value was
+// not extracted, KeyNotFoundError if the key can't be found.
+func (g FileMetadataValues) GetBytes(k string) ([]byte, error) {
+	return getBytes(g, k)
+}
+
+func getBytes(g fielder, k string) ([]byte, error) {
+	v, found := g.field(k)
+	if !found {
+		return nil, ErrKeyNotFound
+	}
+
+	str := toString(v)
+	if strings.HasPrefix(str, base64Prefix) {
+		b, err := base64.StdEncoding.DecodeString(str[len(base64Prefix):])
+		if err != nil {
+			return nil, fmt.Errorf("base64 decoding error: %w", err)
+		}
+		return b, nil
+	}
+	if binaryPlaceholderRegexp.MatchString(str) {
+		return nil, ErrBinaryNotExtracted
+	}
+
+	return []byte(str), nil
+}
+
+// Map converts g into a map, nested structures being converted into nested maps. When a
+// label appears several times, the first value is kept.
+func (g FileMetadataValues) Map() map[string]interface{} {
+	m := make(map[string]interface{}, len(g))
+	for _, f := range g {
+		if _, found := m[f.Label]; !found {
+			m[f.Label] = toMapValue(f.Value)
+		}
+	}
+	return m
+}
+
+func toMapValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case FileMetadataValues:
+		return v.Map()
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, item := range v {
+			res[i] = toMapValue(item)
+		}
+		return res
+	default:
+		return v
+	}
+}
@@ -1,8 +1,11 @@
 package exiftool
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -20,6 +23,11 @@ func getExpectedFileMetadata() FileMetadata {
 				{"int32", int32(32)},
 				{"float32", float32(32.32)},
 				{"array", []interface{}{"str", float64(64.64), float32(32.32), int64(64), true}},
+				{"rational", "1/250"},
+				{"unit", "4.2 mm"},
+				{"equivalent", "4.2 mm (35 mm equivalent: 26.0 mm)"},
+				{"integerUnit", "26 mm"},
+				{"strIntFloat", "42.0"},
 			},
 		},
 	}
@@ -39,6 +47,11 @@ func TestGetInt(t *testing.T) {
 		{"unexisting", true, ErrKeyNotFound, int64(0)},
 		{"strInt", false, nil, int64(84)},
 		{"int32", false, nil, int64(32)},
+		{"strFloat", true, nil, int64(0)},
+		{"rational", true, nil, int64(0)},
+		{"unit", true, nil, int64(0)},
+		{"integerUnit", false, nil, int64(26)},
+		{"strIntFloat", false, nil, int64(42)},
 	}
 	for _, tc := range tcs {
 		tc := tc // Pin variable
@@ -72,6 +85,9 @@ func TestGetFloat(t *testing.T) {
 		{"unexisting", true, ErrKeyNotFound, float64(0)},
 		{"strFloat", false, nil, float64(6.28)},
 		{"float32", false, nil, float64(32.32)},
+		{"rational", false, nil, float64(0.004)},
+		{"unit", false, nil, float64(4.2)},
+		{"equivalent", false, nil, float64(4.2)},
 	}
 	for _, tc := range tcs {
 		tc := tc // Pin variable
@@ -154,3 +170,519 @@ func TestGetStrings(t *testing.T) {
 		})
 	}
 }
+
+func TestGetInts(t *testing.T) {
+	fm := FileMetadata{
+		Groups: map[string]FileMetadataValues{
+			"fields": {
+				{"list", []interface{}{float64(8), "16", int64(32)}},
+				{"spaces", "8 8 8"},
+				{"commas", "1, 2,3"},
+				{"single", float64(42)},
+				{"unit", "4.2 mm"},
+				{"invalidList", []interface{}{float64(8), "a"}},
+				{"invalid", "8 8 a"},
+			},
+		},
+	}
+
+	tcs := []struct {
+		inKey      string
+		expIsError bool
+		expError   error
+		expVal     []int64
+	}{
+		{"list", false, nil, []int64{8, 16, 32}},
+		{"spaces", false, nil, []int64{8, 8, 8}},
+		{"commas", false, nil, []int64{1, 2, 3}},
+		{"single", false, nil, []int64{42}},
+		{"unit", true, nil, nil},
+		{"invalidList", true, nil, nil},
+		{"invalid", true, nil, nil},
+		{"unexisting", true, ErrKeyNotFound, nil},
+	}
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.inKey, func(t *testing.T) {
+			v, err := fm.Groups["fields"].GetInts(tc.inKey)
+			if tc.expIsError {
+				assert.NotNil(t, err)
+				if tc.expError != nil {
+					assert.True(t, errors.Is(err, tc.expError))
+				}
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, tc.expVal, v)
+			}
+		})
+	}
+
+	_, err := fm.Groups["fields"].GetInts("invalid")
+	assert.Contains(t, err.Error(), "element #2")
+}
+
+func TestGetFloats(t *testing.T) {
+	fm := FileMetadata{
+		Groups: map[string]FileMetadataValues{
+			"fields": {
+				{"list", []interface{}{float64(4.2), "1/2", int64(3)}},
+				{"lensInfo", "4.2 4.2 1.7 1.7"},
+				{"single", "4.2 mm"},
+				{"invalid", []interface{}{"a"}},
+			},
+		},
+	}
+
+	tcs := []struct {
+		inKey      string
+		expIsError bool
+		expError   error
+		expVal     []float64
+	}{
+		{"list", false, nil, []float64{4.2, 0.5, 3}},
+		{"lensInfo", false, nil, []float64{4.2, 4.2, 1.7, 1.7}},
+		{"single", false, nil, []float64{4.2}},
+		{"invalid", true, nil, nil},
+		{"unexisting", true, ErrKeyNotFound, nil},
+	}
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.inKey, func(t *testing.T) {
+			v, err := fm.Groups["fields"].GetFloats(tc.inKey)
+			if tc.expIsError {
+				assert.NotNil(t, err)
+				if tc.expError != nil {
+					assert.True(t, errors.Is(err, tc.expError))
+				}
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, tc.expVal, v)
+			}
+		})
+	}
+}
+
+func TestGetBool(t *testing.T) {
+	fm := FileMetadata{
+		Groups: map[string]FileMetadataValues{
+			"fields": {
+				{"bool", true},
+				{"true", "True"},
+				{"false", "False"},
+				{"yes", "Yes"},
+				{"no", "no"},
+				{"on", "On"},
+				{"off", " OFF "},
+				{"one", "1"},
+				{"zero", "0"},
+				{"float", float64(1)},
+				{"floatZero", float64(0)},
+				{"integer", int64(0)},
+				{"invalid", "maybe"},
+			},
+		},
+	}
+
+	tcs := []struct {
+		inKey      string
+		expIsError bool
+		expError   error
+		expVal     bool
+	}{
+		{"bool", false, nil, true},
+		{"true", false, nil, true},
+		{"false", false, nil, false},
+		{"yes", false, nil, true},
+		{"no", false, nil, false},
+		{"on", false, nil, true},
+		{"off", false, nil, false},
+		{"one", false, nil, true},
+		{"zero", false, nil, false},
+		{"float", false, nil, true},
+		{"floatZero", false, nil, false},
+		{"integer", false, nil, false},
+		{"invalid", true, nil, false},
+		{"unexisting", true, ErrKeyNotFound, false},
+	}
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.inKey, func(t *testing.T) {
+			v, err := fm.Groups["fields"].GetBool(tc.inKey)
+			if tc.expIsError {
+				assert.NotNil(t, err)
+				if tc.expError != nil {
+					assert.True(t, errors.Is(err, tc.expError))
+				}
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, tc.expVal, v)
+			}
+		})
+	}
+}
+
+func TestGetDate(t *testing.T) {
+	fm := FileMetadata{
+		Groups: map[string]FileMetadataValues{
+			"fields": {
+				{"date", "2019:04:04 13:18:03"},
+				{"subsec", "2019:04:04 13:18:03.0937"},
+				{"offset", "2019:04:04 13:18:03+02:00"},
+				{"subsecOffset", "2019:04:04 13:18:03.25-05:30"},
+				{"utc", "2019:04:04 13:18:03Z"},
+				{"dateOnly", "2019:04:04"},
+				{"noSeconds", "2019:04:04 13:18"},
+				{"rfc3339", "2019-04-04T13:18:03+02:00"},
+				{"zero", "0000:00:00 00:00:00"},
+				{"invalid", "invalid"},
+			},
+		},
+	}
+	plus2 := time.FixedZone("", 2*3600)
+
+	tcs := []struct {
+		inKey      string
+		expIsError bool
+		expError   error
+		expVal     time.Time
+	}{
+		{"date", false, nil, time.Date(2019, 4, 4, 13, 18, 3, 0, time.UTC)},
+		{"subsec", false, nil, time.Date(2019, 4, 4, 13, 18, 3, 93700000, time.UTC)},
+		{"offset", false, nil, time.Date(2019, 4, 4, 13, 18, 3, 0, plus2)},
+		{"subsecOffset", false, nil, time.Date(2019, 4, 4, 13, 18, 3, 250000000, time.FixedZone("", -(5*3600+30*60)))},
+		{"utc", false, nil, time.Date(2019, 4, 4, 13, 18, 3, 0, time.UTC)},
+		{"dateOnly", false, nil, time.Date(2019, 4, 4, 0, 0, 0, 0, time.UTC)},
+		{"noSeconds", false, nil, time.Date(2019, 4, 4, 13, 18, 0, 0, time.UTC)},
+		{"rfc3339", false, nil, time.Date(2019, 4, 4, 13, 18, 3, 0, plus2)},
+		{"zero", true, nil, time.Time{}},
+		{"invalid", true, nil, time.Time{}},
+		{"unexisting", true, ErrKeyNotFound, time.Time{}},
+	}
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.inKey, func(t *testing.T) {
+			v, err := fm.Groups["fields"].GetDate(tc.inKey)
+			if tc.expIsError {
+				assert.NotNil(t, err)
+				if tc.expError != nil {
+					assert.True(t, errors.Is(err, tc.expError))
+				}
+			} else {
+				assert.Nil(t, err)
+				assert.True(t, tc.expVal.Equal(v), "expected %v, got %v", tc.expVal, v)
+				_, expOffset := tc.expVal.Zone()
+				_, offset := v.Zone()
+				assert.Equal(t, expOffset, offset)
+			}
+		})
+	}
+}
+
+func TestGetDuration(t *testing.T) {
+	fm := FileMetadata{
+		Groups: map[string]FileMetadataValues{
+			"fields": {
+				{"float", float64(12.5)},
+				{"integer", int64(3)},
+				{"seconds", "12.34 s"},
+				{"minutes", "0:02:35"},
+				{"subsec", "1:02:03.456"},
+				{"approx", "5.12 s (approx)"},
+				{"invalid", "invalid"},
+			},
+		},
+	}
+
+	tcs := []struct {
+		inKey      string
+		expIsError bool
+		expError   error
+		expVal     time.Duration
+	}{
+		{"float", false, nil, 12500 * time.Millisecond},
+		{"integer", false, nil, 3 * time.Second},
+		{"seconds", false, nil, 12340 * time.Millisecond},
+		{"minutes", false, nil, 155 * time.Second},
+		{"subsec", false, nil, time.Hour + 2*time.Minute + 3456*time.Millisecond},
+		{"approx", false, nil, 5120 * time.Millisecond},
+		{"invalid", true, nil, 0},
+		{"unexisting", true, ErrKeyNotFound, 0},
+	}
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.inKey, func(t *testing.T) {
+			v, err := fm.Groups["fields"].GetDuration(tc.inKey)
+			if tc.expIsError {
+				assert.NotNil(t, err)
+				if tc.expError != nil {
+					assert.True(t, errors.Is(err, tc.expError))
+				}
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, tc.expVal, v)
+			}
+		})
+	}
+}
+
+func TestGroupComponents(t *testing.T) {
+	fm := FileMetadata{GroupFamilies: []int{0, 1}}
+	assert.Equal(t, map[int]string{0: "EXIF", 1: "IFD0"}, fm.GroupComponents("EXIF:IFD0"))
+	assert.Nil(t, fm.GroupComponents("EXIF"))
+
+	fm = FileMetadata{GroupFamilies: []int{1}}
+	assert.Equal(t, map[int]string{1: "ExifIFD"}, fm.GroupComponents("ExifIFD"))
+}
+
+func TestCollectWarnings(t *testing.T) {
+	fm := FileMetadata{
+		File: "a.jpg",
+		Groups: map[string]FileMetadataValues{
+			"ExifTool": {{"Warning", "[minor] Bad MakerNotes"}},
+		},
+	}
+	messages := []string{"Warning: Bad IFD0 directory - a.jpg", "Error: e", "Warning: [minor] Bad MakerNotes - a.jpg"}
+	assert.Equal(t, []string{"Bad IFD0 directory", "[minor] Bad MakerNotes"}, fm.collectWarnings(messages))
+
+	fm = FileMetadata{
+		Groups: map[string]FileMetadataValues{
+			"ExifTool:ExifTool": {{"Warning", []interface{}{"w1", "w2"}}},
+		},
+	}
+	assert.Equal(t, []string{"w1", "w2"}, fm.collectWarnings(nil))
+	assert.Nil(t, FileMetadata{}.collectWarnings(nil))
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	var tcs = []struct {
+		tcID   string
+		in     string
+		expOk  bool
+		expVal FileMetadataValues
+	}{
+		{"empty", `{}`, true, nil},
+		{"scalars", `{"s":"str","f":3.14,"b":true,"n":null}`, true, FileMetadataValues{
+			{"s", "str"}, {"f", float64(3.14)}, {"b", true}, {"n", nil},
+		}},
+		{"array", `{"a":["str",1,false,null]}`, true, FileMetadataValues{
+			{"a", []interface{}{"str", float64(1), false, nil}},
+		}},
+		{"nestedArrays", `{"a":[[1,2],["x"]]}`, true, FileMetadataValues{
+			{"a", []interface{}{[]interface{}{float64(1), float64(2)}, []interface{}{"x"}}},
+		}},
+		{"struct", `{"RegionInfo":{"AppliedToDimensions":{"W":4032,"H":3024},"RegionList":[{"Name":"me","Type":"Face"}]}}`, true, FileMetadataValues{
+			{"RegionInfo", FileMetadataValues{
+				{"AppliedToDimensions", FileMetadataValues{{"W", float64(4032)}, {"H", float64(3024)}}},
+				{"RegionList", []interface{}{FileMetadataValues{{"Name", "me"}, {"Type", "Face"}}}},
+			}},
+		}},
+		{"notObject", `["a"]`, false, nil},
+		{"truncated", `{"a":[1,`, false, nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			var g FileMetadataValues
+			err := json.Unmarshal([]byte(tc.in), &g)
+			assert.Equal(t, tc.expOk, err == nil, "unexpected error: %v", err)
+			if tc.expOk {
+				assert.Equal(t, tc.expVal, g)
+			}
+		})
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	fm := FileMetadata{
+		File: "a.jpg",
+		Groups: map[string]FileMetadataValues{
+			"File": {{"FileName", "a.jpg"}, {"FileSize", "26 kB"}},
+			"EXIF": {
+				{"Make", "samsung"},
+				{"ISO", float64(100)},
+				{"Flash", true},
+				{"Empty", nil},
+				{"Keywords", []interface{}{"a", "b"}},
+				{"Region", FileMetadataValues{{"W", float64(1)}, {"H", float64(2)}}},
+			},
+		},
+		Err: fmt.Errorf("not encoded"),
+	}
+
+	b, err := json.Marshal(fm)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"SourceFile":"a.jpg",`+
+		`"EXIF":{"Make":"samsung","ISO":100,"Flash":true,"Empty":null,"Keywords":["a","b"],"Region":{"W":1,"H":2}},`+
+		`"File":{"FileName":"a.jpg","FileSize":"26 kB"}}`, string(b))
+
+	var decoded FileMetadata
+	assert.Nil(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, fm.File, decoded.File)
+	assert.Equal(t, fm.Groups, decoded.Groups)
+	assert.Equal(t, []string{"EXIF", "File"}, decoded.GroupOrder)
+
+	var fms []FileMetadata
+	assert.Nil(t, json.Unmarshal([]byte("["+string(b)+","+string(b)+"]"), &fms))
+	assert.Equal(t, 2, len(fms))
+	assert.Equal(t, fm.Groups, fms[1].Groups)
+
+	assert.NotNil(t, json.Unmarshal([]byte(`"a"`), &decoded))
+	_, err = json.Marshal(FileMetadataValues{{"bad", make(chan int)}})
+	assert.NotNil(t, err)
+}
+
+func TestObjectKeys(t *testing.T) {
+	keys, err := objectKeys([]byte(`{"SourceFile":"a.jpg","File":{"FileName":"a.jpg"},"EXIF":{"Make":"samsung"}}`))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"SourceFile", "File", "EXIF"}, keys)
+
+	keys, err = objectKeys([]byte(`{}`))
+	assert.Nil(t, err)
+	assert.Nil(t, keys)
+
+	for _, in := range []string{``, `[]`, `{1:2}`, `{"a":`} {
+		_, err = objectKeys([]byte(in))
+		assert.NotNil(t, err, "no error for %q", in)
+	}
+}
+
+func TestGetRational(t *testing.T) {
+	g := FileMetadataValues{
+		{"exposure", "1/250"},
+		{"unreduced", "10/40"},
+		{"float", float64(0.004)},
+		{"integer", float64(2)},
+		{"spaces", " 1/8 "},
+		{"zeroDen", "1/0"},
+		{"invalid", "abc"},
+		{"overflow", "1/100000000000000000000"},
+	}
+
+	tcs := []struct {
+		inKey      string
+		expIsError bool
+		expError   error
+		expNum     int64
+		expDen     int64
+	}{
+		{"exposure", false, nil, 1, 250},
+		{"unreduced", false, nil, 1, 4},
+		{"float", false, nil, 1, 250},
+		{"integer", false, nil, 2, 1},
+		{"spaces", false, nil, 1, 8},
+		{"zeroDen", true, nil, 0, 0},
+		{"invalid", true, nil, 0, 0},
+		{"overflow", true, nil, 0, 0},
+		{"unexisting", true, ErrKeyNotFound, 0, 0},
+	}
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.inKey, func(t *testing.T) {
+			num, den, err := g.GetRational(tc.inKey)
+			if tc.expIsError {
+				assert.NotNil(t, err)
+				if tc.expError != nil {
+					assert.True(t, errors.Is(err, tc.expError))
+				}
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, tc.expNum, num)
+				assert.Equal(t, tc.expDen, den)
+			}
+		})
+	}
+
+	r, err := g.GetFraction("overflow")
+	assert.Nil(t, err)
+	assert.Equal(t, "1/100000000000000000000", r.String())
+
+	num, den, err := g.Index().GetRational("exposure")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 250}, []int64{num, den})
+	_, err = g.Index().GetFraction("unexisting")
+	assert.Equal(t, ErrKeyNotFound, err)
+}
+
+func TestGetStruct(t *testing.T) {
+	var g FileMetadataValues
+	err := json.Unmarshal([]byte(`{
+		"RegionInfo": {"AppliedToDimensions": {"W": 4032, "H": 3024}, "RegionList": [{"Name": "a"}, {"Name": "b"}]},
+		"Title": "t",
+		"Mixed": [{"Name": "a"}, "b"]
+	}`), &g)
+	assert.Nil(t, err)
+
+	ri, err := g.GetStruct("RegionInfo")
+	assert.Nil(t, err)
+	dims, err := ri.GetStruct("AppliedToDimensions")
+	assert.Nil(t, err)
+	w, err := dims.GetInt("W")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4032), w)
+
+	regions, err := ri.GetStructs("RegionList")
+	assert.Nil(t, err)
+	assert.Equal(t, []FileMetadataValues{{{"Name", "a"}}, {{"Name", "b"}}}, regions)
+
+	l, err := g.GetStructs("RegionInfo")
+	assert.Nil(t, err)
+	assert.Equal(t, []FileMetadataValues{ri}, l)
+
+	_, err = g.GetStruct("Title")
+	assert.NotNil(t, err)
+	_, err = g.GetStructs("Title")
+	assert.NotNil(t, err)
+	_, err = g.GetStructs("Mixed")
+	assert.NotNil(t, err)
+	_, err = g.GetStruct("unexisting")
+	assert.Equal(t, ErrKeyNotFound, err)
+	_, err = g.GetStructs("unexisting")
+	assert.Equal(t, ErrKeyNotFound, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"RegionInfo": map[string]interface{}{
+			"AppliedToDimensions": map[string]interface{}{"W": float64(4032), "H": float64(3024)},
+			"RegionList":          []interface{}{map[string]interface{}{"Name": "a"}, map[string]interface{}{"Name": "b"}},
+		},
+		"Title": "t",
+		"Mixed": []interface{}{map[string]interface{}{"Name": "a"}, "b"},
+	}, g.Map())
+}
+
+func TestGetBytes(t *testing.T) {
+	g := FileMetadataValues{
+		{"ThumbnailImage", "base64:/9j/4A=="},
+		{"Invalid", "base64:!!"},
+		{"Placeholder", "(Binary data 5024 bytes, use -b option to extract)"},
+		{"Make", "Canon"},
+		{"Width", float64(4032)},
+	}
+
+	var tcs = []struct {
+		tcID   string
+		inKey  string
+		expErr error
+		expOk  bool
+		expB   []byte
+	}{
+		{"base64", "ThumbnailImage", nil, true, []byte{0xff, 0xd8, 0xff, 0xe0}},
+		{"invalid", "Invalid", nil, false, nil},
+		{"placeholder", "Placeholder", ErrBinaryNotExtracted, false, nil},
+		{"string", "Make", nil, true, []byte("Canon")},
+		{"float", "Width", nil, true, []byte("4032")},
+		{"unexisting", "unexisting", ErrKeyNotFound, false, nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			b, err := g.GetBytes(tc.inKey)
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expErr != nil {
+				assert.Equal(t, tc.expErr, err)
+			}
+			assert.Equal(t, tc.expB, b)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package exiftool
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// FileSystem is the file system through which the Exiftool checks that files exist,
+// walks directories (see ExtractDir and Watch), creates its temporary files (argfiles,
+// spilled outputs, atomic writes), reads the files it processes by itself (archives,
+// hashes) and replaces or backs up the written files (write policies, lossless
+// transformations). It can be bound to libraries such as afero or billy,
+// or faked in unit tests. As exiftool reads and writes the files by itself, the files
+// provided to exiftool (extracted and written files, argfiles, temporary files of atomic
+// writes) must be reachable by exiftool under the same paths. Implementations must be
+// safe for concurrent use.
+type FileSystem interface {
+	// Stat returns the description of the file name
+	Stat(name string) (os.FileInfo, error)
+	// ReadDir returns the description of the entries of directory dirname
+	ReadDir(dirname string) ([]os.FileInfo, error)
+	// TempFile creates a new temporary file of directory dir (the default directory for
+	// temporary files if dir is empty), see ioutil.TempFile for pattern
+	TempFile(dir, pattern string) (File, error)
+	// Remove removes the file name
+	Remove(name string) error
+	// Open opens the file name for reading
+	Open(name string) (File, error)
+	// OpenFile opens the file name with flag (ie. os.O_CREATE|os.O_EXCL), creating it
+	// with perm if needed, see os.OpenFile
+	OpenFile(name string, flag int, perm os.FileMode) (File, error)
+	// Rename renames (moves) the file oldpath to newpath, replacing it if it exists
+	Rename(oldpath, newpath string) error
+	// Chmod changes the mode of the file name
+	Chmod(name string, mode os.FileMode) error
+	// MkdirAll creates the directory path and its missing parents with perm, see
+	// os.MkdirAll
+	MkdirAll(path string, perm os.FileMode) error
+}
+
+// File is a file created by a FileSystem
+type File interface {
+	io.Reader
+	io.Writer
+	io.Seeker
+	io.Closer
+	Name() string
+}
+
+// OSFileSystem is the FileSystem of the operating system, used by default
+type OSFileSystem struct{}
+
+// Stat calls os.Stat
+func (OSFileSystem) Stat(name string) (os.FileInfo, error) {
+	return os.Stat(name)
+}
+
+// ReadDir calls ioutil.ReadDir
+func (OSFileSystem) ReadDir(dirname string) ([]os.FileInfo, error) {
+	return ioutil.ReadDir(dirname)
+}
+
+// TempFile calls ioutil.TempFile
+func (OSFileSystem) TempFile(dir, pattern string) (File, error) {
+	return ioutil.TempFile(dir, pattern)
+}
+
+// Remove calls os.Remove
+func (OSFileSystem) Remove(name string) error {
+	return os.Remove(name)
+}
+
+// Open calls os.Open
+func (OSFileSystem) Open(name string) (File, error) {
+	return os.Open(name)
+}
+
+// OpenFile calls os.OpenFile
+func (OSFileSystem) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	return os.OpenFile(name, flag, perm)
+}
+
+// Rename calls os.Rename
+func (OSFileSystem) Rename(oldpath, newpath string) error {
+	return os.Rename(oldpath, newpath)
+}
+
+// Chmod calls os.Chmod
+func (OSFileSystem) Chmod(name string, mode os.FileMode) error {
+	return os.Chmod(name, mode)
+}
+
+// MkdirAll calls os.MkdirAll
+func (OSFileSystem) MkdirAll(path string, perm os.FileMode) error {
+	return os.MkdirAll(path, perm)
+}
+
+// SetFileSystem defines the FileSystem used by the Exiftool, or by the Pool when used
+// with NewPool
+// Sample :
+//   e, err := NewExiftool(SetFileSystem(afsAdapter))
+func SetFileSystem(fsys FileSystem) Option {
+	return func(e *Exiftool) error {
+		e.fsys = fsys
+		return nil
+	}
+}
+
+// orOS returns fsys, or the OSFileSystem if fsys is nil
+func orOS(fsys FileSystem) FileSystem {
+	if fsys == nil {
+		return OSFileSystem{}
+	}
+	return fsys
+}
+
+// fileSystem returns the FileSystem of the Exiftool
+func (e *Exiftool) fileSystem() FileSystem {
+	return orOS(e.fsys)
+}
+
+// checkFile returns a FileNotFoundError if f doesn't exist
+func (e *Exiftool) checkFile(f string) error {
+	return checkFileFS(e.fileSystem(), f)
+}
+
+// checkFileFS returns a FileNotFoundError if f doesn't exist in fsys
+func checkFileFS(fsys FileSystem, f string) error {
+	if _, err := fsys.Stat(f); err != nil {
+		if os.IsNotExist(err) {
+			return &FileNotFoundError{File: f}
+		}
+		return err
+	}
+	return nil
+}
+
+// walkFS walks the file tree rooted at root like filepath.Walk, through fsys
+func walkFS(fsys FileSystem, root string, fn filepath.WalkFunc) error {
+	info, err := fsys.Stat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = walkFSPath(fsys, root, info, fn)
+	}
+	if err == filepath.SkipDir {
+		return nil
+	}
+	return err
+}
+
+func walkFSPath(fsys FileSystem, path string, info os.FileInfo, fn filepath.WalkFunc) error {
+	if !info.IsDir() {
+		return fn(path, info, nil)
+	}
+
+	infos, err := fsys.ReadDir(path)
+	if err1 := fn(path, info, err); err != nil || err1 != nil {
+		return err1
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name() < infos[j].Name() })
+	for _, fi := range infos {
+		err := walkFSPath(fsys, filepath.Join(path, fi.Name()), fi, fn)
+		if err != nil && (!fi.IsDir() || err != filepath.SkipDir) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,376 @@
+package exiftool
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memFileSystem is an in-memory FileSystem, directories being implied by the paths of
+// the files
+type memFileSystem struct {
+	lock  sync.Mutex
+	files map[string][]byte
+	modes map[string]os.FileMode
+	temps int
+}
+
+func newMemFileSystem(paths ...string) *memFileSystem {
+	m := memFileSystem{files: map[string][]byte{}, modes: map[string]os.FileMode{}}
+	for _, p := range paths {
+		m.files[filepath.Clean(p)] = nil
+	}
+	return &m
+}
+
+type memFileInfo struct {
+	name string
+	size int64
+	dir  bool
+	mode os.FileMode
+}
+
+func (i memFileInfo) Name() string { return i.name }
+func (i memFileInfo) Size() int64  { return i.size }
+func (i memFileInfo) Mode() os.FileMode {
+	if i.mode == 0 {
+		return 0644
+	}
+	return i.mode
+}
+func (i memFileInfo) ModTime() time.Time { return time.Time{} }
+func (i memFileInfo) IsDir() bool        { return i.dir }
+func (i memFileInfo) Sys() interface{}   { return nil }
+
+func (m *memFileSystem) Stat(name string) (os.FileInfo, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	name = filepath.Clean(name)
+	if b, found := m.files[name]; found {
+		return memFileInfo{name: filepath.Base(name), size: int64(len(b)), mode: m.modes[name]}, nil
+	}
+	for p := range m.files {
+		if strings.HasPrefix(p, name+string(filepath.Separator)) {
+			return memFileInfo{name: filepath.Base(name), dir: true}, nil
+		}
+	}
+	return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+}
+
+func (m *memFileSystem) ReadDir(dirname string) ([]os.FileInfo, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	prefix := filepath.Clean(dirname) + string(filepath.Separator)
+	seen := map[string]bool{}
+	var infos []os.FileInfo
+	for p, b := range m.files {
+		if !strings.HasPrefix(p, prefix) {
+			continue
+		}
+		rel := strings.TrimPrefix(p, prefix)
+		name := strings.SplitN(rel, string(filepath.Separator), 2)[0]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		infos = append(infos, memFileInfo{name: name, size: int64(len(b)), dir: name != rel})
+	}
+	if len(infos) == 0 {
+		return nil, &os.PathError{Op: "readdir", Path: dirname, Err: os.ErrNotExist}
+	}
+	return infos, nil
+}
+
+func (m *memFileSystem) TempFile(dir, pattern string) (File, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.temps++
+	name := filepath.Join(dir, strings.Replace(pattern, "*", fmt.Sprint(m.temps), 1))
+	m.files[name] = nil
+	return &memFile{fs: m, name: name}, nil
+}
+
+func (m *memFileSystem) Remove(name string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if _, found := m.files[name]; !found {
+		return &os.PathError{Op: "remove", Path: name, Err: os.ErrNotExist}
+	}
+	delete(m.files, name)
+	delete(m.modes, name)
+	return nil
+}
+
+func (m *memFileSystem) Open(name string) (File, error) {
+	return m.OpenFile(name, os.O_RDONLY, 0)
+}
+
+func (m *memFileSystem) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	name = filepath.Clean(name)
+	b, found := m.files[name]
+	switch {
+	case found && flag&os.O_CREATE != 0 && flag&os.O_EXCL != 0:
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrExist}
+	case !found && flag&os.O_CREATE == 0:
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	case !found:
+		m.files[name] = nil
+		m.modes[name] = perm
+	}
+	if flag&os.O_TRUNC != 0 {
+		b = nil
+	}
+	return &memFile{fs: m, name: name, data: append([]byte{}, b...)}, nil
+}
+
+func (m *memFileSystem) Rename(oldpath, newpath string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	oldpath, newpath = filepath.Clean(oldpath), filepath.Clean(newpath)
+	b, found := m.files[oldpath]
+	if !found {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: os.ErrNotExist}
+	}
+	m.files[newpath], m.modes[newpath] = b, m.modes[oldpath]
+	delete(m.files, oldpath)
+	delete(m.modes, oldpath)
+	return nil
+}
+
+func (m *memFileSystem) Chmod(name string, mode os.FileMode) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	name = filepath.Clean(name)
+	if _, found := m.files[name]; !found {
+		return &os.PathError{Op: "chmod", Path: name, Err: os.ErrNotExist}
+	}
+	m.modes[name] = mode
+	return nil
+}
+
+// MkdirAll fails if path is a file, directories being implied
+func (m *memFileSystem) MkdirAll(path string, perm os.FileMode) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	path = filepath.Clean(path)
+	for p := path; p != filepath.Dir(p); p = filepath.Dir(p) {
+		if _, found := m.files[p]; found {
+			return &os.PathError{Op: "mkdir", Path: path, Err: syscall.ENOTDIR}
+		}
+	}
+	return nil
+}
+
+type memFile struct {
+	fs   *memFileSystem
+	name string
+	data []byte
+	off  int
+}
+
+func (f *memFile) Read(p []byte) (int, error) {
+	if f.off >= len(f.data) {
+		return 0, io.EOF
+	}
+	n := copy(p, f.data[f.off:])
+	f.off += n
+	return n, nil
+}
+
+func (f *memFile) Write(p []byte) (int, error) {
+	f.data = append(f.data[:f.off], p...)
+	f.off += len(p)
+	return len(p), nil
+}
+
+func (f *memFile) Seek(offset int64, whence int) (int64, error) {
+	if whence != io.SeekStart {
+		return 0, errors.New("unsupported whence")
+	}
+	f.off = int(offset)
+	return offset, nil
+}
+
+func (f *memFile) Close() error {
+	f.fs.lock.Lock()
+	defer f.fs.lock.Unlock()
+	if _, found := f.fs.files[f.name]; found {
+		f.fs.files[f.name] = f.data
+	}
+	return nil
+}
+
+func (f *memFile) Name() string { return f.name }
+
+func TestSetFileSystem(t *testing.T) {
+	e := Exiftool{}
+	assert.Equal(t, OSFileSystem{}, e.fileSystem())
+
+	m := newMemFileSystem()
+	assert.Nil(t, SetFileSystem(m)(&e))
+	assert.Equal(t, m, e.fileSystem())
+}
+
+func TestCheckFileFS(t *testing.T) {
+	m := newMemFileSystem("photos/a.jpg")
+	e := Exiftool{fsys: m}
+
+	assert.Nil(t, e.checkFile("photos/a.jpg"))
+	assert.Nil(t, e.checkFile("photos"))
+	err := e.checkFile("photos/b.jpg")
+	assert.True(t, errors.Is(err, ErrNotExist))
+
+	// nothing reaches exiftool for files missing from the file system
+	assert.True(t, errors.Is(e.Write("./testdata/20190404_131804.jpg", FileMetadataValues{{"Artist", "a"}}), ErrNotExist))
+}
+
+func TestWalkFS(t *testing.T) {
+	m := newMemFileSystem("root/b.jpg", "root/a/2.jpg", "root/a/1.jpg", "root/skip/c.jpg", "root/c/d/e.jpg")
+
+	var paths []string
+	err := walkFS(m, "root", func(path string, info os.FileInfo, err error) error {
+		assert.Nil(t, err)
+		if info.IsDir() && info.Name() == "skip" {
+			return filepath.SkipDir
+		}
+		paths = append(paths, filepath.ToSlash(path))
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"root", "root/a", "root/a/1.jpg", "root/a/2.jpg", "root/b.jpg", "root/c", "root/c/d", "root/c/d/e.jpg"}, paths)
+
+	var errs []error
+	walkFS(m, "nonExisting", func(path string, info os.FileInfo, err error) error {
+		errs = append(errs, err)
+		return nil
+	})
+	assert.Equal(t, 1, len(errs))
+	assert.True(t, os.IsNotExist(errs[0]))
+}
+
+func TestDirConfigWalkFS(t *testing.T) {
+	m := newMemFileSystem("root/a.jpg", "root/b.png", "root/sub/c.JPG")
+	c, err := newDirConfig([]DirOption{Extensions("jpg")})
+	assert.Nil(t, err)
+	c.fsys = m
+
+	var files []string
+	c.walk("root", func(path string, err error) {
+		assert.Nil(t, err)
+		files = append(files, filepath.ToSlash(path))
+	})
+	sort.Strings(files)
+	assert.Equal(t, []string{"root/a.jpg", "root/sub/c.JPG"}, files)
+}
+
+func TestTempFilesFS(t *testing.T) {
+	m := newMemFileSystem()
+
+	f, err := writeArgFile(m, []string{"-a", "b"})
+	assert.Nil(t, err)
+	assert.Equal(t, "-a\nb\n", string(m.files[f]))
+
+	p, err := tempPath(m, filepath.Join("dir", "a.jpg"))
+	assert.Nil(t, err)
+	assert.Equal(t, ".jpg", filepath.Ext(p))
+	_, found := m.files[p]
+	assert.False(t, found)
+
+	o := outputBuffer{fsys: m, threshold: 2}
+	o.write([]byte("abc"))
+	o.write([]byte("def"))
+	b, err := o.bytes()
+	assert.Nil(t, err)
+	assert.Equal(t, "abcdef", string(b))
+	o.close()
+	assert.Equal(t, 1, len(m.files))
+}
+
+func TestMemFileSystemFiles(t *testing.T) {
+	m := newMemFileSystem()
+
+	f, err := m.OpenFile("a.jpg", os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	assert.Nil(t, err)
+	_, err = f.Write([]byte("abc"))
+	assert.Nil(t, err)
+	assert.Nil(t, f.Close())
+	_, err = m.OpenFile("a.jpg", os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	assert.True(t, os.IsExist(err))
+
+	assert.Nil(t, m.Rename("a.jpg", "b.jpg"))
+	fi, err := m.Stat("b.jpg")
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), fi.Mode())
+	assert.Nil(t, m.Chmod("b.jpg", 0640))
+	fi, err = m.Stat("b.jpg")
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0640), fi.Mode())
+
+	r, err := m.Open("b.jpg")
+	assert.Nil(t, err)
+	b := make([]byte, 3)
+	_, err = io.ReadFull(r, b)
+	assert.Nil(t, err)
+	assert.Equal(t, "abc", string(b))
+
+	assert.Nil(t, m.MkdirAll("d/e", 0755))
+	assert.NotNil(t, m.MkdirAll("b.jpg/e", 0755))
+
+	_, err = m.Open("a.jpg")
+	assert.True(t, os.IsNotExist(err))
+	assert.True(t, os.IsNotExist(m.Rename("a.jpg", "c.jpg")))
+	assert.True(t, os.IsNotExist(m.Chmod("a.jpg", 0600)))
+}
+
+func TestOSFileSystemFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-exiftool")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	var fsys FileSystem = OSFileSystem{}
+	a, b := filepath.Join(dir, "a.jpg"), filepath.Join(dir, "b.jpg")
+	f, err := fsys.OpenFile(a, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	assert.Nil(t, err)
+	_, err = f.Write([]byte("abc"))
+	assert.Nil(t, err)
+	assert.Nil(t, f.Close())
+
+	assert.Nil(t, fsys.MkdirAll(filepath.Join(dir, "d", "e"), 0755))
+	fi, err := fsys.Stat(filepath.Join(dir, "d", "e"))
+	assert.Nil(t, err)
+	assert.True(t, fi.IsDir())
+
+	assert.Nil(t, fsys.Rename(a, b))
+	assert.Nil(t, fsys.Chmod(b, 0640))
+	fi, err = fsys.Stat(b)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0640), fi.Mode().Perm())
+
+	r, err := fsys.Open(b)
+	assert.Nil(t, err)
+	defer r.Close()
+	c, err := ioutil.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, "abc", string(c))
+}
@@ -0,0 +1,36 @@
+//go:build go1.16
+// +build go1.16
+
+package exiftool
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+// ExtractFS extracts metadata from files read from fsys (ie. embed.FS, zip archives,
+// fstest.MapFS), each one being streamed to exiftool, see ExtractReader. Results are
+// returned in the same order as paths.
+// Sample :
+//   fms := e.ExtractFS(os.DirFS("/photos"), "a.jpg", "b.jpg")
+func (e *Exiftool) ExtractFS(fsys fs.FS, paths ...string) []FileMetadata {
+	fms := make([]FileMetadata, len(paths))
+	for i, p := range paths {
+		fms[i] = e.extractFS(fsys, p)
+	}
+	return fms
+}
+
+func (e *Exiftool) extractFS(fsys fs.FS, p string) FileMetadata {
+	f, err := fsys.Open(p)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return FileMetadata{File: p, Err: &FileNotFoundError{File: p}}
+		}
+		return FileMetadata{File: p, Err: fmt.Errorf("error when opening file: %w", err)}
+	}
+	defer f.Close()
+
+	return e.ExtractReader(f, p)
+}
@@ -0,0 +1,34 @@
+//go:build go1.16
+// +build go1.16
+
+package exiftool
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractFS(t *testing.T) {
+	b, err := ioutil.ReadFile("./testdata/20190404_131804.jpg")
+	assert.Nil(t, err)
+	fsys := fstest.MapFS{"photos/a.jpg": {Data: b}}
+
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	fms := e.ExtractFS(fsys, "photos/a.jpg", "photos/nonExisting")
+	assert.Equal(t, 2, len(fms))
+	assert.Equal(t, "photos/a.jpg", fms[0].File)
+	assert.Nil(t, fms[0].Err)
+	mk, err := fms[0].Groups["EXIF"].GetString("Make")
+	assert.Nil(t, err)
+	assert.Equal(t, "samsung", mk)
+
+	assert.Equal(t, "photos/nonExisting", fms[1].File)
+	assert.True(t, errors.Is(fms[1].Err, ErrNotExist))
+}
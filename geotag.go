@@ -0,0 +1,149 @@
+package exiftool
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// GeotagOption is a configuration function of Geotag
+type GeotagOption func(*geotagConfig) error
+
+type geotagConfig struct {
+	offset  string
+	syncs   []string
+	timeTag string
+	apis    []string
+}
+
+// GeotagOffset shifts the time of the images by offset before matching it with the track
+// log, which compensates a camera clock set to a wrong time (activates Exiftool's
+// '-geosync' parameter). It is ignored when GeotagSync is used.
+// Sample :
+//   err := e.Geotag(files, "track.gpx", GeotagOffset(-2*time.Hour))
+func GeotagOffset(offset time.Duration) GeotagOption {
+	return func(c *geotagConfig) error {
+		op := "+"
+		if offset < 0 {
+			op, offset = "-", -offset
+		}
+		h := offset / time.Hour
+		m := (offset - h*time.Hour) / time.Minute
+		sec := (offset - h*time.Hour - m*time.Minute).Seconds()
+		c.offset = fmt.Sprintf("%v%d:%d:%v", op, h, m, strconv.FormatFloat(sec, 'f', -1, 64))
+		return nil
+	}
+}
+
+// GeotagSync declares that the camera clock read imageTime when the GPS time was gpsTime
+// (activates Exiftool's '-geosync' parameter). Calling it several times corrects the
+// clock drift between the synchronization points.
+// Sample :
+//   err := e.Geotag(files, "track.gpx", GeotagSync(camTime1, gpsTime1), GeotagSync(camTime2, gpsTime2))
+func GeotagSync(imageTime, gpsTime time.Time) GeotagOption {
+	return func(c *geotagConfig) error {
+		if imageTime.IsZero() || gpsTime.IsZero() {
+			return fmt.Errorf("zero synchronization time")
+		}
+		c.syncs = append(c.syncs, fmt.Sprintf("%v@%v",
+			imageTime.Format("2006:01:02 15:04:05"), gpsTime.UTC().Format("2006:01:02 15:04:05Z")))
+		return nil
+	}
+}
+
+// GeotagTimeTag defines the tag holding the time of the images (DateTimeOriginal by
+// default, activates Exiftool's '-geotime' parameter)
+// Sample :
+//   err := e.Geotag(files, "track.gpx", GeotagTimeTag("CreateDate"))
+func GeotagTimeTag(tag string) GeotagOption {
+	return func(c *geotagConfig) error {
+		if tag == "" {
+			return fmt.Errorf("empty tag")
+		}
+		c.timeTag = tag
+		return nil
+	}
+}
+
+// GeotagMaxInterval defines the maximum time between two track points to interpolate a
+// position (exiftool's GeoMaxIntSecs API option, 1800s by default)
+func GeotagMaxInterval(d time.Duration) GeotagOption {
+	return func(c *geotagConfig) error {
+		if d <= 0 {
+			return fmt.Errorf("invalid interval (%v)", d)
+		}
+		c.apis = append(c.apis, "-api", fmt.Sprintf("GeoMaxIntSecs=%v", int64(d/time.Second)))
+		return nil
+	}
+}
+
+// GeotagMaxExtrapolation defines the maximum time before the first or after the last
+// track point to extrapolate a position (exiftool's GeoMaxExtSecs API option, 1800s by
+// default)
+func GeotagMaxExtrapolation(d time.Duration) GeotagOption {
+	return func(c *geotagConfig) error {
+		if d < 0 {
+			return fmt.Errorf("invalid extrapolation (%v)", d)
+		}
+		c.apis = append(c.apis, "-api", fmt.Sprintf("GeoMaxExtSecs=%v", int64(d/time.Second)))
+		return nil
+	}
+}
+
+// geotagArgs returns the arguments of a geotagging command, files excluded
+func geotagArgs(gpxPath string, opts []GeotagOption) ([]string, error) {
+	var c geotagConfig
+	for _, opt := range opts {
+		if err := opt(&c); err != nil {
+			return nil, fmt.Errorf("error when configuring geotagging: %w", err)
+		}
+	}
+
+	args := append([]string{}, c.apis...)
+	args = append(args, "-geotag", gpxPath)
+	syncs := c.syncs
+	if len(syncs) == 0 && c.offset != "" {
+		syncs = []string{c.offset}
+	}
+	for _, s := range syncs {
+		args = append(args, "-geosync="+s)
+	}
+	if c.timeTag != "" {
+		args = append(args, "-geotime<"+c.timeTag)
+	}
+
+	return args, nil
+}
+
+// Geotag writes the GPS position of files from the GPS track log gpxPath (activates
+// Exiftool's '-geotag' parameter), matching the time of each image with the time of the
+// track points, see https://exiftool.org/geotag.html. Every track log format supported
+// by exiftool (GPX, NMEA, KML, ...) can be used. If anything went wrong, a non empty
+// error will be returned.
+// Sample :
+//   err := e.Geotag([]string{"a.jpg", "b.jpg"}, "track.gpx", GeotagOffset(time.Hour))
+func (e *Exiftool) Geotag(files []string, gpxPath string, opts ...GeotagOption) error {
+	if len(files) == 0 {
+		return fmt.Errorf("no file to geotag")
+	}
+	for _, f := range append([]string{gpxPath}, files...) {
+		if err := e.checkFile(f); err != nil {
+			return err
+		}
+	}
+
+	args, err := geotagArgs(gpxPath, opts)
+	if err != nil {
+		return err
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	out, err := e.executeWriteFiles(args, files...)
+	if err != nil {
+		return err
+	}
+
+	return checkWriteOutput("", out)
+}
@@ -0,0 +1,84 @@
+package exiftool
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeotagArgs(t *testing.T) {
+	img := time.Date(2019, 4, 4, 13, 18, 4, 0, time.UTC)
+	gps := time.Date(2019, 4, 4, 13, 20, 4, 0, time.FixedZone("CEST", 7200))
+
+	var tcs = []struct {
+		tcID    string
+		inOpts  []GeotagOption
+		expOk   bool
+		expArgs []string
+	}{
+		{"default", nil, true, []string{"-geotag", "t.gpx"}},
+		{"offset", []GeotagOption{GeotagOffset(90 * time.Minute)}, true, []string{"-geotag", "t.gpx", "-geosync=+1:30:0"}},
+		{"negativeOffset", []GeotagOption{GeotagOffset(-(26*time.Hour + 1500*time.Millisecond))}, true, []string{"-geotag", "t.gpx", "-geosync=-26:0:1.5"}},
+		{"sync", []GeotagOption{GeotagOffset(time.Hour), GeotagSync(img, gps), GeotagSync(img.Add(time.Hour), gps.Add(time.Hour))}, true,
+			[]string{"-geotag", "t.gpx", "-geosync=2019:04:04 13:18:04@2019:04:04 11:20:04Z", "-geosync=2019:04:04 14:18:04@2019:04:04 12:20:04Z"}},
+		{"timeTag", []GeotagOption{GeotagTimeTag("CreateDate")}, true, []string{"-geotag", "t.gpx", "-geotime<CreateDate"}},
+		{"api", []GeotagOption{GeotagMaxInterval(time.Minute), GeotagMaxExtrapolation(0)}, true,
+			[]string{"-api", "GeoMaxIntSecs=60", "-api", "GeoMaxExtSecs=0", "-geotag", "t.gpx"}},
+		{"zeroSync", []GeotagOption{GeotagSync(time.Time{}, gps)}, false, nil},
+		{"emptyTimeTag", []GeotagOption{GeotagTimeTag("")}, false, nil},
+		{"invalidInterval", []GeotagOption{GeotagMaxInterval(0)}, false, nil},
+		{"invalidExtrapolation", []GeotagOption{GeotagMaxExtrapolation(-time.Second)}, false, nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			args, err := geotagArgs("t.gpx", tc.inOpts)
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.expArgs, args)
+			}
+		})
+	}
+}
+
+const testTrack = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="go-exiftool">
+<trk><trkseg>
+<trkpt lat="43.6" lon="1.44"><ele>150</ele><time>2019-04-04T00:00:00Z</time></trkpt>
+<trkpt lat="43.6" lon="1.44"><ele>150</ele><time>2019-04-05T00:00:00Z</time></trkpt>
+</trkseg></trk>
+</gpx>
+`
+
+func TestGeotag(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+	gpx := filepath.Join(filepath.Dir(f), "track.gpx")
+	assert.Nil(t, ioutil.WriteFile(gpx, []byte(testTrack), 0644))
+
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	assert.NotNil(t, e.Geotag(nil, gpx))
+	assert.True(t, errors.Is(e.Geotag([]string{f}, "./testdata/nonExisting"), ErrNotExist))
+	assert.True(t, errors.Is(e.Geotag([]string{"./testdata/nonExisting"}, gpx), ErrNotExist))
+	assert.NotNil(t, e.Geotag([]string{f}, gpx, GeotagTimeTag("")))
+
+	// the track covers the whole day whatever the local timezone used to read the image
+	// time
+	assert.Nil(t, e.Geotag([]string{f}, gpx, GeotagOffset(-2*time.Hour), GeotagMaxInterval(48*time.Hour)))
+
+	fms := e.ExtractMetadata(f)
+	assert.Equal(t, 1, len(fms))
+	assert.Nil(t, fms[0].Err)
+	pos, err := fms[0].GPSPosition()
+	assert.Nil(t, err)
+	assert.InDelta(t, 43.6, pos.Latitude, 1e-6)
+	assert.InDelta(t, 1.44, pos.Longitude, 1e-6)
+}
@@ -0,0 +1,208 @@
+package exiftool
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var gpsNumberRegexp = regexp.MustCompile(`[-+]?\d+(?:\.\d+)?`)
+
+// GPS is a geographic position in decimal degrees (negative for south and west).
+// Altitude, in meters (negative below sea level), is only meaningful when HasAltitude is
+// true.
+type GPS struct {
+	Latitude    float64
+	Longitude   float64
+	Altitude    float64
+	HasAltitude bool
+}
+
+// GPSPosition returns the GPS position of the file, handling both numeric values (-n)
+// and degrees/minutes/seconds strings along with their GPSLatitudeRef / GPSLongitudeRef.
+// ErrKeyNotFound will be returned if the latitude or the longitude can't be found.
+func (fm FileMetadata) GPSPosition() (GPS, error) {
+	var gps GPS
+	var err error
+
+	if gps.Latitude, err = fm.gpsCoordinate("GPSLatitude", "S"); err != nil {
+		return GPS{}, err
+	}
+	if gps.Longitude, err = fm.gpsCoordinate("GPSLongitude", "W"); err != nil {
+		return GPS{}, err
+	}
+
+	gps.Altitude, err = fm.gpsAltitude()
+	switch {
+	case err == nil:
+		gps.HasAltitude = true
+	case err != ErrKeyNotFound:
+		return GPS{}, err
+	}
+
+	return gps, nil
+}
+
+// gpsCoordinate returns the decimal value of the tag k, negRef being the reference
+// ("S" or "W") making the coordinate negative
+func (fm FileMetadata) gpsCoordinate(k, negRef string) (float64, error) {
+	g, label, found := fm.lookup(k)
+	if !found {
+		return 0, ErrKeyNotFound
+	}
+	s, _ := g.GetString(label)
+
+	v, ref, err := parseCoordinate(s)
+	if err != nil {
+		return 0, fmt.Errorf("%v parsing error: %w", k, err)
+	}
+
+	if ref == "" {
+		if rg, rl, found := fm.lookup(k + "Ref"); found {
+			rs, _ := rg.GetString(rl)
+			ref = strings.ToUpper(strings.TrimSpace(rs))
+		}
+	}
+	if strings.HasPrefix(ref, negRef) && v > 0 {
+		v = -v
+	}
+
+	return v, nil
+}
+
+// parseCoordinate parses a coordinate such as "43.6", "43 deg 36' 11.96\" N" or
+// "43,36.1993N" and returns its absolute value (or signed value if no direction is
+// provided) and its direction if any
+func parseCoordinate(s string) (float64, string, error) {
+	s = strings.TrimSpace(s)
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f, "", nil
+	}
+
+	nums := gpsNumberRegexp.FindAllString(s, -1)
+	if len(nums) == 0 || len(nums) > 3 {
+		return 0, "", fmt.Errorf("invalid coordinate (%v)", s)
+	}
+
+	var v float64
+	for i, n := range nums {
+		f, err := strconv.ParseFloat(n, 64)
+		if err != nil {
+			return 0, "", fmt.Errorf("invalid coordinate (%v): %w", s, err)
+		}
+		if i > 0 && f < 0 {
+			return 0, "", fmt.Errorf("invalid coordinate (%v)", s)
+		}
+		switch i {
+		case 0:
+			v = f
+		case 1:
+			v += f / 60
+		case 2:
+			v += f / 3600
+		}
+	}
+
+	var ref string
+	if last := s[len(s)-1:]; strings.ContainsAny(last, "NSEWnsew") {
+		ref = strings.ToUpper(last)
+	}
+
+	return v, ref, nil
+}
+
+// gpsAltitude returns the altitude in meters, negative below sea level
+func (fm FileMetadata) gpsAltitude() (float64, error) {
+	g, label, found := fm.lookup("GPSAltitude")
+	if !found {
+		return 0, ErrKeyNotFound
+	}
+	s, _ := g.GetString(label)
+
+	n := gpsNumberRegexp.FindString(s)
+	if n == "" {
+		return 0, fmt.Errorf("GPSAltitude parsing error (%v)", s)
+	}
+	v, err := strconv.ParseFloat(n, 64)
+	if err != nil {
+		return 0, fmt.Errorf("GPSAltitude parsing error (%v): %w", s, err)
+	}
+
+	below := strings.Contains(s, "Below")
+	if !below && !strings.Contains(s, "Above") {
+		if rg, rl, found := fm.lookup("GPSAltitudeRef"); found {
+			rs, _ := rg.GetString(rl)
+			below = strings.Contains(rs, "Below") || strings.TrimSpace(rs) == "1"
+		}
+	}
+	if below && v > 0 {
+		v = -v
+	}
+
+	return v, nil
+}
+
+// SetGPS writes the GPS position of file: lat and lon in decimal degrees (negative for
+// south and west), alt in meters (negative below sea level) if not nil and the GPS date
+// and time of t (converted to UTC) if not zero. The GPS*Ref tags are derived from the
+// signs, the coordinates being written as absolute values.
+// Sample :
+//   alt := 35.0
+//   err := e.SetGPS("a.jpg", 48.8584, 2.2945, &alt, time.Now())
+func (e *Exiftool) SetGPS(file string, lat, lon float64, alt *float64, t time.Time) error {
+	values, err := gpsValues(lat, lon, alt, t)
+	if err != nil {
+		return err
+	}
+	return e.Write(file, values)
+}
+
+// gpsValues returns the tags to write for the GPS position lat/lon/alt taken at t, see
+// SetGPS. The raw values (#) are written so that they don't depend on the print
+// conversion.
+func gpsValues(lat, lon float64, alt *float64, t time.Time) (FileMetadataValues, error) {
+	if math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return nil, fmt.Errorf("invalid latitude (%v)", lat)
+	}
+	if math.IsNaN(lon) || lon < -180 || lon > 180 {
+		return nil, fmt.Errorf("invalid longitude (%v)", lon)
+	}
+
+	values := FileMetadataValues{
+		{"GPS:GPSLatitude#", math.Abs(lat)},
+		{"GPS:GPSLatitudeRef#", signRef(lat, "N", "S")},
+		{"GPS:GPSLongitude#", math.Abs(lon)},
+		{"GPS:GPSLongitudeRef#", signRef(lon, "E", "W")},
+	}
+
+	if alt != nil {
+		if math.IsNaN(*alt) || math.IsInf(*alt, 0) {
+			return nil, fmt.Errorf("invalid altitude (%v)", *alt)
+		}
+		values = append(values,
+			FileMetadataValue{"GPS:GPSAltitude#", math.Abs(*alt)},
+			FileMetadataValue{"GPS:GPSAltitudeRef#", signRef(*alt, "0", "1")},
+		)
+	}
+
+	if !t.IsZero() {
+		t = t.UTC()
+		values = append(values,
+			FileMetadataValue{"GPS:GPSDateStamp#", t.Format("2006:01:02")},
+			FileMetadataValue{"GPS:GPSTimeStamp#", t.Format("15:04:05.999999")},
+		)
+	}
+
+	return values, nil
+}
+
+// signRef returns pos if v is positive or zero, neg otherwise
+func signRef(v float64, pos, neg string) string {
+	if v < 0 {
+		return neg
+	}
+	return pos
+}
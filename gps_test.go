@@ -0,0 +1,195 @@
+package exiftool
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCoordinate(t *testing.T) {
+	var tcs = []struct {
+		tcID   string
+		in     string
+		expOk  bool
+		expVal float64
+		expRef string
+	}{
+		{"numeric", "43.60332", true, 43.60332, ""},
+		{"negative", "-1.5", true, -1.5, ""},
+		{"dms", `43 deg 36' 12.00"`, true, 43.60333333, ""},
+		{"dmsRef", `1 deg 30' 0.00" W`, true, 1.5, "W"},
+		{"xmp", "43,36.2N", true, 43.60333333, "N"},
+		{"invalid", "abc", false, 0, ""},
+		{"tooManyNumbers", "1 2 3 4", false, 0, ""},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			v, ref, err := parseCoordinate(tc.in)
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.InDelta(t, tc.expVal, v, 1e-6)
+				assert.Equal(t, tc.expRef, ref)
+			}
+		})
+	}
+}
+
+func TestGPSPosition(t *testing.T) {
+	var tcs = []struct {
+		tcID   string
+		in     map[string]FileMetadataValues
+		expOk  bool
+		expErr error
+		expGPS GPS
+	}{
+		{
+			"printConversion",
+			map[string]FileMetadataValues{"EXIF": {
+				{"GPSLatitudeRef", "South"},
+				{"GPSLatitude", `33 deg 51' 36.00"`},
+				{"GPSLongitudeRef", "East"},
+				{"GPSLongitude", `151 deg 12' 36.00"`},
+				{"GPSAltitudeRef", "Above Sea Level"},
+				{"GPSAltitude", "58 m"},
+			}},
+			true, nil, GPS{-33.86, 151.21, 58, true},
+		},
+		{
+			"noPrintConversion",
+			map[string]FileMetadataValues{"EXIF": {
+				{"GPSLatitudeRef", "N"},
+				{"GPSLatitude", float64(48.8584)},
+				{"GPSLongitudeRef", "W"},
+				{"GPSLongitude", float64(2.2945)},
+				{"GPSAltitudeRef", float64(1)},
+				{"GPSAltitude", float64(12.5)},
+			}},
+			true, nil, GPS{48.8584, -2.2945, -12.5, true},
+		},
+		{
+			"composite",
+			map[string]FileMetadataValues{"Composite": {
+				{"GPSLatitude", `33 deg 51' 36.00" S`},
+				{"GPSLongitude", `151 deg 12' 36.00" E`},
+				{"GPSAltitude", "3 m Below Sea Level"},
+			}},
+			true, nil, GPS{-33.86, 151.21, -3, true},
+		},
+		{
+			"noAltitude",
+			map[string]FileMetadataValues{"Composite": {
+				{"GPSLatitude", float64(-33.86)},
+				{"GPSLongitude", float64(151.21)},
+			}},
+			true, nil, GPS{-33.86, 151.21, 0, false},
+		},
+		{
+			"noLongitude",
+			map[string]FileMetadataValues{"EXIF": {{"GPSLatitude", float64(1)}}},
+			false, ErrKeyNotFound, GPS{},
+		},
+		{
+			"invalid",
+			map[string]FileMetadataValues{"EXIF": {{"GPSLatitude", "abc"}, {"GPSLongitude", float64(1)}}},
+			false, nil, GPS{},
+		},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			gps, err := FileMetadata{Groups: tc.in}.GPSPosition()
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expErr != nil {
+				assert.Equal(t, tc.expErr, err)
+			}
+			if tc.expOk {
+				assert.InDelta(t, tc.expGPS.Latitude, gps.Latitude, 1e-6)
+				assert.InDelta(t, tc.expGPS.Longitude, gps.Longitude, 1e-6)
+				assert.InDelta(t, tc.expGPS.Altitude, gps.Altitude, 1e-6)
+				assert.Equal(t, tc.expGPS.HasAltitude, gps.HasAltitude)
+			}
+		})
+	}
+}
+
+func TestGPSValues(t *testing.T) {
+	alt := 35.5
+	below := -3.0
+	nan := math.NaN()
+	ts := time.Date(2019, 4, 4, 15, 18, 4, 500000000, time.FixedZone("CEST", 2*3600))
+
+	var tcs = []struct {
+		tcID      string
+		lat, lon  float64
+		alt       *float64
+		t         time.Time
+		expOk     bool
+		expValues FileMetadataValues
+	}{
+		{"northEast", 48.8584, 2.2945, nil, time.Time{}, true, FileMetadataValues{
+			{"GPS:GPSLatitude#", 48.8584}, {"GPS:GPSLatitudeRef#", "N"},
+			{"GPS:GPSLongitude#", 2.2945}, {"GPS:GPSLongitudeRef#", "E"},
+		}},
+		{"southWest", -33.86, -151.21, nil, time.Time{}, true, FileMetadataValues{
+			{"GPS:GPSLatitude#", 33.86}, {"GPS:GPSLatitudeRef#", "S"},
+			{"GPS:GPSLongitude#", 151.21}, {"GPS:GPSLongitudeRef#", "W"},
+		}},
+		{"altitudeAndTime", 0, 0, &alt, ts, true, FileMetadataValues{
+			{"GPS:GPSLatitude#", 0.0}, {"GPS:GPSLatitudeRef#", "N"},
+			{"GPS:GPSLongitude#", 0.0}, {"GPS:GPSLongitudeRef#", "E"},
+			{"GPS:GPSAltitude#", 35.5}, {"GPS:GPSAltitudeRef#", "0"},
+			{"GPS:GPSDateStamp#", "2019:04:04"}, {"GPS:GPSTimeStamp#", "13:18:04.5"},
+		}},
+		{"belowSeaLevel", 1, 1, &below, time.Time{}, true, FileMetadataValues{
+			{"GPS:GPSLatitude#", 1.0}, {"GPS:GPSLatitudeRef#", "N"},
+			{"GPS:GPSLongitude#", 1.0}, {"GPS:GPSLongitudeRef#", "E"},
+			{"GPS:GPSAltitude#", 3.0}, {"GPS:GPSAltitudeRef#", "1"},
+		}},
+		{"invalidLatitude", 91, 0, nil, time.Time{}, false, nil},
+		{"invalidLongitude", 0, -180.5, nil, time.Time{}, false, nil},
+		{"nanLatitude", nan, 0, nil, time.Time{}, false, nil},
+		{"invalidAltitude", 0, 0, &nan, time.Time{}, false, nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			values, err := gpsValues(tc.lat, tc.lon, tc.alt, tc.t)
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.expValues, values)
+			}
+		})
+	}
+}
+
+func TestSetGPS(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	alt := -12.5
+	ts := time.Date(2019, 4, 4, 13, 18, 4, 0, time.UTC)
+	assert.NotNil(t, e.SetGPS(f, 100, 0, nil, ts))
+	assert.Nil(t, e.SetGPS(f, -33.86, -151.21, &alt, ts))
+
+	fms := e.ExtractMetadata(f)
+	assert.Equal(t, 1, len(fms))
+	assert.Nil(t, fms[0].Err)
+	gps, err := fms[0].GPSPosition()
+	assert.Nil(t, err)
+	assert.InDelta(t, -33.86, gps.Latitude, 1e-6)
+	assert.InDelta(t, -151.21, gps.Longitude, 1e-6)
+	assert.InDelta(t, -12.5, gps.Altitude, 1e-6)
+	d, err := fms[0].Groups["EXIF"].GetString("GPSDateStamp")
+	assert.Nil(t, err)
+	assert.Equal(t, "2019:04:04", d)
+}
@@ -0,0 +1,181 @@
+package exiftool
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// HashAlgorithm is a hash algorithm computed by ComputeHashes
+type HashAlgorithm struct {
+	name string
+	new  func() hash.Hash
+}
+
+// Name returns the name of the algorithm, the key of its hash in FileMetadata.Hashes
+func (a HashAlgorithm) Name() string {
+	return a.name
+}
+
+// MD5 is the MD5 HashAlgorithm
+func MD5() HashAlgorithm {
+	return HashAlgorithm{name: "MD5", new: md5.New}
+}
+
+// SHA1 is the SHA-1 HashAlgorithm
+func SHA1() HashAlgorithm {
+	return HashAlgorithm{name: "SHA1", new: sha1.New}
+}
+
+// SHA256 is the SHA-256 HashAlgorithm
+func SHA256() HashAlgorithm {
+	return HashAlgorithm{name: "SHA256", new: sha256.New}
+}
+
+// CustomHash is a HashAlgorithm named name, computed with the hashes returned by newHash
+// (ie. xxhash.New of github.com/cespare/xxhash)
+// Sample :
+//   e, err := NewExiftool(ComputeHashes(SHA256(), CustomHash("XXH64", func() hash.Hash { return xxhash.New() })))
+func CustomHash(name string, newHash func() hash.Hash) HashAlgorithm {
+	return HashAlgorithm{name: name, new: newHash}
+}
+
+// ComputeHashes computes the hashes of the extracted files with algs, stored in
+// FileMetadata.Hashes (hexadecimal encoded, indexed by algorithm name). Each file is
+// read once more, through the FileSystem, for all the algorithms at the same time, and
+// concurrently with its extraction by exiftool: the file is read twice, but the hashing
+// doesn't delay the extraction. If a file can't be hashed, the error is returned in
+// FileMetadata.Err. With SetCache, the hashes are cached along with the extractions.
+// Sample :
+//   e, err := NewExiftool(ComputeHashes(MD5(), SHA256()))
+func ComputeHashes(algs ...HashAlgorithm) Option {
+	return func(e *Exiftool) error {
+		if len(algs) == 0 {
+			return fmt.Errorf("no hash algorithm provided")
+		}
+		names := map[string]bool{}
+		for _, a := range algs {
+			if a.name == "" || a.new == nil {
+				return fmt.Errorf("invalid hash algorithm (%q)", a.name)
+			}
+			if names[a.name] {
+				return fmt.Errorf("duplicated hash algorithm (%v)", a.name)
+			}
+			names[a.name] = true
+		}
+		e.hashes = append([]HashAlgorithm{}, algs...)
+		return nil
+	}
+}
+
+// fileHashes are the hashes of a file, or the error that occurred while computing them
+type fileHashes struct {
+	hashes map[string]string
+	err    error
+}
+
+// hashFile computes the hashes of file, a file of fsys, with algs in a single read
+func hashFile(fsys FileSystem, file string, algs []HashAlgorithm) (map[string]string, error) {
+	f, err := fsys.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	hs := make([]hash.Hash, len(algs))
+	ws := make([]io.Writer, len(algs))
+	for i, a := range algs {
+		hs[i] = a.new()
+		ws[i] = hs[i]
+	}
+	if _, err := io.Copy(io.MultiWriter(ws...), f); err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]string, len(algs))
+	for i, a := range algs {
+		res[a.name] = hex.EncodeToString(hs[i].Sum(nil))
+	}
+	return res, nil
+}
+
+// startHashing starts computing the hashes of files in the background, except for the
+// skipped ones, the returned function waiting for them
+func (e *Exiftool) startHashing(files []string, skip []bool) func() []fileHashes {
+	res := make([]fileHashes, len(files))
+	if len(e.hashes) == 0 {
+		return func() []fileHashes { return res }
+	}
+
+	fsys := e.fileSystem()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i, f := range files {
+			if !skip[i] {
+				res[i].hashes, res[i].err = hashFile(fsys, f, e.hashes)
+			}
+		}
+	}()
+	return func() []fileHashes {
+		<-done
+		return res
+	}
+}
+
+// attachHashes sets the hashes of fm, or its error if they couldn't be computed
+func attachHashes(fm *FileMetadata, fh fileHashes) {
+	if fm.Err != nil || (fh.hashes == nil && fh.err == nil) {
+		return
+	}
+	if fh.err != nil {
+		fm.Err = fmt.Errorf("error while hashing %v: %w", fm.File, fh.err)
+		return
+	}
+	fm.Hashes = fh.hashes
+}
+
+// hashCacheKeys returns the CacheKeys of the extractions of files configured by cfg when
+// both the cache and the hashes are enabled, and which of the files have a cached
+// extraction already holding their hashes, that don't have to be computed again
+func (e *Exiftool) hashCacheKeys(cfg extractConfig, files []string) ([]CacheKey, []bool) {
+	keys := make([]CacheKey, len(files))
+	cached := make([]bool, len(files))
+	if e.cache == nil || len(e.hashes) == 0 {
+		return keys, cached
+	}
+
+	args := append(append([]string{}, e.extraInitArgs...), e.extractArgs(cfg)...)
+	for i, f := range files {
+		k, err := cacheKey(e.fileSystem(), f, args)
+		if err != nil {
+			continue
+		}
+		keys[i] = k
+		if fm, found := e.cache.Get(k); found && !cfg.refresh {
+			cached[i] = hasHashes(fm, e.hashes)
+		}
+	}
+	return keys, cached
+}
+
+// hasHashes returns true if fm holds the hashes of algs
+func hasHashes(fm FileMetadata, algs []HashAlgorithm) bool {
+	for _, a := range algs {
+		if _, found := fm.Hashes[a.name]; !found {
+			return false
+		}
+	}
+	return true
+}
+
+// cacheHashes stores fm, just hashed with fh, under k so that it isn't hashed again
+func (e *Exiftool) cacheHashes(k CacheKey, fm FileMetadata, fh fileHashes) {
+	if k.File != "" && fm.Err == nil && fh.hashes != nil {
+		e.cache.Set(k, fm)
+	}
+}
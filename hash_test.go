@@ -0,0 +1,166 @@
+package exiftool
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"hash/crc32"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeHashesOption(t *testing.T) {
+	crc := func() hash.Hash { return crc32.NewIEEE() }
+
+	var tcs = []struct {
+		tcID     string
+		in       []HashAlgorithm
+		expOk    bool
+		expNames []string
+	}{
+		{"nominal", []HashAlgorithm{MD5(), SHA1(), SHA256(), CustomHash("CRC32", crc)}, true, []string{"MD5", "SHA1", "SHA256", "CRC32"}},
+		{"none", nil, false, nil},
+		{"noName", []HashAlgorithm{CustomHash("", crc)}, false, nil},
+		{"noFunc", []HashAlgorithm{CustomHash("CRC32", nil)}, false, nil},
+		{"duplicated", []HashAlgorithm{MD5(), MD5()}, false, nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			e := Exiftool{}
+			err := ComputeHashes(tc.in...)(&e)
+			assert.Equal(t, tc.expOk, err == nil)
+			var names []string
+			for _, a := range e.hashes {
+				names = append(names, a.Name())
+			}
+			assert.Equal(t, tc.expNames, names)
+		})
+	}
+}
+
+func TestHashFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-exiftool")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	f := filepath.Join(dir, "a.txt")
+	assert.Nil(t, ioutil.WriteFile(f, []byte("abc"), 0644))
+
+	hs, err := hashFile(OSFileSystem{}, f, []HashAlgorithm{MD5(), SHA1(), SHA256()})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"MD5":    "900150983cd24fb0d6963f7d28e17f72",
+		"SHA1":   "a9993e364706816aba3e25717850c26c9cd0d89d",
+		"SHA256": "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+	}, hs)
+
+	_, err = hashFile(OSFileSystem{}, filepath.Join(dir, "nonExisting"), []HashAlgorithm{MD5()})
+	assert.NotNil(t, err)
+}
+
+func TestHashFileFS(t *testing.T) {
+	m := newMemFileSystem()
+	m.files["a.txt"] = []byte("abc")
+
+	hs, err := hashFile(m, "a.txt", []HashAlgorithm{MD5()})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"MD5": "900150983cd24fb0d6963f7d28e17f72"}, hs)
+
+	_, err = hashFile(m, "nonExisting", []HashAlgorithm{MD5()})
+	assert.NotNil(t, err)
+}
+
+func TestHashCacheKeys(t *testing.T) {
+	files := []string{"./testdata/20190404_131804.jpg", "./testdata/nonExisting"}
+	e := &Exiftool{hashes: []HashAlgorithm{SHA256()}}
+	cfg := e.extractConfig()
+
+	keys, cached := e.hashCacheKeys(cfg, files)
+	assert.Equal(t, []CacheKey{{}, {}}, keys)
+	assert.Equal(t, []bool{false, false}, cached)
+
+	e.cache = NewLRUCache(10)
+	keys, cached = e.hashCacheKeys(cfg, files)
+	assert.Equal(t, files[0], keys[0].File)
+	assert.Equal(t, CacheKey{}, keys[1])
+	assert.Equal(t, []bool{false, false}, cached)
+
+	e.cacheHashes(keys[0], FileMetadata{File: files[0]}, fileHashes{})
+	_, cached = e.hashCacheKeys(cfg, files)
+	assert.Equal(t, []bool{false, false}, cached)
+
+	e.cacheHashes(keys[0], FileMetadata{File: files[0], Hashes: map[string]string{"SHA256": "abc"}}, fileHashes{hashes: map[string]string{"SHA256": "abc"}})
+	_, cached = e.hashCacheKeys(cfg, files)
+	assert.Equal(t, []bool{true, false}, cached)
+
+	e.hashes = []HashAlgorithm{SHA256(), MD5()}
+	_, cached = e.hashCacheKeys(cfg, files)
+	assert.Equal(t, []bool{false, false}, cached)
+}
+
+func TestAttachHashes(t *testing.T) {
+	hs := map[string]string{"MD5": "900150983cd24fb0d6963f7d28e17f72"}
+	failure := errors.New("failure")
+
+	var tcs = []struct {
+		tcID      string
+		inErr     error
+		in        fileHashes
+		expErr    bool
+		expHashes map[string]string
+	}{
+		{"nominal", nil, fileHashes{hashes: hs}, false, hs},
+		{"disabled", nil, fileHashes{}, false, nil},
+		{"hashError", nil, fileHashes{err: failure}, true, nil},
+		{"extractionError", failure, fileHashes{hashes: hs}, true, nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			fm := FileMetadata{File: "a.jpg", Err: tc.inErr}
+			attachHashes(&fm, tc.in)
+			assert.Equal(t, tc.expErr, fm.Err != nil)
+			assert.Equal(t, tc.expHashes, fm.Hashes)
+		})
+	}
+}
+
+func TestNewExifTool_WithComputeHashes(t *testing.T) {
+	b, err := ioutil.ReadFile("./testdata/20190404_131804.jpg")
+	assert.Nil(t, err)
+	sum := sha256.Sum256(b)
+
+	e, err := NewExiftool(ComputeHashes(SHA256()))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	fms := e.Extract([]string{"./testdata/20190404_131804.jpg", "./testdata/nonExisting", "./testdata/20190404_131804.jpg"}, WithBatchSize(2))
+	assert.Len(t, fms, 3)
+	assert.Nil(t, fms[0].Err)
+	assert.Equal(t, map[string]string{"SHA256": hex.EncodeToString(sum[:])}, fms[0].Hashes)
+	assert.NotNil(t, fms[1].Err)
+	assert.Nil(t, fms[1].Hashes)
+	assert.Nil(t, fms[2].Err)
+	assert.Equal(t, fms[0].Hashes, fms[2].Hashes)
+
+	// cached along with the extraction
+	c := NewLRUCache(10)
+	e2, err := NewExiftool(ComputeHashes(SHA256()), SetCache(c))
+	assert.Nil(t, err)
+	defer e2.Close()
+	for i := 0; i < 2; i++ {
+		fm := e2.ExtractMetadata("./testdata/20190404_131804.jpg")[0]
+		assert.Nil(t, fm.Err)
+		assert.Equal(t, fms[0].Hashes, fm.Hashes)
+	}
+	assert.Equal(t, 1, c.Len())
+	_, cached := e2.hashCacheKeys(e2.extractConfig(), []string{"./testdata/20190404_131804.jpg"})
+	assert.Equal(t, []bool{true}, cached)
+}
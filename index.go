@@ -0,0 +1,119 @@
+package exiftool
+
+import (
+	"math/big"
+	"time"
+)
+
+// IndexedValues is a FileMetadataValues whose fields are indexed by label, so that the
+// typed getters don't scan every value. Iterating over FileMetadataValues keeps the
+// original exiftool order. It must be rebuilt if FileMetadataValues is modified.
+type IndexedValues struct {
+	FileMetadataValues
+	index map[string]int
+}
+
+// Index builds the IndexedValues of g. When a label appears several times, the first
+// value is kept, like the FileMetadataValues getters do.
+// Sample :
+//   exif := fm.Groups["EXIF"].Index()
+//   iso, err := exif.GetInt("ISO")
+func (g FileMetadataValues) Index() IndexedValues {
+	idx := make(map[string]int, len(g))
+	for i, f := range g {
+		if _, found := idx[f.Label]; !found {
+			idx[f.Label] = i
+		}
+	}
+
+	return IndexedValues{FileMetadataValues: g, index: idx}
+}
+
+// IndexedGroups builds the IndexedValues of every group
+func (fm FileMetadata) IndexedGroups() map[string]IndexedValues {
+	res := make(map[string]IndexedValues, len(fm.Groups))
+	for n, g := range fm.Groups {
+		res[n] = g.Index()
+	}
+
+	return res
+}
+
+func (g IndexedValues) field(k string) (interface{}, bool) {
+	i, found := g.index[k]
+	if !found {
+		return nil, false
+	}
+
+	return g.FileMetadataValues[i].Value, true
+}
+
+// GetString behaves like FileMetadataValues.GetString
+func (g IndexedValues) GetString(k string) (string, error) {
+	return getString(g, k)
+}
+
+// GetFloat behaves like FileMetadataValues.GetFloat
+func (g IndexedValues) GetFloat(k string) (float64, error) {
+	return getFloat(g, k)
+}
+
+// GetInt behaves like FileMetadataValues.GetInt
+func (g IndexedValues) GetInt(k string) (int64, error) {
+	return getInt(g, k)
+}
+
+// GetBool behaves like FileMetadataValues.GetBool
+func (g IndexedValues) GetBool(k string) (bool, error) {
+	return getBool(g, k)
+}
+
+// GetStrings behaves like FileMetadataValues.GetStrings
+func (g IndexedValues) GetStrings(k string) ([]string, error) {
+	return getStrings(g, k)
+}
+
+// GetInts behaves like FileMetadataValues.GetInts
+func (g IndexedValues) GetInts(k string) ([]int64, error) {
+	return getInts(g, k)
+}
+
+// GetFloats behaves like FileMetadataValues.GetFloats
+func (g IndexedValues) GetFloats(k string) ([]float64, error) {
+	return getFloats(g, k)
+}
+
+// GetDate behaves like FileMetadataValues.GetDate
+func (g IndexedValues) GetDate(k string) (time.Time, error) {
+	return getDate(g, k)
+}
+
+// GetDuration behaves like FileMetadataValues.GetDuration
+func (g IndexedValues) GetDuration(k string) (time.Duration, error) {
+	return getDuration(g, k)
+}
+
+// GetFraction behaves like FileMetadataValues.GetFraction
+func (g IndexedValues) GetFraction(k string) (*big.Rat, error) {
+	return getFraction(g, k)
+}
+
+// GetRational behaves like FileMetadataValues.GetRational
+func (g IndexedValues) GetRational(k string) (int64, int64, error) {
+	return getRational(g, k)
+}
+
+// GetStruct behaves like FileMetadataValues.GetStruct
+func (g IndexedValues) GetStruct(k string) (FileMetadataValues, error) {
+	return getStruct(g, k)
+}
+
+// GetStructs behaves like FileMetadataValues.GetStructs
+func (g IndexedValues) GetStructs(k string) ([]FileMetadataValues, error) {
+	return getStructs(g, k)
+}
+
+// GetBytes behaves like FileMetadataValues.GetBytes
+func (g IndexedValues) GetBytes(k string) ([]byte, error) {
+	return getBytes(g, k)
+}
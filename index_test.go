@@ -0,0 +1,85 @@
+package exiftool
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndex(t *testing.T) {
+	g := FileMetadataValues{
+		{"string", "value"},
+		{"float", float64(3.14)},
+		{"integer", int64(42)},
+		{"array", []interface{}{"a", "b"}},
+		{"date", "2019:04:04 13:18:03"},
+		{"string", "duplicate"},
+		{"binary", "base64:AQI="},
+	}
+	idx := g.Index()
+
+	assert.Equal(t, g, idx.FileMetadataValues)
+
+	s, err := idx.GetString("string")
+	assert.Nil(t, err)
+	assert.Equal(t, "value", s)
+
+	f, err := idx.GetFloat("float")
+	assert.Nil(t, err)
+	assert.Equal(t, float64(3.14), f)
+
+	i, err := idx.GetInt("integer")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(42), i)
+
+	a, err := idx.GetStrings("array")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, a)
+
+	d, err := idx.GetDate("date")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2019, 4, 4, 13, 18, 3, 0, time.UTC), d)
+
+	b, err := idx.GetBytes("binary")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{1, 2}, b)
+
+	_, err = idx.GetString("unexisting")
+	assert.Equal(t, ErrKeyNotFound, err)
+}
+
+func TestIndexedGroups(t *testing.T) {
+	fm := getExpectedFileMetadata()
+	grps := fm.IndexedGroups()
+	assert.Equal(t, 1, len(grps))
+
+	for k, v := range fm.Groups["fields"] {
+		assert.Equal(t, v, grps["fields"].FileMetadataValues[k])
+	}
+
+	s, err := grps["fields"].GetString("stringMono")
+	assert.Nil(t, err)
+	assert.Equal(t, "stringMonoValue", s)
+}
+
+func BenchmarkGetString(b *testing.B) {
+	g := FileMetadataValues{}
+	for i := 0; i < 500; i++ {
+		g = append(g, FileMetadataValue{Label: fmt.Sprintf("label%v", i), Value: "v"})
+	}
+	idx := g.Index()
+	last := g[len(g)-1].Label
+
+	b.Run("linear", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			g.GetString(last)
+		}
+	})
+	b.Run("indexed", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			idx.GetString(last)
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package exiftool
+
+import (
+	"fmt"
+	"strings"
+)
+
+// hierarchySeparator separates the levels of a hierarchical keyword (ie. "Places|France")
+const hierarchySeparator = "|"
+
+var (
+	keywordsKeys = [][]string{
+		{"IPTC:Keywords"},
+		{"XMP-dc:Subject", "XMP:Subject"},
+	}
+	hierarchicalSubjectKeys = []string{"XMP-lr:HierarchicalSubject", "XMP:HierarchicalSubject"}
+)
+
+// AddKeywords adds keywords to file without overwriting the existing ones, nor adding a
+// keyword twice. Flat keywords are added to IPTC:Keywords and XMP-dc:Subject while
+// hierarchical keywords (ie. "Places|France|Paris") are added to XMP-lr:HierarchicalSubject
+// and their last level to the flat keywords. If anything went wrong, a non empty error
+// will be returned.
+// Sample :
+//   err := e.AddKeywords("photo.jpg", "holidays", "Places|France|Paris")
+func (e *Exiftool) AddKeywords(file string, keywords ...string) error {
+	return e.editKeywords(file, keywords, func(tag, kw string) []string {
+		// removing first is the exiftool way of avoiding duplicates
+		return []string{fmt.Sprintf("-%v-=%v", tag, kw), fmt.Sprintf("-%v+=%v", tag, kw)}
+	})
+}
+
+// RemoveKeywords removes keywords from file, see AddKeywords. Removing a hierarchical
+// keyword also removes its last level from the flat keywords. If anything went wrong, a
+// non empty error will be returned.
+func (e *Exiftool) RemoveKeywords(file string, keywords ...string) error {
+	return e.editKeywords(file, keywords, func(tag, kw string) []string {
+		return []string{fmt.Sprintf("-%v-=%v", tag, kw)}
+	})
+}
+
+// editKeywords writes the arguments returned by edit for every keyword tag to edit
+func (e *Exiftool) editKeywords(file string, keywords []string, edit func(tag, kw string) []string) error {
+	if len(keywords) == 0 {
+		return fmt.Errorf("no keyword provided")
+	}
+	if err := e.checkFile(file); err != nil {
+		return err
+	}
+
+	var args []string
+	for _, kw := range keywords {
+		if strings.TrimSpace(kw) == "" || strings.ContainsAny(kw, "\r\n") {
+			return fmt.Errorf("invalid keyword (%q)", kw)
+		}
+		flat := kw
+		if levels := strings.Split(kw, hierarchySeparator); len(levels) > 1 {
+			flat = levels[len(levels)-1]
+			args = append(args, edit(hierarchicalSubjectKeys[0], kw)...)
+		}
+		for _, keys := range keywordsKeys {
+			args = append(args, edit(keys[0], flat)...)
+		}
+	}
+
+	return e.executeWrite(file, args)
+}
+
+// GetKeywords returns the flat keywords of the file (IPTC:Keywords and XMP-dc:Subject
+// merged, without duplicates). ErrKeyNotFound will be returned if the file has no
+// keyword.
+func (fm FileMetadata) GetKeywords() ([]string, error) {
+	var res []string
+	seen := map[string]bool{}
+	found := false
+	for _, keys := range keywordsKeys {
+		kws, ok := fm.lookupStrings(keys...)
+		found = found || ok
+		for _, kw := range kws {
+			if !seen[kw] {
+				seen[kw] = true
+				res = append(res, kw)
+			}
+		}
+	}
+
+	if !found {
+		return nil, ErrKeyNotFound
+	}
+
+	return res, nil
+}
+
+// GetHierarchicalKeywords returns the hierarchical keywords of the file
+// (XMP-lr:HierarchicalSubject), each one being split into its levels. ErrKeyNotFound will
+// be returned if the file has no hierarchical keyword.
+func (fm FileMetadata) GetHierarchicalKeywords() ([][]string, error) {
+	kws, found := fm.lookupStrings(hierarchicalSubjectKeys...)
+	if !found {
+		return nil, ErrKeyNotFound
+	}
+
+	res := make([][]string, len(kws))
+	for i, kw := range kws {
+		res[i] = strings.Split(kw, hierarchySeparator)
+	}
+
+	return res, nil
+}
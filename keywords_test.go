@@ -0,0 +1,89 @@
+package exiftool
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetKeywords(t *testing.T) {
+	var tcs = []struct {
+		tcID   string
+		in     map[string]FileMetadataValues
+		expOk  bool
+		expKws []string
+	}{
+		{
+			"merged",
+			map[string]FileMetadataValues{
+				"IPTC": {{"Keywords", []interface{}{"a", "b"}}},
+				"XMP":  {{"Subject", []interface{}{"b", "c"}}},
+			},
+			true,
+			[]string{"a", "b", "c"},
+		},
+		{"single", map[string]FileMetadataValues{"IPTC": {{"Keywords", "a"}}}, true, []string{"a"}},
+		{"family1", map[string]FileMetadataValues{"XMP:XMP-dc": {{"Subject", []interface{}{"a"}}}}, true, []string{"a"}},
+		{"none", map[string]FileMetadataValues{"File": {{"FileName", "a.jpg"}}}, false, nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			kws, err := FileMetadata{Groups: tc.in}.GetKeywords()
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.expKws, kws)
+			} else {
+				assert.Equal(t, ErrKeyNotFound, err)
+			}
+		})
+	}
+}
+
+func TestGetHierarchicalKeywords(t *testing.T) {
+	fm := FileMetadata{Groups: map[string]FileMetadataValues{
+		"XMP": {{"HierarchicalSubject", []interface{}{"Places|France|Paris", "People"}}},
+	}}
+	kws, err := fm.GetHierarchicalKeywords()
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{{"Places", "France", "Paris"}, {"People"}}, kws)
+
+	_, err = FileMetadata{}.GetHierarchicalKeywords()
+	assert.Equal(t, ErrKeyNotFound, err)
+}
+
+func TestAddAndRemoveKeywords(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	assert.NotNil(t, e.AddKeywords(f))
+	assert.NotNil(t, e.AddKeywords(f, " "))
+	assert.NotNil(t, e.AddKeywords(f, "a\nb"))
+	assert.True(t, errors.Is(e.AddKeywords("./testdata/nonExisting", "a"), ErrNotExist))
+
+	assert.Nil(t, e.AddKeywords(f, "holidays", "Places|France|Paris"))
+	assert.Nil(t, e.AddKeywords(f, "holidays", "beach"))
+
+	fms := e.ExtractMetadata(f)
+	assert.Equal(t, 1, len(fms))
+	kws, err := fms[0].GetKeywords()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"holidays", "Paris", "beach"}, kws)
+	hkws, err := fms[0].GetHierarchicalKeywords()
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{{"Places", "France", "Paris"}}, hkws)
+
+	assert.Nil(t, e.RemoveKeywords(f, "holidays", "Places|France|Paris"))
+
+	fms = e.ExtractMetadata(f)
+	assert.Equal(t, 1, len(fms))
+	kws, err = fms[0].GetKeywords()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"beach"}, kws)
+}
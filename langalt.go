@@ -0,0 +1,169 @@
+package exiftool
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// defaultLang is the language of the default value of a language alternative
+const defaultLang = "x-default"
+
+// langRegexp matches the RFC 3066 language codes (ie. "fr", "en-US") suffixing the
+// labels of the language variants printed by exiftool, tag names never containing "-"
+var langRegexp = regexp.MustCompile(`^[a-zA-Z]{1,8}(-[a-zA-Z0-9]{1,8})*$`)
+
+// LangAlt is an XMP language alternative (ie. XMP-dc:Title, XMP-dc:Description), the
+// values being indexed by language code, "x-default" being the default one. Its String
+// method returns the default value, so that GetString keeps returning it once the
+// variants are merged (see MergeLangAlt).
+type LangAlt map[string]string
+
+func (l LangAlt) String() string {
+	if v, found := l[defaultLang]; found {
+		return v
+	}
+	if langs := l.Langs(); len(langs) > 0 {
+		return l[langs[0]]
+	}
+	return ""
+}
+
+// Langs returns the language codes of l, sorted alphabetically
+func (l LangAlt) Langs() []string {
+	langs := make([]string, 0, len(l))
+	for lang := range l {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// MergeLangAlt merges the language variants that exiftool prints as distinct tags
+// ("Title", "Title-fr", "Title-en-US") into a single LangAlt value labelled after the
+// tag, the value printed without language being the "x-default" one. The JSON encoding
+// of the merged tags becomes a JSON object.
+// Sample :
+//   e, err := NewExiftool(MergeLangAlt())
+func MergeLangAlt() Option {
+	return func(e *Exiftool) error {
+		e.langAlt = true
+		return nil
+	}
+}
+
+// splitLang returns the tag and the language of label, ok being false if label isn't
+// a language variant
+func splitLang(label string) (string, string, bool) {
+	idx := strings.Index(label, "-")
+	if idx < 1 || !langRegexp.MatchString(label[idx+1:]) {
+		return "", "", false
+	}
+	return label[:idx], label[idx+1:], true
+}
+
+// mergeLangAlt returns g where the language variants of each tag are merged into a
+// single LangAlt value, at the position of the first variant
+func mergeLangAlt(g FileMetadataValues) FileMetadataValues {
+	variants := map[string]bool{}
+	for _, v := range g {
+		if tag, _, ok := splitLang(v.Label); ok {
+			variants[tag] = true
+		}
+	}
+	if len(variants) == 0 {
+		return g
+	}
+
+	res := make(FileMetadataValues, 0, len(g))
+	pos := map[string]int{}
+	for _, v := range g {
+		tag, lang := v.Label, defaultLang
+		if t, l, ok := splitLang(v.Label); ok {
+			tag, lang = t, l
+		} else if !variants[tag] {
+			res = append(res, v)
+			continue
+		}
+
+		i, found := pos[tag]
+		if !found {
+			i = len(res)
+			pos[tag] = i
+			res = append(res, FileMetadataValue{Label: tag, Value: LangAlt{}})
+		}
+		res[i].Value.(LangAlt)[lang] = toString(v.Value)
+	}
+	return res
+}
+
+// GetLangAlt returns the language variants of the tag k, whether they have been merged
+// (see MergeLangAlt) or not. ErrKeyNotFound will be returned if the tag has no variant.
+func (g FileMetadataValues) GetLangAlt(k string) (LangAlt, error) {
+	l := LangAlt{}
+	for _, v := range g {
+		if v.Label == k {
+			if la, ok := v.Value.(LangAlt); ok {
+				for lang, s := range la {
+					l[lang] = s
+				}
+				continue
+			}
+			l[defaultLang] = toString(v.Value)
+			continue
+		}
+		if tag, lang, ok := splitLang(v.Label); ok && tag == k {
+			l[lang] = toString(v.Value)
+		}
+	}
+
+	if len(l) == 0 {
+		return nil, ErrKeyNotFound
+	}
+	return l, nil
+}
+
+// langAltValues returns the values writing the variants of values to the tag, an empty
+// variant deleting it
+func langAltValues(tag string, values LangAlt) (FileMetadataValues, error) {
+	if tag == "" {
+		return nil, fmt.Errorf("empty tag")
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("no language variant provided")
+	}
+
+	var vals FileMetadataValues
+	for _, lang := range values.Langs() {
+		label := tag
+		switch {
+		case lang == "" || strings.EqualFold(lang, defaultLang):
+		case langRegexp.MatchString(lang):
+			label += "-" + lang
+		default:
+			return nil, fmt.Errorf("invalid language (%v)", lang)
+		}
+
+		var v interface{}
+		if s := values[lang]; s != "" {
+			v = s
+		}
+		vals = append(vals, FileMetadataValue{Label: label, Value: v})
+	}
+	return vals, nil
+}
+
+// SetLangAlt writes the language variants of values (indexed by language code,
+// "x-default" or "" for the default one) to the language alternative tag of file, the
+// other variants being kept. An empty value deletes the variant. If anything went wrong,
+// a non empty error will be returned.
+// Sample :
+//   err := e.SetLangAlt("a.jpg", "XMP-dc:Description", LangAlt{"x-default": "A cat", "fr": "Un chat"})
+func (e *Exiftool) SetLangAlt(file, tag string, values LangAlt) error {
+	vals, err := langAltValues(tag, values)
+	if err != nil {
+		return err
+	}
+	return e.Write(file, vals)
+}
@@ -0,0 +1,146 @@
+package exiftool
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitLang(t *testing.T) {
+	var tcs = []struct {
+		in      string
+		expOk   bool
+		expTag  string
+		expLang string
+	}{
+		{"Title-fr", true, "Title", "fr"},
+		{"Description-en-US", true, "Description", "en-US"},
+		{"Title", false, "", ""},
+		{"-fr", false, "", ""},
+		{"Title-", false, "", ""},
+		{"Title-f r", false, "", ""},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.in, func(t *testing.T) {
+			tag, lang, ok := splitLang(tc.in)
+			assert.Equal(t, tc.expOk, ok)
+			assert.Equal(t, tc.expTag, tag)
+			assert.Equal(t, tc.expLang, lang)
+		})
+	}
+}
+
+func TestMergeLangAlt(t *testing.T) {
+	in := FileMetadataValues{
+		{"Rating", float64(5)},
+		{"Title", "A cat"},
+		{"Description-fr", "Un chat"},
+		{"Title-fr", "Un chat"},
+		{"Title-de-CH", "Eine Katze"},
+		{"Description", "A cat"},
+	}
+	assert.Equal(t, FileMetadataValues{
+		{"Rating", float64(5)},
+		{"Title", LangAlt{"x-default": "A cat", "fr": "Un chat", "de-CH": "Eine Katze"}},
+		{"Description", LangAlt{"fr": "Un chat", "x-default": "A cat"}},
+	}, mergeLangAlt(in))
+
+	noVariant := FileMetadataValues{{"Title", "A cat"}}
+	assert.Equal(t, noVariant, mergeLangAlt(noVariant))
+}
+
+func TestLangAltString(t *testing.T) {
+	assert.Equal(t, "A cat", LangAlt{"fr": "Un chat", "x-default": "A cat"}.String())
+	assert.Equal(t, "Eine Katze", LangAlt{"fr": "Un chat", "de": "Eine Katze"}.String())
+	assert.Equal(t, "", LangAlt{}.String())
+	assert.Equal(t, []string{"de", "fr"}, LangAlt{"fr": "Un chat", "de": "Eine Katze"}.Langs())
+
+	s, err := FileMetadataValues{{"Title", LangAlt{"x-default": "A cat"}}}.GetString("Title")
+	assert.Nil(t, err)
+	assert.Equal(t, "A cat", s)
+}
+
+func TestGetLangAlt(t *testing.T) {
+	exp := LangAlt{"x-default": "A cat", "fr": "Un chat"}
+
+	l, err := FileMetadataValues{{"Title", "A cat"}, {"Title-fr", "Un chat"}, {"TitleBis-de", "Katze"}}.GetLangAlt("Title")
+	assert.Nil(t, err)
+	assert.Equal(t, exp, l)
+
+	merged := FileMetadataValues{{"Title", LangAlt{"x-default": "A cat", "fr": "Un chat"}}}
+	l, err = merged.GetLangAlt("Title")
+	assert.Nil(t, err)
+	assert.Equal(t, exp, l)
+	l["de"] = "Katze"
+	assert.Equal(t, 2, len(merged[0].Value.(LangAlt)))
+
+	_, err = FileMetadataValues{{"Title-fr", "Un chat"}}.GetLangAlt("Description")
+	assert.Equal(t, ErrKeyNotFound, err)
+}
+
+func TestMergeLangAltOption(t *testing.T) {
+	e := Exiftool{}
+	assert.Nil(t, MergeLangAlt()(&e))
+
+	raw := json.RawMessage(`{"SourceFile": "a.jpg", "XMP": {"Title": "A cat", "Title-fr": "Un chat"}}`)
+	fm := FileMetadata{File: "a.jpg"}
+	e.decodeRaw(&fm, raw, nil)
+	assert.Nil(t, fm.Err)
+	assert.Equal(t, FileMetadataValues{{"Title", LangAlt{"x-default": "A cat", "fr": "Un chat"}}}, fm.Groups["XMP"])
+}
+
+func TestLangAltValues(t *testing.T) {
+	var tcs = []struct {
+		tcID    string
+		inTag   string
+		in      LangAlt
+		expOk   bool
+		expVals FileMetadataValues
+	}{
+		{"nominal", "XMP-dc:Title", LangAlt{"x-default": "A cat", "fr": "Un chat", "de-CH": "Eine Katze"}, true, FileMetadataValues{
+			{"XMP-dc:Title-de-CH", "Eine Katze"},
+			{"XMP-dc:Title-fr", "Un chat"},
+			{"XMP-dc:Title", "A cat"},
+		}},
+		{"emptyLang", "Title", LangAlt{"": "A cat"}, true, FileMetadataValues{{"Title", "A cat"}}},
+		{"delete", "Title", LangAlt{"fr": ""}, true, FileMetadataValues{{"Title-fr", nil}}},
+		{"emptyTag", "", LangAlt{"fr": "Un chat"}, false, nil},
+		{"noVariant", "Title", LangAlt{}, false, nil},
+		{"invalidLang", "Title", LangAlt{"f r": "Un chat"}, false, nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			vals, err := langAltValues(tc.inTag, tc.in)
+			assert.Equal(t, tc.expOk, err == nil)
+			assert.Equal(t, tc.expVals, vals)
+		})
+	}
+}
+
+func TestSetLangAlt(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+
+	e, err := NewExiftool(MergeLangAlt())
+	assert.Nil(t, err)
+	defer e.Close()
+
+	assert.Nil(t, e.SetLangAlt(f, "XMP-dc:Title", LangAlt{"x-default": "A cat", "fr": "Un chat"}))
+	assert.Nil(t, e.SetLangAlt(f, "XMP-dc:Title", LangAlt{"de": "Eine Katze"}))
+
+	fms := e.ExtractMetadata(f)
+	assert.Nil(t, fms[0].Err)
+	l, err := fms[0].Groups["XMP"].GetLangAlt("Title")
+	assert.Nil(t, err)
+	assert.Equal(t, LangAlt{"x-default": "A cat", "fr": "Un chat", "de": "Eine Katze"}, l)
+
+	assert.Nil(t, e.SetLangAlt(f, "XMP-dc:Title", LangAlt{"fr": ""}))
+	l, err = e.ExtractMetadata(f)[0].Groups["XMP"].GetLangAlt("Title")
+	assert.Nil(t, err)
+	assert.Equal(t, LangAlt{"x-default": "A cat", "de": "Eine Katze"}, l)
+}
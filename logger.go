@@ -0,0 +1,38 @@
+package exiftool
+
+// Logger receives the events of an Exiftool: msg describes the event and keyvals are
+// alternating keys (strings) and values describing it. The following events are logged:
+//   - "command" (args, duration, err): a command sent to the exiftool process
+//   - "extraction" (file, duration, err): the extraction of a file
+//   - "restart" (attempt, err): an attempt to start a new exiftool process
+//   - "decode error" (file, err): the output of exiftool couldn't be decoded
+type Logger interface {
+	Log(msg string, keyvals ...interface{})
+}
+
+// LoggerFunc is an adapter allowing the use of an ordinary function as a Logger
+// Sample :
+//   l := LoggerFunc(func(msg string, keyvals ...interface{}) { log.Println(msg, keyvals) })
+type LoggerFunc func(msg string, keyvals ...interface{})
+
+// Log calls f(msg, keyvals...)
+func (f LoggerFunc) Log(msg string, keyvals ...interface{}) {
+	f(msg, keyvals...)
+}
+
+// SetLogger defines the Logger receiving the events of the Exiftool
+// Sample :
+//   e, err := NewExiftool(SetLogger(l))
+func SetLogger(l Logger) Option {
+	return func(e *Exiftool) error {
+		e.logger = l
+		return nil
+	}
+}
+
+// log sends an event to the logger if any
+func (e *Exiftool) log(msg string, keyvals ...interface{}) {
+	if e.logger != nil {
+		e.logger.Log(msg, keyvals...)
+	}
+}
@@ -0,0 +1,78 @@
+package exiftool
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type logEvent struct {
+	msg     string
+	keyvals map[string]interface{}
+}
+
+// recordingLogger is a Logger keeping every received event
+type recordingLogger struct {
+	lock   sync.Mutex
+	events []logEvent
+}
+
+func (l *recordingLogger) Log(msg string, keyvals ...interface{}) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	kv := map[string]interface{}{}
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		kv[keyvals[i].(string)] = keyvals[i+1]
+	}
+	l.events = append(l.events, logEvent{msg, kv})
+}
+
+func (l *recordingLogger) messages() []string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	res := make([]string, len(l.events))
+	for i, ev := range l.events {
+		res[i] = ev.msg
+	}
+	return res
+}
+
+func TestLoggerFunc(t *testing.T) {
+	var got []interface{}
+	l := LoggerFunc(func(msg string, keyvals ...interface{}) {
+		got = append([]interface{}{msg}, keyvals...)
+	})
+	l.Log("msg", "k", 1)
+	assert.Equal(t, []interface{}{"msg", "k", 1}, got)
+}
+
+func TestSetLogger(t *testing.T) {
+	l := &recordingLogger{}
+	e := Exiftool{}
+	e.log("ignored")
+	assert.Nil(t, SetLogger(l)(&e))
+
+	var fm FileMetadata
+	e.decode(&fm, []byte("bad"), nil)
+	assert.NotNil(t, fm.Err)
+	assert.Equal(t, []string{"decode error"}, l.messages())
+	assert.Equal(t, fm.Err, l.events[0].keyvals["err"])
+}
+
+func TestNewExifTool_WithLogger(t *testing.T) {
+	l := &recordingLogger{}
+	e, err := NewExiftool(SetLogger(l))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	fms := e.ExtractMetadata("./testdata/20190404_131804.jpg", "./testdata/nonExisting")
+	assert.Equal(t, 2, len(fms))
+	assert.Equal(t, []string{"command", "extraction", "extraction"}, l.messages())
+	assert.Equal(t, "./testdata/20190404_131804.jpg", l.events[1].keyvals["file"])
+	assert.Nil(t, l.events[1].keyvals["err"])
+	assert.Equal(t, fms[1].Err, l.events[2].keyvals["err"])
+	assert.Contains(t, l.events[0].keyvals["args"], "./testdata/20190404_131804.jpg")
+}
@@ -0,0 +1,44 @@
+package exiftool
+
+import "time"
+
+// Metrics receives the measures of an Exiftool or a Pool, so that they can be bound to
+// any instrumentation library (ie. Prometheus counters and histograms, OpenTelemetry
+// instruments). Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObserveExtraction is called once per extracted file with the extraction duration
+	// and its error, nil if the extraction succeeded
+	ObserveExtraction(d time.Duration, err error)
+	// IncDecodeErrors is called each time the output of exiftool couldn't be decoded
+	IncDecodeErrors()
+	// SetQueueDepth is called with the number of extractions waiting for an idle worker
+	// of a Pool each time it changes
+	SetQueueDepth(n int)
+}
+
+// SetMetrics defines the Metrics receiving the measures of the Exiftool, or of the Pool
+// when used with NewPool
+// Sample :
+//   e, err := NewExiftool(SetMetrics(m))
+func SetMetrics(m Metrics) Option {
+	return func(e *Exiftool) error {
+		e.metrics = m
+		return nil
+	}
+}
+
+// observeExtraction reports the extraction of fm, that took d
+func (e *Exiftool) observeExtraction(fm FileMetadata, d time.Duration) {
+	e.log("extraction", "file", fm.File, "duration", d, "err", fm.Err)
+	if e.metrics != nil {
+		e.metrics.ObserveExtraction(d, fm.Err)
+	}
+}
+
+// decodeFailed reports that the output of exiftool for fm couldn't be decoded
+func (e *Exiftool) decodeFailed(fm *FileMetadata) {
+	e.log("decode error", "file", fm.File, "err", fm.Err)
+	if e.metrics != nil {
+		e.metrics.IncDecodeErrors()
+	}
+}
@@ -0,0 +1,87 @@
+package exiftool
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingMetrics is a Metrics keeping every received measure
+type recordingMetrics struct {
+	lock         sync.Mutex
+	extractions  int
+	failures     int
+	decodeErrors int
+	depths       []int
+}
+
+func (m *recordingMetrics) ObserveExtraction(d time.Duration, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.extractions++
+	if err != nil {
+		m.failures++
+	}
+}
+
+func (m *recordingMetrics) IncDecodeErrors() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.decodeErrors++
+}
+
+func (m *recordingMetrics) SetQueueDepth(n int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.depths = append(m.depths, n)
+}
+
+func TestSetMetrics(t *testing.T) {
+	m := &recordingMetrics{}
+	e := Exiftool{}
+	assert.Nil(t, SetMetrics(m)(&e))
+
+	var fm FileMetadata
+	e.decode(&fm, []byte("[]"), nil)
+	assert.NotNil(t, fm.Err)
+	e.observeExtraction(fm, time.Second)
+	e.observeExtraction(FileMetadata{}, time.Second)
+	assert.Equal(t, 1, m.decodeErrors)
+	assert.Equal(t, 2, m.extractions)
+	assert.Equal(t, 1, m.failures)
+}
+
+func TestNewExifTool_WithMetrics(t *testing.T) {
+	m := &recordingMetrics{}
+	e, err := NewExiftool(SetMetrics(m))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	fms := e.ExtractMetadata("./testdata/20190404_131804.jpg", "./testdata/nonExisting")
+	assert.Equal(t, 2, len(fms))
+	assert.True(t, errors.Is(fms[1].Err, ErrNotExist))
+	assert.Equal(t, 2, m.extractions)
+	assert.Equal(t, 1, m.failures)
+	assert.Equal(t, 0, m.decodeErrors)
+}
+
+func TestNewPool_WithMetrics(t *testing.T) {
+	m := &recordingMetrics{}
+	p, err := NewPool(2, SetMetrics(m))
+	assert.Nil(t, err)
+	defer p.Close()
+
+	fms := p.ExtractMetadata("./testdata/20190404_131804.jpg", "./testdata/20190404_131804.jpg")
+	assert.Equal(t, 2, len(fms))
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	assert.Equal(t, 2, m.extractions)
+	assert.Equal(t, 4, len(m.depths))
+	for _, d := range m.depths {
+		assert.True(t, d >= 0 && d <= 2)
+	}
+}
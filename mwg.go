@@ -0,0 +1,166 @@
+package exiftool
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrMWGDisabled is returned when writing MWG tags without UseMWG
+var ErrMWGDisabled = errors.New("MWG module is not enabled (see UseMWG)")
+
+// mwgDateLayout is the layout of the dates written to the MWG tags
+const mwgDateLayout = "2006:01:02 15:04:05-07:00"
+
+// MWGInfo are the values reconciled by the MWG module of exiftool according to the
+// Metadata Working Group guidelines (https://exiftool.org/TagNames/MWG.html), whatever
+// the EXIF, IPTC or XMP tags holding them. Fields are zero when the corresponding tag
+// can't be found.
+type MWGInfo struct {
+	Description      string
+	Keywords         []string
+	Creator          []string
+	Copyright        string
+	Rating           int
+	DateTimeOriginal time.Time
+	CreateDate       time.Time
+	ModifyDate       time.Time
+}
+
+// UseMWG makes exiftool load its MWG module (activates Exiftool's '-use MWG'
+// parameter): the MWG composite tags (ie. Composite:Description, Composite:Keywords)
+// are extracted, see MWGInfo, and can be written through their "MWG:" group, exiftool
+// reconciling the EXIF, IPTC and XMP tags according to the MWG guidelines.
+// Sample :
+//   e, err := NewExiftool(UseMWG())
+func UseMWG() Option {
+	return func(e *Exiftool) error {
+		e.extraInitArgs = append(e.extraInitArgs, "-use", "MWG")
+		e.mwg = true
+		return nil
+	}
+}
+
+// MWGInfo returns the values reconciled by the MWG module, which requires the metadata
+// to be extracted with UseMWG. ErrKeyNotFound will be returned if none of them can be
+// found.
+func (fm FileMetadata) MWGInfo() (MWGInfo, error) {
+	var mi MWGInfo
+	found := false
+
+	for _, i := range []struct {
+		key string
+		dst *string
+	}{
+		{"Composite:Description", &mi.Description},
+		{"Composite:Copyright", &mi.Copyright},
+	} {
+		if s, ok := fm.lookupString(i.key); ok {
+			*i.dst, found = strings.TrimSpace(s), true
+		}
+	}
+
+	for _, i := range []struct {
+		key string
+		dst *[]string
+	}{
+		{"Composite:Keywords", &mi.Keywords},
+		{"Composite:Creator", &mi.Creator},
+	} {
+		if ss, ok := fm.lookupStrings(i.key); ok {
+			*i.dst, found = ss, true
+		}
+	}
+
+	r, err := fm.lookupInt("Composite:Rating")
+	if err == nil {
+		mi.Rating, found = int(r), true
+	} else if err != ErrKeyNotFound {
+		return MWGInfo{}, fmt.Errorf("Rating parsing error: %w", err)
+	}
+
+	for _, i := range []struct {
+		key string
+		dst *time.Time
+	}{
+		{"Composite:DateTimeOriginal", &mi.DateTimeOriginal},
+		{"Composite:CreateDate", &mi.CreateDate},
+		{"Composite:ModifyDate", &mi.ModifyDate},
+	} {
+		s, ok := fm.lookupString(i.key)
+		if !ok {
+			continue
+		}
+		t, err := toDateLayout(s, fm.DateLayout)
+		if err != nil {
+			return MWGInfo{}, fmt.Errorf("%v parsing error: %w", strings.TrimPrefix(i.key, "Composite:"), err)
+		}
+		*i.dst, found = t, true
+	}
+
+	if !found {
+		return MWGInfo{}, ErrKeyNotFound
+	}
+
+	return mi, nil
+}
+
+// mwgValues returns the values writing the non zero fields of mi to the MWG tags
+func mwgValues(mi MWGInfo) (FileMetadataValues, error) {
+	var vals FileMetadataValues
+	if mi.Description != "" {
+		vals = append(vals, FileMetadataValue{"MWG:Description", mi.Description})
+	}
+	if len(mi.Keywords) > 0 {
+		vals = append(vals, FileMetadataValue{"MWG:Keywords", mi.Keywords})
+	}
+	if len(mi.Creator) > 0 {
+		vals = append(vals, FileMetadataValue{"MWG:Creator", mi.Creator})
+	}
+	if mi.Copyright != "" {
+		vals = append(vals, FileMetadataValue{"MWG:Copyright", mi.Copyright})
+	}
+	if mi.Rating != 0 {
+		if mi.Rating < -1 || mi.Rating > 5 {
+			return nil, fmt.Errorf("invalid rating (%v)", mi.Rating)
+		}
+		vals = append(vals, FileMetadataValue{"MWG:Rating", int64(mi.Rating)})
+	}
+	for _, d := range []struct {
+		label string
+		t     time.Time
+	}{
+		{"MWG:DateTimeOriginal", mi.DateTimeOriginal},
+		{"MWG:CreateDate", mi.CreateDate},
+		{"MWG:ModifyDate", mi.ModifyDate},
+	} {
+		if !d.t.IsZero() {
+			vals = append(vals, FileMetadataValue{d.label, d.t.Format(mwgDateLayout)})
+		}
+	}
+
+	if len(vals) == 0 {
+		return nil, errors.New("no MWG value provided")
+	}
+	return vals, nil
+}
+
+// SetMWG writes the non zero fields of mi to the MWG tags of file, exiftool writing
+// them to the EXIF, IPTC and XMP tags recommended by the MWG guidelines. The lists
+// (Keywords, Creator) replace the existing ones. ErrMWGDisabled will be returned if
+// the Exiftool wasn't instanciated with UseMWG. If anything went wrong, a non empty
+// error will be returned.
+// Sample :
+//   err := e.SetMWG("a.jpg", MWGInfo{Description: "A cat", Keywords: []string{"cat", "pet"}})
+func (e *Exiftool) SetMWG(file string, mi MWGInfo) error {
+	if !e.mwg {
+		return ErrMWGDisabled
+	}
+
+	vals, err := mwgValues(mi)
+	if err != nil {
+		return err
+	}
+	return e.Write(file, vals)
+}
@@ -0,0 +1,129 @@
+package exiftool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUseMWG(t *testing.T) {
+	e := Exiftool{}
+	assert.Nil(t, UseMWG()(&e))
+	assert.True(t, e.mwg)
+	assert.Equal(t, []string{"-use", "MWG"}, e.extraInitArgs)
+}
+
+func TestMWGInfo(t *testing.T) {
+	var tcs = []struct {
+		tcID  string
+		in    map[string]FileMetadataValues
+		expOk bool
+		expMi MWGInfo
+	}{
+		{"nominal", map[string]FileMetadataValues{
+			"Composite": {
+				{"Description", "A cat "},
+				{"Keywords", []interface{}{"cat", "pet"}},
+				{"Creator", "me"},
+				{"Copyright", "(c) me"},
+				{"Rating", float64(4)},
+				{"DateTimeOriginal", "2019:04:04 13:18:03+02:00"},
+				{"CreateDate", "2019:04:04 13:18:03"},
+			},
+			"EXIF": {{"ImageDescription", "A dog"}},
+		}, true, MWGInfo{
+			Description:      "A cat",
+			Keywords:         []string{"cat", "pet"},
+			Creator:          []string{"me"},
+			Copyright:        "(c) me",
+			Rating:           4,
+			DateTimeOriginal: time.Date(2019, 4, 4, 13, 18, 3, 0, time.FixedZone("", 2*3600)),
+			CreateDate:       time.Date(2019, 4, 4, 13, 18, 3, 0, time.UTC),
+		}},
+		{"notReconciled", map[string]FileMetadataValues{"EXIF": {{"ImageDescription", "A dog"}}}, false, MWGInfo{}},
+		{"invalidRating", map[string]FileMetadataValues{"Composite": {{"Rating", "many"}}}, false, MWGInfo{}},
+		{"invalidDate", map[string]FileMetadataValues{"Composite": {{"ModifyDate", "yesterday"}}}, false, MWGInfo{}},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			mi, err := FileMetadata{Groups: tc.in}.MWGInfo()
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.True(t, tc.expMi.DateTimeOriginal.Equal(mi.DateTimeOriginal))
+				mi.DateTimeOriginal, tc.expMi.DateTimeOriginal = time.Time{}, time.Time{}
+				assert.Equal(t, tc.expMi, mi)
+			}
+		})
+	}
+
+	_, err := FileMetadata{}.MWGInfo()
+	assert.Equal(t, ErrKeyNotFound, err)
+}
+
+func TestMWGValues(t *testing.T) {
+	var tcs = []struct {
+		tcID    string
+		in      MWGInfo
+		expOk   bool
+		expVals FileMetadataValues
+	}{
+		{"nominal", MWGInfo{
+			Description:      "A cat",
+			Keywords:         []string{"cat", "pet"},
+			Creator:          []string{"me"},
+			Copyright:        "(c) me",
+			Rating:           -1,
+			DateTimeOriginal: time.Date(2019, 4, 4, 13, 18, 3, 0, time.FixedZone("", 2*3600)),
+		}, true, FileMetadataValues{
+			{"MWG:Description", "A cat"},
+			{"MWG:Keywords", []string{"cat", "pet"}},
+			{"MWG:Creator", []string{"me"}},
+			{"MWG:Copyright", "(c) me"},
+			{"MWG:Rating", int64(-1)},
+			{"MWG:DateTimeOriginal", "2019:04:04 13:18:03+02:00"},
+		}},
+		{"empty", MWGInfo{}, false, nil},
+		{"invalidRating", MWGInfo{Rating: 6}, false, nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			vals, err := mwgValues(tc.in)
+			assert.Equal(t, tc.expOk, err == nil)
+			assert.Equal(t, tc.expVals, vals)
+		})
+	}
+}
+
+func TestSetMWG(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+	assert.Equal(t, ErrMWGDisabled, e.SetMWG(f, MWGInfo{Description: "A cat"}))
+
+	m, err := NewExiftool(UseMWG())
+	assert.Nil(t, err)
+	defer m.Close()
+
+	assert.Nil(t, m.SetMWG(f, MWGInfo{Description: "A cat", Keywords: []string{"cat", "pet"}}))
+	fms := m.ExtractMetadata(f)
+	assert.Nil(t, fms[0].Err)
+	mi, err := fms[0].MWGInfo()
+	assert.Nil(t, err)
+	assert.Equal(t, "A cat", mi.Description)
+	assert.Equal(t, []string{"cat", "pet"}, mi.Keywords)
+
+	// reconciled across EXIF, IPTC and XMP
+	for _, k := range []string{"EXIF:ImageDescription", "IPTC:Caption-Abstract", "XMP:Description"} {
+		s, found := fms[0].lookupString(k)
+		assert.True(t, found, k)
+		assert.Equal(t, "A cat", s, k)
+	}
+}
@@ -0,0 +1,265 @@
+package exiftool
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Orientation is the EXIF orientation of an image, telling how it must be transformed to
+// be displayed upright
+type Orientation int
+
+// Orientation values, as defined by the EXIF specification
+const (
+	OrientationNormal Orientation = iota + 1
+	OrientationMirrorHorizontal
+	OrientationRotate180
+	OrientationMirrorVertical
+	OrientationMirrorHorizontalRotate270
+	OrientationRotate90
+	OrientationMirrorHorizontalRotate90
+	OrientationRotate270
+)
+
+// orientationNames are the values printed by exiftool, indexed by Orientation - 1
+var orientationNames = []string{
+	"Horizontal (normal)",
+	"Mirror horizontal",
+	"Rotate 180",
+	"Mirror vertical",
+	"Mirror horizontal and rotate 270 CW",
+	"Rotate 90 CW",
+	"Mirror horizontal and rotate 90 CW",
+	"Rotate 270 CW",
+}
+
+func (o Orientation) String() string {
+	if o < OrientationNormal || o > OrientationRotate270 {
+		return fmt.Sprintf("Unknown (%d)", int(o))
+	}
+	return orientationNames[o-1]
+}
+
+// SwapsDimensions returns true if the image is rotated by 90 or 270 degrees, its width
+// and height being swapped when displayed upright
+func (o Orientation) SwapsDimensions() bool {
+	return o >= OrientationMirrorHorizontalRotate270 && o <= OrientationRotate270
+}
+
+// parseOrientation parses an orientation printed by exiftool, either numeric (-n) or not
+func parseOrientation(s string) (Orientation, error) {
+	s = strings.TrimSpace(s)
+	if i, err := strconv.Atoi(s); err == nil {
+		if o := Orientation(i); o >= OrientationNormal && o <= OrientationRotate270 {
+			return o, nil
+		}
+		return 0, fmt.Errorf("invalid orientation (%v)", s)
+	}
+
+	for i, n := range orientationNames {
+		if strings.EqualFold(n, s) {
+			return Orientation(i + 1), nil
+		}
+	}
+
+	return 0, fmt.Errorf("invalid orientation (%v)", s)
+}
+
+// Orientation returns the EXIF orientation of the image. ErrKeyNotFound will be returned
+// if the image has no orientation.
+func (fm FileMetadata) Orientation() (Orientation, error) {
+	s, found := fm.lookupString("EXIF:Orientation", "Orientation")
+	if !found {
+		return 0, ErrKeyNotFound
+	}
+
+	return parseOrientation(s)
+}
+
+// Dimensions returns the width and height of the image once displayed upright, ie. once
+// its orientation is applied (rotations by 90 or 270 degrees swapping them). Images
+// without orientation are considered upright. ErrKeyNotFound will be returned if the
+// width or the height can't be found.
+func (fm FileMetadata) Dimensions() (int64, int64, error) {
+	w, err := fm.lookupInt("File:ImageWidth", "ImageWidth", "ExifImageWidth")
+	if err != nil {
+		return 0, 0, err
+	}
+	h, err := fm.lookupInt("File:ImageHeight", "ImageHeight", "ExifImageHeight")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	o, err := fm.Orientation()
+	switch {
+	case err == ErrKeyNotFound:
+		return w, h, nil
+	case err != nil:
+		return 0, 0, err
+	case o.SwapsDimensions():
+		return h, w, nil
+	default:
+		return w, h, nil
+	}
+}
+
+// ErrNotJPEG is returned when a lossless transformation is requested on a non JPEG image
+var ErrNotJPEG = errors.New("lossless transformation is only supported for JPEG images")
+
+// NormalizeOption is a configuration function of NormalizeOrientation
+type NormalizeOption func(*normalizeConfig) error
+
+type normalizeConfig struct {
+	transform ImageTransformer
+}
+
+// ImageTransformer losslessly transforms the pixels of the JPEG image src into dst, a
+// non existing file, so that the image of orientation o is upright once dst is
+// displayed without orientation
+type ImageTransformer func(src, dst string, o Orientation) error
+
+// NormalizeTransformer makes NormalizeOrientation transform the pixels of the image
+// with t before resetting its orientation, so that it is still displayed upright
+// Sample :
+//   o, err := e.NormalizeOrientation("a.jpg", NormalizeTransformer(Jpegtran("jpegtran")))
+func NormalizeTransformer(t ImageTransformer) NormalizeOption {
+	return func(c *normalizeConfig) error {
+		if t == nil {
+			return errors.New("nil image transformer")
+		}
+		c.transform = t
+		return nil
+	}
+}
+
+// Jpegtran returns the ImageTransformer running the jpegtran binary p (ie. "jpegtran" to
+// look for it in the PATH), every metadata being kept and the partial edge blocks that
+// can't be transformed losslessly being trimmed
+// Sample :
+//   t := Jpegtran("/usr/bin/jpegtran")
+func Jpegtran(p string) ImageTransformer {
+	return func(src, dst string, o Orientation) error {
+		args := append([]string{"-copy", "all", "-trim"}, jpegtranArgs(o)...)
+		args = append(args, "-outfile", dst, src)
+		if out, err := exec.Command(p, args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("error while running jpegtran (%v): %w", strings.TrimSpace(string(out)), err)
+		}
+		return nil
+	}
+}
+
+// jpegtranArgs returns the jpegtran transformation making an image of orientation o
+// upright
+func jpegtranArgs(o Orientation) []string {
+	switch o {
+	case OrientationMirrorHorizontal:
+		return []string{"-flip", "horizontal"}
+	case OrientationRotate180:
+		return []string{"-rotate", "180"}
+	case OrientationMirrorVertical:
+		return []string{"-flip", "vertical"}
+	case OrientationMirrorHorizontalRotate270:
+		return []string{"-transpose"}
+	case OrientationRotate90:
+		return []string{"-rotate", "90"}
+	case OrientationMirrorHorizontalRotate90:
+		return []string{"-transverse"}
+	case OrientationRotate270:
+		return []string{"-rotate", "270"}
+	default:
+		return nil
+	}
+}
+
+// isJPEG returns true if fm describes a JPEG image
+func (fm FileMetadata) isJPEG() bool {
+	if t, found := fm.lookupString("File:FileType", "FileType"); found {
+		return strings.EqualFold(t, "JPEG")
+	}
+	if t, found := fm.lookupString("File:MIMEType", "MIMEType"); found {
+		return strings.EqualFold(t, "image/jpeg")
+	}
+	return false
+}
+
+// NormalizeOrientation resets the EXIF orientation of file to OrientationNormal and
+// returns its previous orientation. By default only the tag is written, which changes
+// how the image is displayed: use NormalizeTransformer to losslessly transform its
+// pixels beforehand (JPEG images only, ErrNotJPEG being returned otherwise). Nothing is
+// written if file is already upright or has no orientation. If anything went wrong, a
+// non empty error will be returned.
+// Sample :
+//   o, err := e.NormalizeOrientation("a.jpg", NormalizeTransformer(Jpegtran("jpegtran")))
+func (e *Exiftool) NormalizeOrientation(file string, opts ...NormalizeOption) (Orientation, error) {
+	var c normalizeConfig
+	for _, opt := range opts {
+		if err := opt(&c); err != nil {
+			return 0, fmt.Errorf("error when configuring normalization: %w", err)
+		}
+	}
+
+	fm := e.ExtractMetadata(file)[0]
+	if fm.Err != nil {
+		return 0, fm.Err
+	}
+	o, err := fm.Orientation()
+	switch {
+	case err == ErrKeyNotFound:
+		return OrientationNormal, nil
+	case err != nil:
+		return 0, err
+	case o == OrientationNormal:
+		return o, nil
+	}
+
+	if c.transform != nil {
+		if !fm.isJPEG() {
+			return o, ErrNotJPEG
+		}
+		if err := e.transform(file, o, c.transform); err != nil {
+			return o, err
+		}
+	}
+
+	if err := e.Write(file, FileMetadataValues{{"EXIF:Orientation#", int(OrientationNormal)}}); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// transform transforms the pixels of file of orientation o with t, file being backed up
+// according to the write policy and replaced once the transformation succeeded. Nothing
+// is done in dry-run mode.
+func (e *Exiftool) transform(file string, o Orientation, t ImageTransformer) error {
+	if e.dryRun != nil {
+		return nil
+	}
+
+	fsys := e.fileSystem()
+	fi, err := fsys.Stat(file)
+	if err != nil {
+		return fmt.Errorf("error while opening image: %w", err)
+	}
+	tmp, err := tempPath(fsys, file)
+	if err != nil {
+		return err
+	}
+
+	if err := t(file, tmp, o); err != nil {
+		fsys.Remove(tmp)
+		return fmt.Errorf("error while transforming image: %w", err)
+	}
+	if err := e.backupOriginal(file); err != nil {
+		fsys.Remove(tmp)
+		return err
+	}
+	if err := replaceFile(fsys, tmp, file, fi.Mode()); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,229 @@
+package exiftool
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOrientation(t *testing.T) {
+	var tcs = []struct {
+		tcID   string
+		in     string
+		expOk  bool
+		expOri Orientation
+	}{
+		{"numeric", "6", true, OrientationRotate90},
+		{"printed", "Rotate 270 CW", true, OrientationRotate270},
+		{"normal", "Horizontal (normal)", true, OrientationNormal},
+		{"case", "mirror vertical", true, OrientationMirrorVertical},
+		{"outOfRange", "9", false, 0},
+		{"unknown", "Sideways", false, 0},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			o, err := parseOrientation(tc.in)
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.expOri, o)
+			}
+		})
+	}
+}
+
+func TestOrientationString(t *testing.T) {
+	for o := OrientationNormal; o <= OrientationRotate270; o++ {
+		p, err := parseOrientation(o.String())
+		assert.Nil(t, err)
+		assert.Equal(t, o, p)
+		assert.Equal(t, o >= 5, o.SwapsDimensions())
+	}
+	assert.Equal(t, "Unknown (0)", Orientation(0).String())
+}
+
+func TestDimensions(t *testing.T) {
+	var tcs = []struct {
+		tcID  string
+		in    map[string]FileMetadataValues
+		expOk bool
+		expW  int64
+		expH  int64
+	}{
+		{"noOrientation", map[string]FileMetadataValues{"File": {{"ImageWidth", float64(4032)}, {"ImageHeight", float64(3024)}}}, true, 4032, 3024},
+		{"normal", map[string]FileMetadataValues{
+			"File": {{"ImageWidth", float64(4032)}, {"ImageHeight", float64(3024)}},
+			"EXIF": {{"Orientation", "Horizontal (normal)"}},
+		}, true, 4032, 3024},
+		{"rotated", map[string]FileMetadataValues{
+			"File": {{"ImageWidth", float64(4032)}, {"ImageHeight", float64(3024)}},
+			"EXIF": {{"Orientation", float64(6)}},
+		}, true, 3024, 4032},
+		{"exifDimensions", map[string]FileMetadataValues{
+			"EXIF": {{"ExifImageWidth", float64(4032)}, {"ExifImageHeight", float64(3024)}, {"Orientation", "Rotate 270 CW"}},
+		}, true, 3024, 4032},
+		{"invalidOrientation", map[string]FileMetadataValues{
+			"File": {{"ImageWidth", float64(4032)}, {"ImageHeight", float64(3024)}},
+			"EXIF": {{"Orientation", "Sideways"}},
+		}, false, 0, 0},
+		{"noHeight", map[string]FileMetadataValues{"File": {{"ImageWidth", float64(4032)}}}, false, 0, 0},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			w, h, err := FileMetadata{Groups: tc.in}.Dimensions()
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.expW, w)
+				assert.Equal(t, tc.expH, h)
+			}
+		})
+	}
+
+	_, err := FileMetadata{}.Orientation()
+	assert.Equal(t, ErrKeyNotFound, err)
+}
+
+func TestJpegtranArgs(t *testing.T) {
+	var tcs = []struct {
+		in      Orientation
+		expArgs []string
+	}{
+		{OrientationNormal, nil},
+		{OrientationMirrorHorizontal, []string{"-flip", "horizontal"}},
+		{OrientationRotate180, []string{"-rotate", "180"}},
+		{OrientationMirrorVertical, []string{"-flip", "vertical"}},
+		{OrientationMirrorHorizontalRotate270, []string{"-transpose"}},
+		{OrientationRotate90, []string{"-rotate", "90"}},
+		{OrientationMirrorHorizontalRotate90, []string{"-transverse"}},
+		{OrientationRotate270, []string{"-rotate", "270"}},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.in.String(), func(t *testing.T) {
+			assert.Equal(t, tc.expArgs, jpegtranArgs(tc.in))
+		})
+	}
+}
+
+func TestNormalizeTransformer(t *testing.T) {
+	var c normalizeConfig
+	assert.NotNil(t, NormalizeTransformer(nil)(&c))
+	assert.Nil(t, NormalizeTransformer(func(src, dst string, o Orientation) error { return nil })(&c))
+	assert.NotNil(t, c.transform)
+}
+
+func TestNormalizeOrientation(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	// tag only
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+	o, err := e.NormalizeOrientation(f)
+	assert.Nil(t, err)
+	assert.Equal(t, OrientationRotate90, o)
+	o, err = e.ExtractMetadata(f)[0].Orientation()
+	assert.Nil(t, err)
+	assert.Equal(t, OrientationNormal, o)
+
+	// already upright: nothing written
+	o, err = e.NormalizeOrientation(f, NormalizeTransformer(func(src, dst string, o Orientation) error {
+		t.Error("unexpected transformation")
+		return nil
+	}))
+	assert.Nil(t, err)
+	assert.Equal(t, OrientationNormal, o)
+
+	// transformed
+	f2, clean2 := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean2()
+	var transformed Orientation
+	o, err = e.NormalizeOrientation(f2, NormalizeTransformer(func(src, dst string, o Orientation) error {
+		transformed = o
+		b, err := ioutil.ReadFile(src)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(dst, b, 0644)
+	}))
+	assert.Nil(t, err)
+	assert.Equal(t, OrientationRotate90, o)
+	assert.Equal(t, OrientationRotate90, transformed)
+	o, err = e.ExtractMetadata(f2)[0].Orientation()
+	assert.Nil(t, err)
+	assert.Equal(t, OrientationNormal, o)
+	_, err = os.Stat(f2 + "_original")
+	assert.Nil(t, err)
+
+	// failed transformation: nothing written
+	f3, clean3 := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean3()
+	_, err = e.NormalizeOrientation(f3, NormalizeTransformer(func(src, dst string, o Orientation) error {
+		return errors.New("ko")
+	}))
+	assert.NotNil(t, err)
+	o, err = e.ExtractMetadata(f3)[0].Orientation()
+	assert.Nil(t, err)
+	assert.Equal(t, OrientationRotate90, o)
+	files, err := ioutil.ReadDir(filepath.Dir(f3))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(files))
+
+	_, err = e.NormalizeOrientation("./testdata/nonExisting.jpg")
+	assert.NotNil(t, err)
+}
+
+func TestNormalizeOrientationDryRun(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+	before, err := ioutil.ReadFile(f)
+	assert.Nil(t, err)
+
+	var r CommandRecorder
+	e, err := NewExiftool(DryRun(&r))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	o, err := e.NormalizeOrientation(f, NormalizeTransformer(func(src, dst string, o Orientation) error {
+		t.Error("unexpected transformation")
+		return nil
+	}))
+	assert.Nil(t, err)
+	assert.Equal(t, OrientationRotate90, o)
+	assert.Equal(t, 1, len(r.Commands()))
+
+	after, err := ioutil.ReadFile(f)
+	assert.Nil(t, err)
+	assert.Equal(t, before, after)
+	files, err := ioutil.ReadDir(filepath.Dir(f))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(files))
+}
+
+func TestJpegtran(t *testing.T) {
+	p, err := exec.LookPath("jpegtran")
+	if err != nil {
+		t.Skip("jpegtran not found")
+	}
+
+	dir, err := ioutil.TempDir("", "go-exiftool")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	dst := filepath.Join(dir, "a.jpg")
+	assert.Nil(t, Jpegtran(p)("./testdata/20190404_131804.jpg", dst, OrientationRotate90))
+	_, err = os.Stat(dst)
+	assert.Nil(t, err)
+
+	assert.NotNil(t, Jpegtran(p)("./testdata/nonExisting.jpg", dst, OrientationRotate90))
+}
@@ -0,0 +1,211 @@
+package exiftool
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// ErrOutputTooLarge is a sentinel error used when exiftool printed more than the size
+// set with MaxOutputSize in answer to a command
+var ErrOutputTooLarge = errors.New("exiftool output too large")
+
+// outputChunkSize is the size of the chunks the output is read by when MaxOutputSize or
+// SpillOutput is used
+const outputChunkSize = 64 * 1024
+
+// BufferSize defines the initial and maximum sizes of the buffer used to read from
+// stdout and stderr, see Buffer. The output of a command can't be larger than max,
+// unless MaxOutputSize or SpillOutput is used.
+// Sample :
+//   e, err := NewExiftool(BufferSize(64*1024, 16*1024*1024))
+func BufferSize(initial, max int) Option {
+	return func(e *Exiftool) error {
+		if initial < 1 || max < initial {
+			return fmt.Errorf("invalid buffer sizes (%v, %v)", initial, max)
+		}
+		return Buffer(make([]byte, initial), max)(e)
+	}
+}
+
+// MaxOutputSize limits the output of a command to size bytes, ErrOutputTooLarge being
+// returned beyond (ie. as FileMetadata.Err). The output is read by chunks, hence its size
+// isn't limited by the buffer anymore, and the remaining output is drained so that the
+// exiftool process can run the next commands.
+// Sample :
+//   e, err := NewExiftool(MaxOutputSize(256 * 1024 * 1024))
+func MaxOutputSize(size int64) Option {
+	return func(e *Exiftool) error {
+		if size < 1 {
+			return fmt.Errorf("invalid max output size (%v)", size)
+		}
+		e.maxOutput = size
+		return nil
+	}
+}
+
+// SpillOutput stores the output of the commands larger than threshold bytes into a
+// temporary file of dir (the default directory for temporary files if empty) while it is
+// read, instead of growing an in-memory buffer that would be kept by the Exiftool. The
+// output is read by chunks, hence its size isn't limited by the buffer anymore.
+// Sample :
+//   e, err := NewExiftool(SpillOutput(32*1024*1024, ""))
+func SpillOutput(threshold int64, dir string) Option {
+	return func(e *Exiftool) error {
+		if threshold < 1 {
+			return fmt.Errorf("invalid spill threshold (%v)", threshold)
+		}
+		if dir != "" {
+			if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+				return fmt.Errorf("invalid spill directory (%v)", dir)
+			}
+		}
+		e.spillAt = threshold
+		e.spillDir = dir
+		return nil
+	}
+}
+
+// chunked returns true if the output is read by chunks
+func (e *Exiftool) chunked() bool {
+	return e.maxOutput > 0 || e.spillAt > 0
+}
+
+// chunkedScanner configures sc to read the output by chunks
+func (e *Exiftool) chunkedScanner(sc *bufio.Scanner) {
+	size := outputChunkSize
+	if e.bufferSet {
+		sc.Buffer(e.buffer, e.bufferMaxSize)
+		size = e.bufferMaxSize / 2
+	} else {
+		sc.Buffer(make([]byte, 4096), 2*outputChunkSize+readyTokenLen)
+	}
+	if size <= readyTokenLen {
+		size = readyTokenLen + 1
+	}
+	sc.Split(splitChunks(size, &e.lastChunk))
+}
+
+// splitChunks returns a split function behaving like splitReadyToken, except that the
+// outputs larger than size are split into several tokens, final being false for every
+// token except the last one of an output
+func splitChunks(size int, final *bool) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := splitReadyToken(data, atEOF)
+		if advance > 0 || err != nil {
+			*final = true
+			return advance, token, err
+		}
+
+		// the last bytes may be the beginning of the ready token
+		if n := len(data) - (readyTokenLen - 1); n >= size {
+			*final = false
+			return n, data[:n], nil
+		}
+
+		return 0, nil, nil
+	}
+}
+
+// readChunks reads the output of a command by chunks. The caller must hold e.lock.
+func (e *Exiftool) readChunks() ([]byte, error) {
+	out := outputBuffer{fsys: e.fileSystem(), threshold: e.spillAt, dir: e.spillDir}
+	defer out.close()
+
+	var total int64
+	for {
+		if !e.scanMergedOut.Scan() {
+			if err := e.scanMergedOut.Err(); err != nil {
+				return nil, e.crashed(fmt.Errorf("error while reading stdMergedOut: %w", err))
+			}
+			return nil, e.crashed(fmt.Errorf("nothing on stdMergedOut"))
+		}
+
+		// the whole output is read, even too large, to be ready for the next command
+		chunk := e.scanMergedOut.Bytes()
+		total += int64(len(chunk))
+		if e.maxOutput <= 0 || total <= e.maxOutput {
+			out.write(chunk)
+		}
+		if e.lastChunk {
+			break
+		}
+	}
+
+	if e.maxOutput > 0 && total > e.maxOutput {
+		return nil, ErrOutputTooLarge
+	}
+
+	return out.bytes()
+}
+
+// outputBuffer accumulates an output in memory, then in a temporary file of fsys (the
+// OSFileSystem if nil) once it is larger than threshold (if > 0)
+type outputBuffer struct {
+	fsys      FileSystem
+	threshold int64
+	dir       string
+	mem       bytes.Buffer
+	f         File
+	err       error
+}
+
+// write appends p to the output, the first error being kept for bytes
+func (o *outputBuffer) write(p []byte) {
+	if o.err != nil {
+		return
+	}
+
+	if o.f == nil && o.threshold > 0 && int64(o.mem.Len()+len(p)) > o.threshold {
+		f, err := orOS(o.fsys).TempFile(o.dir, "go-exiftool-output")
+		if err != nil {
+			o.err = fmt.Errorf("error while creating spill file: %w", err)
+			return
+		}
+		o.f = f
+		if _, err := o.f.Write(o.mem.Bytes()); err != nil {
+			o.err = fmt.Errorf("error while writing spill file: %w", err)
+			return
+		}
+		o.mem = bytes.Buffer{}
+	}
+
+	if o.f == nil {
+		o.mem.Write(p)
+		return
+	}
+	if _, err := o.f.Write(p); err != nil {
+		o.err = fmt.Errorf("error while writing spill file: %w", err)
+	}
+}
+
+// bytes returns the accumulated output
+func (o *outputBuffer) bytes() ([]byte, error) {
+	if o.err != nil {
+		return nil, o.err
+	}
+	if o.f == nil {
+		return o.mem.Bytes(), nil
+	}
+
+	if _, err := o.f.Seek(0, 0); err != nil {
+		return nil, fmt.Errorf("error while reading spill file: %w", err)
+	}
+	b, err := ioutil.ReadAll(o.f)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading spill file: %w", err)
+	}
+
+	return b, nil
+}
+
+// close removes the temporary file, if any
+func (o *outputBuffer) close() {
+	if o.f != nil {
+		o.f.Close()
+		orOS(o.fsys).Remove(o.f.Name())
+	}
+}
@@ -0,0 +1,133 @@
+package exiftool
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputOptions(t *testing.T) {
+	var tcs = []struct {
+		tcID  string
+		in    Option
+		expOk bool
+	}{
+		{"bufferSize", BufferSize(1024, 4096), true},
+		{"bufferSizeMaxTooLow", BufferSize(1024, 512), false},
+		{"bufferSizeZero", BufferSize(0, 512), false},
+		{"maxOutput", MaxOutputSize(1024), true},
+		{"maxOutputZero", MaxOutputSize(0), false},
+		{"spill", SpillOutput(1024, ""), true},
+		{"spillDir", SpillOutput(1024, os.TempDir()), true},
+		{"spillZero", SpillOutput(0, ""), false},
+		{"spillNonExistingDir", SpillOutput(1024, "./testdata/nonExisting"), false},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			e := Exiftool{}
+			assert.Equal(t, tc.expOk, tc.in(&e) == nil)
+		})
+	}
+
+	e := Exiftool{}
+	assert.False(t, e.chunked())
+	assert.Nil(t, BufferSize(1024, 4096)(&e))
+	assert.Equal(t, 1024, len(e.buffer))
+	assert.Equal(t, 4096, e.bufferMaxSize)
+	assert.False(t, e.chunked())
+	assert.Nil(t, MaxOutputSize(1024)(&e))
+	assert.True(t, e.chunked())
+}
+
+func TestSplitChunks(t *testing.T) {
+	big := strings.Repeat("a", 100)
+	in := big + string(readyToken) + "small" + string(readyToken) + string(readyToken)
+
+	var final bool
+	sc := bufio.NewScanner(iotest.OneByteReader(strings.NewReader(in)))
+	sc.Buffer(make([]byte, 16), 64)
+	sc.Split(splitChunks(20, &final))
+
+	var outputs []string
+	var cur bytes.Buffer
+	chunks := 0
+	for sc.Scan() {
+		chunks++
+		cur.Write(sc.Bytes())
+		if final {
+			outputs = append(outputs, cur.String())
+			cur.Reset()
+		}
+	}
+	assert.Nil(t, sc.Err())
+	assert.Equal(t, []string{big, "small", ""}, outputs)
+	assert.True(t, chunks > 3)
+}
+
+func TestOutputBuffer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-exiftool")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	o := outputBuffer{}
+	o.write([]byte("ab"))
+	o.write([]byte("cd"))
+	b, err := o.bytes()
+	assert.Nil(t, err)
+	assert.Equal(t, "abcd", string(b))
+	o.close()
+
+	o = outputBuffer{threshold: 3, dir: dir}
+	o.write([]byte("ab"))
+	assert.Nil(t, o.f)
+	o.write([]byte("cd"))
+	assert.NotNil(t, o.f)
+	o.write([]byte("ef"))
+	b, err = o.bytes()
+	assert.Nil(t, err)
+	assert.Equal(t, "abcdef", string(b))
+	o.close()
+	files, err := ioutil.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Empty(t, files)
+
+	o = outputBuffer{threshold: 1, dir: "./testdata/nonExisting"}
+	o.write([]byte("ab"))
+	_, err = o.bytes()
+	assert.NotNil(t, err)
+}
+
+func TestNewExifTool_WithMaxOutputSize(t *testing.T) {
+	e, err := NewExiftool(MaxOutputSize(10))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	fms := e.ExtractMetadata("./testdata/20190404_131804.jpg")
+	assert.Equal(t, 1, len(fms))
+	assert.Equal(t, ErrOutputTooLarge, fms[0].Err)
+
+	// the process is still usable
+	assert.Nil(t, e.Ping())
+}
+
+func TestNewExifTool_WithSpillOutput(t *testing.T) {
+	e, err := NewExiftool(SpillOutput(16, ""))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	fms := e.ExtractMetadata("./testdata/20190404_131804.jpg", "./testdata/20190404_131804.jpg")
+	assert.Equal(t, 2, len(fms))
+	for _, fm := range fms {
+		assert.Nil(t, fm.Err)
+		assert.Equal(t, "./testdata/20190404_131804.jpg", fm.File)
+	}
+	assert.Nil(t, e.Ping())
+}
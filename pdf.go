@@ -0,0 +1,199 @@
+package exiftool
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// pdfDateLayout is the layout of the dates written to the PDF Info dictionary
+const pdfDateLayout = "2006:01:02 15:04:05-07:00"
+
+// PDFInfo describes a PDF document: its structure (version, pages, encryption and
+// linearization) and its Info dictionary. Fields are zero when the corresponding tag
+// can't be found.
+type PDFInfo struct {
+	Version   string
+	PageCount int
+	// Encryption is the encryption method (ie. "Standard V2.3 (128-bit)"), empty if the
+	// document isn't encrypted
+	Encryption string
+	// Linearized is true if the document is optimized for fast web view
+	Linearized bool
+	Title      string
+	Author     string
+	Subject    string
+	Keywords   []string
+	Creator    string
+	Producer   string
+	CreateDate time.Time
+	ModifyDate time.Time
+	// XMP is the embedded XMP packet, only filled by ExtractPDFInfo
+	XMP []byte
+}
+
+// Encrypted returns true if the document is encrypted
+func (pi PDFInfo) Encrypted() bool {
+	return pi.Encryption != ""
+}
+
+// PDFInfo returns the structure and the Info dictionary of a PDF document.
+// ErrKeyNotFound will be returned if the file isn't a PDF document.
+func (fm FileMetadata) PDFInfo() (PDFInfo, error) {
+	var pi PDFInfo
+	found := false
+
+	for _, i := range []struct {
+		key string
+		dst *string
+	}{
+		{"PDF:PDFVersion", &pi.Version},
+		{"PDF:Encryption", &pi.Encryption},
+		{"PDF:Title", &pi.Title},
+		{"PDF:Author", &pi.Author},
+		{"PDF:Subject", &pi.Subject},
+		{"PDF:Creator", &pi.Creator},
+		{"PDF:Producer", &pi.Producer},
+	} {
+		if s, ok := fm.lookupString(i.key); ok {
+			*i.dst, found = strings.TrimSpace(s), true
+		}
+	}
+
+	pc, err := fm.lookupInt("PDF:PageCount")
+	if err == nil {
+		pi.PageCount, found = int(pc), true
+	} else if err != ErrKeyNotFound {
+		return PDFInfo{}, fmt.Errorf("PageCount parsing error: %w", err)
+	}
+
+	if g, label, ok := fm.lookup("PDF:Linearized"); ok {
+		l, err := g.GetBool(label)
+		if err != nil {
+			return PDFInfo{}, fmt.Errorf("Linearized parsing error: %w", err)
+		}
+		pi.Linearized, found = l, true
+	}
+
+	if kws, ok := fm.lookupStrings("PDF:Keywords"); ok {
+		pi.Keywords, found = splitPDFKeywords(kws), true
+	}
+
+	for _, i := range []struct {
+		key string
+		dst *time.Time
+	}{
+		{"PDF:CreateDate", &pi.CreateDate},
+		{"PDF:ModifyDate", &pi.ModifyDate},
+	} {
+		s, ok := fm.lookupString(i.key)
+		if !ok {
+			continue
+		}
+		t, err := toDateLayout(s, fm.DateLayout)
+		if err != nil {
+			return PDFInfo{}, fmt.Errorf("%v parsing error: %w", strings.TrimPrefix(i.key, "PDF:"), err)
+		}
+		*i.dst, found = t, true
+	}
+
+	if !found {
+		return PDFInfo{}, ErrKeyNotFound
+	}
+
+	return pi, nil
+}
+
+// splitPDFKeywords splits the keywords of the Info dictionary, usually stored as a single
+// comma separated string
+func splitPDFKeywords(kws []string) []string {
+	var res []string
+	for _, kw := range kws {
+		for _, k := range strings.Split(kw, ",") {
+			if k = strings.TrimSpace(k); k != "" {
+				res = append(res, k)
+			}
+		}
+	}
+	return res
+}
+
+// ExtractPDFInfo extracts the PDFInfo of file, along with its embedded XMP packet if any.
+// ErrKeyNotFound will be returned if file isn't a PDF document.
+// Sample :
+//   pi, err := e.ExtractPDFInfo("report.pdf")
+func (e *Exiftool) ExtractPDFInfo(file string) (PDFInfo, error) {
+	fm := e.ExtractMetadata(file)[0]
+	if fm.Err != nil {
+		return PDFInfo{}, fm.Err
+	}
+
+	pi, err := fm.PDFInfo()
+	if err != nil {
+		return PDFInfo{}, err
+	}
+
+	xmp, err := e.ExtractBinary(file, "XMP")
+	switch {
+	case err == nil:
+		pi.XMP = xmp
+	case err != ErrKeyNotFound:
+		return PDFInfo{}, fmt.Errorf("error while extracting XMP packet: %w", err)
+	}
+
+	return pi, nil
+}
+
+// pdfInfoValues returns the values writing the non zero Info dictionary fields of pi
+func pdfInfoValues(pi PDFInfo) (FileMetadataValues, error) {
+	var vals FileMetadataValues
+	for _, f := range []struct {
+		label string
+		v     string
+	}{
+		{"PDF:Title", pi.Title},
+		{"PDF:Author", pi.Author},
+		{"PDF:Subject", pi.Subject},
+		{"PDF:Creator", pi.Creator},
+		{"PDF:Producer", pi.Producer},
+	} {
+		if f.v != "" {
+			vals = append(vals, FileMetadataValue{f.label, f.v})
+		}
+	}
+	if len(pi.Keywords) > 0 {
+		vals = append(vals, FileMetadataValue{"PDF:Keywords", pi.Keywords})
+	}
+	for _, d := range []struct {
+		label string
+		t     time.Time
+	}{
+		{"PDF:CreateDate", pi.CreateDate},
+		{"PDF:ModifyDate", pi.ModifyDate},
+	} {
+		if !d.t.IsZero() {
+			vals = append(vals, FileMetadataValue{d.label, d.t.Format(pdfDateLayout)})
+		}
+	}
+
+	if len(vals) == 0 {
+		return nil, errors.New("no Info dictionary field provided")
+	}
+	return vals, nil
+}
+
+// SetPDFInfo writes the non zero Info dictionary fields of pi (Title, Author, Subject,
+// Keywords, Creator, Producer, CreateDate and ModifyDate) to the PDF document file, the
+// other fields being ignored. As exiftool updates PDF documents incrementally, the
+// previous values can be recovered (exiftool -PDF-update:all= file). If anything went
+// wrong, a non empty error will be returned.
+// Sample :
+//   err := e.SetPDFInfo("report.pdf", PDFInfo{Title: "Report", Keywords: []string{"q1", "sales"}})
+func (e *Exiftool) SetPDFInfo(file string, pi PDFInfo) error {
+	vals, err := pdfInfoValues(pi)
+	if err != nil {
+		return err
+	}
+	return e.Write(file, vals)
+}
@@ -0,0 +1,163 @@
+package exiftool
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeTestPDF writes a single page PDF document into a temporary directory
+func writeTestPDF(t *testing.T) (string, func()) {
+	objs := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] >>",
+		"<< /Title (Draft) /Producer (go-exiftool) >>",
+	}
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objs))
+	for i, o := range objs {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%v\nendobj\n", i+1, o)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, o := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", o)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R /Info 4 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, xref)
+
+	dir, err := ioutil.TempDir("", "go-exiftool")
+	assert.Nil(t, err)
+	f := filepath.Join(dir, "a.pdf")
+	assert.Nil(t, ioutil.WriteFile(f, b.Bytes(), 0644))
+	return f, func() { os.RemoveAll(dir) }
+}
+
+func TestPDFInfo(t *testing.T) {
+	var tcs = []struct {
+		tcID  string
+		in    map[string]FileMetadataValues
+		expOk bool
+		expPi PDFInfo
+	}{
+		{"nominal", map[string]FileMetadataValues{"PDF": {
+			{"PDFVersion", "1.7"},
+			{"Linearized", "Yes"},
+			{"Encryption", "Standard V2.3 (128-bit)"},
+			{"PageCount", float64(12)},
+			{"Title", "Report"},
+			{"Author", "me"},
+			{"Subject", "Sales"},
+			{"Keywords", "q1, sales,"},
+			{"Creator", "Writer"},
+			{"Producer", "LibreOffice 7.0"},
+			{"CreateDate", "2021:03:01 10:00:00Z"},
+			{"ModifyDate", "2021:03:02 10:00:00Z"},
+		}}, true, PDFInfo{
+			Version:    "1.7",
+			PageCount:  12,
+			Encryption: "Standard V2.3 (128-bit)",
+			Linearized: true,
+			Title:      "Report",
+			Author:     "me",
+			Subject:    "Sales",
+			Keywords:   []string{"q1", "sales"},
+			Creator:    "Writer",
+			Producer:   "LibreOffice 7.0",
+			CreateDate: time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC),
+			ModifyDate: time.Date(2021, 3, 2, 10, 0, 0, 0, time.UTC),
+		}},
+		{"keywordsList", map[string]FileMetadataValues{"PDF": {{"Keywords", []interface{}{"q1", "sales"}}}}, true, PDFInfo{Keywords: []string{"q1", "sales"}}},
+		{"invalidPageCount", map[string]FileMetadataValues{"PDF": {{"PageCount", "many"}}}, false, PDFInfo{}},
+		{"invalidLinearized", map[string]FileMetadataValues{"PDF": {{"Linearized", "maybe"}}}, false, PDFInfo{}},
+		{"invalidDate", map[string]FileMetadataValues{"PDF": {{"CreateDate", "yesterday"}}}, false, PDFInfo{}},
+		{"notPDF", map[string]FileMetadataValues{"EXIF": {{"Title", "Report"}}}, false, PDFInfo{}},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			pi, err := FileMetadata{Groups: tc.in}.PDFInfo()
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.expPi.CreateDate.Unix(), pi.CreateDate.Unix())
+				assert.Equal(t, tc.expPi.ModifyDate.Unix(), pi.ModifyDate.Unix())
+				pi.CreateDate, pi.ModifyDate = tc.expPi.CreateDate, tc.expPi.ModifyDate
+				assert.Equal(t, tc.expPi, pi)
+			}
+		})
+	}
+
+	_, err := FileMetadata{}.PDFInfo()
+	assert.Equal(t, ErrKeyNotFound, err)
+	assert.True(t, PDFInfo{Encryption: "AES"}.Encrypted())
+	assert.False(t, PDFInfo{}.Encrypted())
+}
+
+func TestPDFInfoValues(t *testing.T) {
+	var tcs = []struct {
+		tcID    string
+		in      PDFInfo
+		expOk   bool
+		expVals FileMetadataValues
+	}{
+		{"nominal", PDFInfo{
+			Title:      "Report",
+			Keywords:   []string{"q1", "sales"},
+			Producer:   "go-exiftool",
+			ModifyDate: time.Date(2021, 3, 2, 10, 0, 0, 0, time.UTC),
+			PageCount:  12,
+		}, true, FileMetadataValues{
+			{"PDF:Title", "Report"},
+			{"PDF:Producer", "go-exiftool"},
+			{"PDF:Keywords", []string{"q1", "sales"}},
+			{"PDF:ModifyDate", "2021:03:02 10:00:00+00:00"},
+		}},
+		{"structureOnly", PDFInfo{PageCount: 12, Linearized: true}, false, nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			vals, err := pdfInfoValues(tc.in)
+			assert.Equal(t, tc.expOk, err == nil)
+			assert.Equal(t, tc.expVals, vals)
+		})
+	}
+}
+
+func TestExtractAndSetPDFInfo(t *testing.T) {
+	f, clean := writeTestPDF(t)
+	defer clean()
+
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	pi, err := e.ExtractPDFInfo(f)
+	assert.Nil(t, err)
+	assert.Equal(t, "1.4", pi.Version)
+	assert.Equal(t, 1, pi.PageCount)
+	assert.Equal(t, "Draft", pi.Title)
+	assert.False(t, pi.Encrypted())
+	assert.Nil(t, pi.XMP)
+
+	assert.Nil(t, e.SetPDFInfo(f, PDFInfo{Title: "Report", Keywords: []string{"q1", "sales"}}))
+	pi, err = e.ExtractPDFInfo(f)
+	assert.Nil(t, err)
+	assert.Equal(t, "Report", pi.Title)
+	assert.Equal(t, "go-exiftool", pi.Producer)
+	assert.Equal(t, []string{"q1", "sales"}, pi.Keywords)
+
+	_, err = e.ExtractPDFInfo("./testdata/20190404_131804.jpg")
+	assert.Equal(t, ErrKeyNotFound, err)
+}
@@ -0,0 +1,125 @@
+package exiftool
+
+import (
+	"context"
+	"fmt"
+)
+
+// Pipeline chains the extraction and the edits of a file, see Exiftool.File. Its methods
+// return the Pipeline so that the calls can be chained, the first error being returned
+// by Execute.
+type Pipeline struct {
+	e      *Exiftool
+	file   string
+	tags   []string
+	values FileMetadataValues
+	err    error
+}
+
+// File starts a Pipeline on file: the tags to extract (Read) and the edits (Set, Delete,
+// DeleteGroup) are recorded, then sent by Execute, the edits being compiled into a
+// single writing command instead of one command per edit.
+// Sample :
+//   fm, err := e.File("a.jpg").Read("EXIF:all").Set("XMP:Rating", 5).DeleteGroup("IPTC").Execute()
+func (e *Exiftool) File(file string) *Pipeline {
+	return &Pipeline{e: e, file: file}
+}
+
+// Read adds tags to extract (-TAG), which can be prefixed by a group and support the
+// exiftool wildcards (ie. "EXIF:all", "*Date*"). The tags are extracted before the edits
+// are applied.
+func (p *Pipeline) Read(tags ...string) *Pipeline {
+	if p.err == nil && len(tags) == 0 {
+		p.err = fmt.Errorf("no tag to read")
+	}
+	p.tags = append(p.tags, tags...)
+	return p
+}
+
+// Set writes v into tag (-TAG=VALUE), like Exiftool.Write does
+func (p *Pipeline) Set(tag string, v interface{}) *Pipeline {
+	if p.err == nil && v == nil {
+		p.err = fmt.Errorf("nil value for %v (see Delete)", tag)
+	}
+	p.values = append(p.values, FileMetadataValue{Label: tag, Value: v})
+	return p
+}
+
+// Delete removes tags (-TAG=), like Exiftool.Delete does
+func (p *Pipeline) Delete(tags ...string) *Pipeline {
+	if p.err == nil && len(tags) == 0 {
+		p.err = fmt.Errorf("no tag to delete")
+	}
+	for _, t := range tags {
+		p.values = append(p.values, FileMetadataValue{Label: t})
+	}
+	return p
+}
+
+// DeleteGroup removes every tag of group (-GROUP:all=)
+func (p *Pipeline) DeleteGroup(group string) *Pipeline {
+	if p.err == nil && group == "" {
+		p.err = fmt.Errorf("empty group")
+	}
+	p.values = append(p.values, FileMetadataValue{Label: group + ":all"})
+	return p
+}
+
+// Execute runs the pipeline while holding the Exiftool: the tags to read are extracted
+// in a single command, then the edits are written in a single command according to the
+// write policy. It returns the extracted metadata, only File being set if nothing was
+// read. If anything went wrong, a non empty error will be returned.
+func (p *Pipeline) Execute() (FileMetadata, error) {
+	fm := FileMetadata{File: p.file}
+	if p.err != nil {
+		return fm, p.err
+	}
+	if len(p.tags) == 0 && len(p.values) == 0 {
+		return fm, fmt.Errorf("empty pipeline")
+	}
+
+	e := p.e
+	if err := e.checkFile(p.file); err != nil {
+		return fm, err
+	}
+
+	var cfg extractConfig
+	if len(p.tags) > 0 {
+		var err error
+		if cfg, err = e.newExtractConfig([]ExtractOption{WithTags(p.tags...)}); err != nil {
+			return fm, err
+		}
+	}
+	var args []string
+	if len(p.values) > 0 {
+		var err error
+		if args, err = writeArgs(p.values); err != nil {
+			return fm, err
+		}
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if len(p.tags) > 0 {
+		fm = e.extractFile(context.Background(), cfg, p.file)
+		if fm.Err != nil {
+			return fm, fm.Err
+		}
+	}
+
+	if len(p.values) > 0 {
+		if err := e.checkWritable(p.values); err != nil {
+			return fm, err
+		}
+		out, err := e.executeWriteFiles(args, p.file)
+		if err != nil {
+			return fm, err
+		}
+		if err := checkWriteOutput(p.file, out); err != nil {
+			return fm, err
+		}
+	}
+
+	return fm, nil
+}
@@ -0,0 +1,76 @@
+package exiftool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelineErrors(t *testing.T) {
+	e := &Exiftool{}
+
+	var tcs = []struct {
+		tcID string
+		in   *Pipeline
+	}{
+		{"empty", e.File("a.jpg")},
+		{"noTagToRead", e.File("a.jpg").Read().Set("Artist", "me")},
+		{"nilValue", e.File("a.jpg").Set("Artist", nil)},
+		{"noTagToDelete", e.File("a.jpg").Delete()},
+		{"emptyGroup", e.File("a.jpg").DeleteGroup("")},
+		{"emptyLabel", e.File("a.jpg").Set("", "me")},
+		{"lineBreak", e.File("./testdata/20190404_131804.jpg").Set("Artist", "a\nb")},
+		{"invalidTag", e.File("./testdata/20190404_131804.jpg").Read("")},
+		{"nonExisting", e.File("./testdata/nonExisting").Set("Artist", "me")},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			fm, err := tc.in.Execute()
+			assert.NotNil(t, err)
+			assert.Equal(t, tc.in.file, fm.File)
+		})
+	}
+}
+
+func TestPipelineDryRun(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+
+	var r CommandRecorder
+	e, err := NewExiftool(DryRun(&r))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	fm, err := e.File(f).Set("XMP:Rating", 5).DeleteGroup("IPTC").Delete("Artist", "Copyright").Execute()
+	assert.Nil(t, err)
+	assert.Equal(t, f, fm.File)
+	assert.Nil(t, fm.Groups)
+
+	common := append([]string{e.binaryPath}, e.extraInitArgs...)
+	assert.Equal(t, [][]string{
+		append(append([]string{}, common...), "-XMP:Rating=5", "-IPTC:all=", "-Artist=", "-Copyright=", f),
+	}, r.Commands())
+}
+
+func TestPipelineExecute(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	fm, err := e.File(f).Read("EXIF:Make").Set("XMP:Rating", 5).Execute()
+	assert.Nil(t, err)
+	make, err := fm.Groups["EXIF"].GetString("Make")
+	assert.Nil(t, err)
+	assert.Equal(t, "samsung", make)
+
+	fm, err = e.File(f).Read("XMP:Rating").Execute()
+	assert.Nil(t, err)
+	rating, err := fm.Groups["XMP"].GetInt("Rating")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), rating)
+}
@@ -1,3 +1,25 @@
 package exiftool
 
+import (
+	"os/exec"
+	"syscall"
+)
+
 var readyToken = []byte("{ready}\n")
+
+// binaries lists the names of the exiftool binary looked for in the PATH
+var binaries = []string{"exiftool"}
+
+// platformInitArgs are the arguments always passed to exiftool on this platform
+var platformInitArgs []string
+
+// configureCommand applies the platform specifics to cmd
+func configureCommand(cmd *exec.Cmd) {}
+
+// resourceLimitsSupported is true if Nice and the resource limits are available
+const resourceLimitsSupported = true
+
+// setPriority sets the niceness of the process pid
+func setPriority(pid, nice int) error {
+	return syscall.Setpriority(syscall.PRIO_PROCESS, pid, nice)
+}
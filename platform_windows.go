@@ -1,3 +1,36 @@
 package exiftool
 
+import (
+	"os/exec"
+	"syscall"
+)
+
+// createNoWindow is the CREATE_NO_WINDOW process creation flag, see
+// https://docs.microsoft.com/en-us/windows/win32/procthread/process-creation-flags
+const createNoWindow = 0x08000000
+
 var readyToken = []byte("{ready}\r\n")
+
+// binaries lists the names of the exiftool binary looked for in the PATH. The Windows
+// executable is distributed as "exiftool(-k).exe", which waits for a key press before
+// exiting: it must be renamed to "exiftool.exe", see https://exiftool.org/install.html#Windows
+var binaries = []string{"exiftool.exe", "exiftool"}
+
+// platformInitArgs are the arguments always passed to exiftool on this platform: file
+// names are passed as UTF-8 instead of the system code page, see
+// https://exiftool.org/faq.html#Q18
+var platformInitArgs = []string{"-charset", "filename=UTF8"}
+
+// configureCommand applies the platform specifics to cmd: no console window is opened
+// for the exiftool process
+func configureCommand(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true, CreationFlags: createNoWindow}
+}
+
+// resourceLimitsSupported is true if Nice and the resource limits are available
+const resourceLimitsSupported = false
+
+// setPriority sets the niceness of the process pid, which isn't supported
+func setPriority(pid, nice int) error {
+	return syscall.EWINDOWS
+}
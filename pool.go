@@ -0,0 +1,327 @@
+package exiftool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrPoolClosed is a sentinel error used when an extraction is requested on a closed pool
+var ErrPoolClosed = errors.New("pool is closed")
+
+// Pool manages several long-running exiftool processes (stay_open) and dispatches
+// extractions across them. Workers whose process died are transparently restarted.
+type Pool struct {
+	size     int
+	opts     []Option
+	workers  chan *Exiftool
+	lock     sync.Mutex
+	closed   bool
+	done     chan struct{}
+	metrics  Metrics
+	waiting  int64
+	dedup    bool
+	dedups   int64
+	fsys     FileSystem
+	members  map[*Exiftool]bool
+	replaced int64
+}
+
+// NewPool instanciates a new Pool of size exiftool processes, each one being configured
+// with the provided configuration functions. If anything went wrong, a non empty error
+// will be returned.
+// Sample :
+//   p, err := NewPool(4, NoPrintConversion())
+func NewPool(size int, opts ...Option) (*Pool, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("invalid pool size (%v)", size)
+	}
+
+	p := Pool{
+		size:    size,
+		opts:    opts,
+		workers: make(chan *Exiftool, size),
+		done:    make(chan struct{}),
+		members: map[*Exiftool]bool{},
+	}
+
+	for i := 0; i < size; i++ {
+		e, err := NewExiftool(opts...)
+		if err != nil {
+			p.size = i
+			p.Close()
+			return nil, fmt.Errorf("error when starting worker #%v: %w", i, err)
+		}
+		p.workers <- e
+		p.replace(nil, e)
+		p.metrics = e.metrics
+		p.dedup = e.dedup
+		p.fsys = e.fsys
+	}
+
+	return &p, nil
+}
+
+// Close closes every exiftool process of the pool, waiting for running extractions to
+// complete. If anything went wrong, a non empty error will be returned.
+func (p *Pool) Close() error {
+	p.lock.Lock()
+	if p.closed {
+		p.lock.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.done)
+	p.lock.Unlock()
+
+	var errs []error
+	for i := 0; i < p.size; i++ {
+		e := <-p.workers
+		if !e.alive() {
+			continue
+		}
+		if err := e.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("error while closing worker #%v: %w", i, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("error while closing pool: %v", errs)
+	}
+
+	return nil
+}
+
+// ExtractMetadata extracts metadata from files, dispatching each file to the first
+// available worker. Results are returned in the same order as files.
+func (p *Pool) ExtractMetadata(files ...string) []FileMetadata {
+	return p.ExtractMetadataContext(context.Background(), files...)
+}
+
+// ExtractMetadataContext behaves like ExtractMetadata but aborts when ctx is done, see
+// Exiftool.ExtractMetadataContext. Workers killed because of ctx are restarted.
+func (p *Pool) ExtractMetadataContext(ctx context.Context, files ...string) []FileMetadata {
+	return p.ExtractContext(ctx, files)
+}
+
+// Extract extracts metadata from files configured by opts, dispatching each file (or each
+// batch of files, see WithBatchSize) to the first available worker. Results are returned
+// in the same order as files.
+// Sample :
+//   fms := p.Extract(files, WithFast(2))
+func (p *Pool) Extract(files []string, opts ...ExtractOption) []FileMetadata {
+	return p.ExtractContext(context.Background(), files, opts...)
+}
+
+// ExtractContext behaves like Extract but aborts when ctx is done, see
+// ExtractMetadataContext.
+func (p *Pool) ExtractContext(ctx context.Context, files []string, opts ...ExtractOption) []FileMetadata {
+	fms := make([]FileMetadata, len(files))
+
+	// the progress and the deduplication are handled for the whole batch, not by each
+	// worker; the errors of opts are reported by the workers
+	cfg := extractConfig{dedup: p.dedup, batch: 1}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	prog := newProgress(cfg.progress, len(files))
+	opts = append(append([]ExtractOption{}, opts...), withoutProgress())
+
+	var first []int
+	if cfg.dedup {
+		first = firstOccurrences(files)
+	}
+
+	// each worker gets a file, or a batch of cfg.batch files
+	var chunks [][]int
+	for i := range files {
+		if first != nil && first[i] != i {
+			continue
+		}
+		if n := len(chunks); n == 0 || len(chunks[n-1]) >= cfg.batch {
+			chunks = append(chunks, nil)
+		}
+		chunks[len(chunks)-1] = append(chunks[len(chunks)-1], i)
+	}
+
+	var wg sync.WaitGroup
+	for _, chunk := range chunks {
+		cfiles := make([]string, len(chunk))
+		for j, i := range chunk {
+			cfiles[j] = files[i]
+		}
+
+		e, err := p.acquire(ctx)
+		if err != nil {
+			for _, i := range chunk {
+				fms[i] = FileMetadata{File: files[i], Err: err, Index: i}
+				prog.done(files[i])
+			}
+			continue
+		}
+
+		wg.Add(1)
+		go func(chunk []int, cfiles []string, e *Exiftool) {
+			defer wg.Done()
+			defer p.release(e)
+			for j, fm := range e.ExtractContext(ctx, cfiles, opts...) {
+				i := chunk[j]
+				fms[i] = fm
+				fms[i].Index = i
+				prog.done(files[i])
+			}
+		}(chunk, cfiles, e)
+	}
+	wg.Wait()
+
+	for i, f := range files {
+		if first != nil && first[i] != i {
+			fms[i] = fms[first[i]]
+			fms[i].Index = i
+			atomic.AddInt64(&p.dedups, 1)
+			prog.done(f)
+		}
+	}
+
+	return fms
+}
+
+// ExtractAll extracts metadata from files configured by opts, with at most concurrency
+// simultaneous extractions (the pool size if concurrency < 1), and streams the results
+// as they complete, hence not necessarily in the order of files unless PreserveOrder is
+// used. FileMetadata.Index is the position of the file in files. The returned channel is
+// closed once every file has been processed.
+// Sample :
+//   for fm := range p.ExtractAll(files, 4) {
+//     ...
+//   }
+func (p *Pool) ExtractAll(files []string, concurrency int, opts ...ExtractOption) <-chan FileMetadata {
+	if concurrency < 1 {
+		concurrency = p.size
+	}
+
+	// the errors of opts are reported by the extractions
+	var cfg extractConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	res := make(chan FileMetadata, concurrency)
+	go func() {
+		defer close(res)
+
+		if cfg.ordered {
+			p.extractOrdered(files, concurrency, opts, res)
+			return
+		}
+
+		sem := make(chan struct{}, concurrency)
+		var wg sync.WaitGroup
+		for i, f := range files {
+			p.extractAsync([]int{i}, []string{f}, opts, sem, &wg, func(fm FileMetadata) {
+				res <- fm
+			}, nil)
+		}
+		wg.Wait()
+	}()
+
+	return res
+}
+
+// extractOrdered extracts files with at most concurrency simultaneous extractions and
+// sends the results to res in the order of files
+func (p *Pool) extractOrdered(files []string, concurrency int, opts []ExtractOption, res chan<- FileMetadata) {
+	// each extraction gets a slot, which are emitted in order: the extraction of a file
+	// only starts once its slot could be queued
+	slots := make(chan chan FileMetadata, concurrency-1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for slot := range slots {
+			res <- <-slot
+		}
+	}()
+
+	for i, f := range files {
+		slot := make(chan FileMetadata, 1)
+		slots <- slot
+		go func(i int, f string) {
+			slot <- p.extractIndex(i, f, opts)
+		}(i, f)
+	}
+	close(slots)
+	<-done
+}
+
+// extractIndex extracts f, the file #i of a batch, configured by opts
+func (p *Pool) extractIndex(i int, f string, opts []ExtractOption) FileMetadata {
+	fm := p.Extract([]string{f}, opts...)[0]
+	fm.Index = i
+	return fm
+}
+
+// extractAsync extracts files, the files #idx of a batch, in a new goroutine once a slot
+// of sem is available, passes the results to emit and reports them to prog
+func (p *Pool) extractAsync(idx []int, files []string, opts []ExtractOption, sem chan struct{}, wg *sync.WaitGroup, emit func(FileMetadata), prog *progress) {
+	sem <- struct{}{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() { <-sem }()
+		for j, fm := range p.Extract(files, opts...) {
+			fm.Index = idx[j]
+			emit(fm)
+			prog.done(files[j])
+		}
+	}()
+}
+
+// acquire waits for an idle worker, restarting it if its process died
+func (p *Pool) acquire(ctx context.Context) (*Exiftool, error) {
+	p.queued(1)
+	defer p.queued(-1)
+
+	var e *Exiftool
+	select {
+	case e = <-p.workers:
+	case <-p.done:
+		return nil, ErrPoolClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	select {
+	case <-p.done:
+		p.release(e)
+		return nil, ErrPoolClosed
+	default:
+	}
+
+	if e.alive() {
+		return e, nil
+	}
+
+	ne, err := NewExiftool(p.opts...)
+	e.log("restart", "attempt", 1, "err", err)
+	if err != nil {
+		p.release(e)
+		return nil, fmt.Errorf("error when restarting worker: %w", err)
+	}
+	p.replace(e, ne)
+
+	return ne, nil
+}
+
+// queued adds delta to the number of extractions waiting for an idle worker
+func (p *Pool) queued(delta int64) {
+	n := atomic.AddInt64(&p.waiting, delta)
+	if p.metrics != nil {
+		p.metrics.SetQueueDepth(int(n))
+	}
+}
+
+func (p *Pool) release(e *Exiftool) {
+	p.workers <- e
+}
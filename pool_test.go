@@ -0,0 +1,154 @@
+package exiftool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPoolInvalidSize(t *testing.T) {
+	_, err := NewPool(0)
+	assert.NotNil(t, err)
+}
+
+func TestNewPoolOptKo(t *testing.T) {
+	f := func(*Exiftool) error {
+		return fmt.Errorf("err")
+	}
+	_, err := NewPool(2, f)
+	assert.NotNil(t, err)
+}
+
+func TestPoolExtract(t *testing.T) {
+	p, err := NewPool(2)
+	assert.Nil(t, err)
+	defer p.Close()
+
+	files := []string{
+		"./testdata/20190404_131804.jpg",
+		"./testdata/nonExisting",
+		"./testdata/20190404_131804.jpg",
+		"./testdata/empty.jpg",
+	}
+	fms := p.ExtractMetadata(files...)
+	assert.Equal(t, len(files), len(fms))
+	for i, fm := range fms {
+		assert.Equal(t, files[i], fm.File)
+		assert.Equal(t, i, fm.Index)
+	}
+	assert.Nil(t, fms[0].Err)
+	assert.True(t, errors.Is(fms[1].Err, ErrNotExist))
+	assert.Nil(t, fms[2].Err)
+	assert.Nil(t, fms[3].Err)
+}
+
+func TestPoolRestartsDeadWorker(t *testing.T) {
+	p, err := NewPool(1)
+	assert.Nil(t, err)
+	defer p.Close()
+
+	e := <-p.workers
+	assert.Nil(t, e.Close())
+	<-e.exited
+	assert.False(t, e.alive())
+	p.release(e)
+
+	fms := p.ExtractMetadata("./testdata/20190404_131804.jpg")
+	assert.Equal(t, 1, len(fms))
+	assert.Nil(t, fms[0].Err)
+}
+
+func TestPoolClosed(t *testing.T) {
+	p, err := NewPool(1)
+	assert.Nil(t, err)
+	assert.Nil(t, p.Close())
+	assert.Nil(t, p.Close())
+
+	fms := p.ExtractMetadata("./testdata/20190404_131804.jpg")
+	assert.Equal(t, 1, len(fms))
+	assert.Equal(t, ErrPoolClosed, fms[0].Err)
+}
+
+func TestPoolExtractContextCanceled(t *testing.T) {
+	p, err := NewPool(1)
+	assert.Nil(t, err)
+	defer p.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	fms := p.ExtractMetadataContext(ctx, "./testdata/20190404_131804.jpg")
+	assert.Equal(t, 1, len(fms))
+	assert.Equal(t, context.Canceled, fms[0].Err)
+
+	fms = p.ExtractMetadata("./testdata/20190404_131804.jpg")
+	assert.Equal(t, 1, len(fms))
+	assert.Nil(t, fms[0].Err)
+}
+
+func TestPoolExtractAll(t *testing.T) {
+	p, err := NewPool(2)
+	assert.Nil(t, err)
+	defer p.Close()
+
+	files := []string{
+		"./testdata/20190404_131804.jpg",
+		"./testdata/nonExisting",
+		"./testdata/empty.jpg",
+		"./testdata/20190404_131804.jpg",
+		"./testdata/extractEmbedded.mp4",
+	}
+	for _, concurrency := range []int{0, 1, 3} {
+		counts := map[string]int{}
+		for fm := range p.ExtractAll(files, concurrency) {
+			counts[fm.File]++
+			assert.Equal(t, files[fm.Index], fm.File)
+			assert.Equal(t, fm.File == "./testdata/nonExisting", fm.Err != nil)
+		}
+		assert.Equal(t, map[string]int{
+			"./testdata/20190404_131804.jpg": 2,
+			"./testdata/nonExisting":         1,
+			"./testdata/empty.jpg":           1,
+			"./testdata/extractEmbedded.mp4": 1,
+		}, counts)
+	}
+}
+
+func TestPoolExtractAllPreserveOrder(t *testing.T) {
+	p, err := NewPool(3)
+	assert.Nil(t, err)
+	defer p.Close()
+
+	var files []string
+	for i := 0; i < 20; i++ {
+		files = append(files, "./testdata/20190404_131804.jpg", "./testdata/extractEmbedded.mp4")
+	}
+	for _, concurrency := range []int{0, 1, 4} {
+		i := 0
+		for fm := range p.ExtractAll(files, concurrency, PreserveOrder()) {
+			assert.Equal(t, i, fm.Index)
+			assert.Equal(t, files[i], fm.File)
+			assert.Nil(t, fm.Err)
+			i++
+		}
+		assert.Equal(t, len(files), i)
+	}
+}
+
+func TestPoolExtractWithOptions(t *testing.T) {
+	p, err := NewPool(2, Fast(1))
+	assert.Nil(t, err)
+	defer p.Close()
+
+	files := []string{"./testdata/20190404_131804.jpg", "./testdata/nonExisting"}
+	fms := p.Extract(files, WithFast(2))
+	assert.Equal(t, len(files), len(fms))
+	assert.Nil(t, fms[0].Err)
+	assert.True(t, errors.Is(fms[1].Err, ErrNotExist))
+
+	fms = p.Extract(files, WithFast(-1))
+	assert.NotNil(t, fms[0].Err)
+}
@@ -0,0 +1,120 @@
+package exiftool
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	// decoders of the formats of the embedded previews
+	_ "image/jpeg"
+	_ "image/png"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// previewLabels are the tags holding embedded previews, by order of preference when
+// they have the same size
+var previewLabels = []string{"JpgFromRaw", "PreviewImage", "OtherImage", "ThumbnailImage", "CoverArt"}
+
+// binarySizeRegexp extracts the size of a binary value from its placeholder
+var binarySizeRegexp = regexp.MustCompile(`^\(Binary data (\d+) bytes`)
+
+// PreviewInfo describes the preview returned by BestPreview
+type PreviewInfo struct {
+	// Tag is the key of the tag holding the preview (ie. "EXIF:ThumbnailImage")
+	Tag string
+	// Format is the image format (ie. "jpeg"), empty if it isn't supported
+	Format string
+	// Width and Height are the dimensions of the preview, zero if its format isn't
+	// supported
+	Width  int
+	Height int
+	// Size is the size of the preview, in bytes
+	Size int
+}
+
+// previewCandidate is an embedded preview found among the metadata
+type previewCandidate struct {
+	key   string
+	size  int
+	value []byte
+}
+
+// previewCandidates returns the embedded previews of fm, largest first. Their value is
+// only set when they were extracted inline (see BinaryValues).
+func (fm FileMetadata) previewCandidates() ([]previewCandidate, error) {
+	var res []previewCandidate
+	for _, label := range previewLabels {
+		for _, n := range fm.groupNames() {
+			g := fm.Groups[n]
+			v, found := g.field(label)
+			if !found {
+				continue
+			}
+
+			c := previewCandidate{key: n + ":" + label}
+			if m := binarySizeRegexp.FindStringSubmatch(toString(v)); m != nil {
+				c.size, _ = strconv.Atoi(m[1])
+			} else if strings.HasPrefix(toString(v), base64Prefix) {
+				b, err := g.GetBytes(label)
+				if err != nil {
+					return nil, fmt.Errorf("error while decoding %v: %w", c.key, err)
+				}
+				c.size, c.value = len(b), b
+			} else {
+				continue
+			}
+			if c.size > 0 {
+				res = append(res, c)
+			}
+		}
+	}
+
+	// keeping the order of preference for identical sizes
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].size > res[j].size
+	})
+	return res, nil
+}
+
+// BestPreview returns the largest embedded preview of file (JpgFromRaw, PreviewImage,
+// OtherImage, ThumbnailImage or the CoverArt of a video), along with its format and its
+// dimensions, avoiding to decode the whole image (ie. RAW files). ErrKeyNotFound will be
+// returned if file has no embedded preview. As the preview is read like any other
+// exiftool output, the Buffer option may have to be used.
+// Sample :
+//   b, pi, err := e.BestPreview("a.cr2")
+func (e *Exiftool) BestPreview(file string) ([]byte, PreviewInfo, error) {
+	fm := e.ExtractMetadata(file)[0]
+	if fm.Err != nil {
+		return nil, PreviewInfo{}, fm.Err
+	}
+
+	cs, err := fm.previewCandidates()
+	if err != nil {
+		return nil, PreviewInfo{}, err
+	}
+	if len(cs) == 0 {
+		return nil, PreviewInfo{}, ErrKeyNotFound
+	}
+
+	c := cs[0]
+	b := c.value
+	if b == nil {
+		if b, err = e.ExtractBinary(file, c.key); err != nil {
+			return nil, PreviewInfo{}, fmt.Errorf("error while extracting %v: %w", c.key, err)
+		}
+	}
+
+	return b, previewInfo(c.key, b), nil
+}
+
+// previewInfo describes the preview b, read from the tag key
+func previewInfo(key string, b []byte) PreviewInfo {
+	pi := PreviewInfo{Tag: key, Size: len(b)}
+	if cfg, format, err := image.DecodeConfig(bytes.NewReader(b)); err == nil {
+		pi.Format, pi.Width, pi.Height = format, cfg.Width, cfg.Height
+	}
+	return pi
+}
@@ -0,0 +1,84 @@
+package exiftool
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/jpeg"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testJPEG(t *testing.T, w, h int) []byte {
+	var buf bytes.Buffer
+	assert.Nil(t, jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, w, h)), nil))
+	return buf.Bytes()
+}
+
+func TestPreviewCandidates(t *testing.T) {
+	inline := testJPEG(t, 4, 2)
+
+	var tcs = []struct {
+		tcID    string
+		in      map[string]FileMetadataValues
+		expOk   bool
+		expKeys []string
+		expSize []int
+	}{
+		{"placeholders", map[string]FileMetadataValues{
+			"EXIF":       {{"ThumbnailImage", "(Binary data 5024 bytes, use -b option to extract)"}},
+			"MakerNotes": {{"PreviewImage", "(Binary data 105024 bytes, use -b option to extract)"}},
+			"Composite":  {{"JpgFromRaw", "(Binary data 5024 bytes, use -b option to extract)"}},
+		}, true, []string{"MakerNotes:PreviewImage", "Composite:JpgFromRaw", "EXIF:ThumbnailImage"}, []int{105024, 5024, 5024}},
+		{"inline", map[string]FileMetadataValues{
+			"EXIF": {{"ThumbnailImage", base64Prefix + base64.StdEncoding.EncodeToString(inline)}},
+		}, true, []string{"EXIF:ThumbnailImage"}, []int{len(inline)}},
+		{"empty", map[string]FileMetadataValues{
+			"EXIF": {{"ThumbnailImage", "(Binary data 0 bytes, use -b option to extract)"}, {"PreviewImage", "none"}},
+		}, true, nil, nil},
+		{"invalidBase64", map[string]FileMetadataValues{
+			"EXIF": {{"ThumbnailImage", "base64:!!"}},
+		}, false, nil, nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			cs, err := FileMetadata{Groups: tc.in}.previewCandidates()
+			assert.Equal(t, tc.expOk, err == nil)
+			var keys []string
+			var sizes []int
+			for _, c := range cs {
+				keys = append(keys, c.key)
+				sizes = append(sizes, c.size)
+			}
+			assert.Equal(t, tc.expKeys, keys)
+			assert.Equal(t, tc.expSize, sizes)
+		})
+	}
+}
+
+func TestPreviewInfo(t *testing.T) {
+	b := testJPEG(t, 4, 2)
+	assert.Equal(t, PreviewInfo{Tag: "EXIF:ThumbnailImage", Format: "jpeg", Width: 4, Height: 2, Size: len(b)}, previewInfo("EXIF:ThumbnailImage", b))
+	assert.Equal(t, PreviewInfo{Tag: "QuickTime:CoverArt", Size: 3}, previewInfo("QuickTime:CoverArt", []byte("abc")))
+}
+
+func TestBestPreview(t *testing.T) {
+	e, err := NewExiftool(Buffer(make([]byte, 128*1000), 1024*1000))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	b, pi, err := e.BestPreview("./testdata/20190404_131804.jpg")
+	assert.Nil(t, err)
+	assert.Equal(t, "jpeg", pi.Format)
+	assert.Equal(t, len(b), pi.Size)
+	assert.True(t, pi.Width > 0 && pi.Height > 0)
+
+	_, _, err = e.BestPreview("./testdata/empty.jpg")
+	assert.Equal(t, ErrKeyNotFound, err)
+
+	_, _, err = e.BestPreview("./testdata/nonExisting")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,73 @@
+package exiftool
+
+import (
+	"sync"
+	"time"
+)
+
+// ProgressFunc is called each time a file of a batch has been processed, with the
+// number of files processed so far, the total number of files of the batch, the file
+// that has just been processed and the time elapsed since the batch started. Calls are
+// sequential.
+type ProgressFunc func(completed, total int, file string, elapsed time.Duration)
+
+// WithProgress calls fn each time a file of the extraction has been processed
+// Sample :
+//   fms := e.Extract(files, WithProgress(func(completed, total int, file string, elapsed time.Duration) {
+//     fmt.Printf("%v/%v\n", completed, total)
+//   }))
+func WithProgress(fn ProgressFunc) ExtractOption {
+	return func(c *extractConfig) error {
+		c.progress = fn
+		return nil
+	}
+}
+
+// withoutProgress disables the progress reporting of an extraction, which is used when
+// the progress is reported by the caller (ie. a Pool)
+func withoutProgress() ExtractOption {
+	return func(c *extractConfig) error {
+		c.progress = nil
+		return nil
+	}
+}
+
+// DirProgress calls fn each time a file of the directory has been processed. The
+// directory is walked beforehand to count its files, the total being 0 if it is empty.
+// Sample :
+//   c, err := e.ExtractDir("photos", DirProgress(bar.Update))
+func DirProgress(fn ProgressFunc) DirOption {
+	return func(c *dirConfig) error {
+		c.progress = fn
+		return nil
+	}
+}
+
+// progress tracks the progress of a batch, a nil progress tracking nothing
+type progress struct {
+	fn        ProgressFunc
+	total     int
+	start     time.Time
+	lock      sync.Mutex
+	completed int
+}
+
+func newProgress(fn ProgressFunc, total int) *progress {
+	if fn == nil {
+		return nil
+	}
+	return &progress{fn: fn, total: total, start: time.Now()}
+}
+
+// done reports that file has been processed
+func (p *progress) done(file string) {
+	if p == nil {
+		return
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.completed++
+	p.fn(p.completed, p.total, file, time.Since(p.start))
+}
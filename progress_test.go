@@ -0,0 +1,95 @@
+package exiftool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// progressRecorder records the calls of a ProgressFunc
+type progressRecorder struct {
+	completed []int
+	totals    []int
+	files     []string
+}
+
+func (r *progressRecorder) record(completed, total int, file string, elapsed time.Duration) {
+	r.completed = append(r.completed, completed)
+	r.totals = append(r.totals, total)
+	r.files = append(r.files, file)
+}
+
+func TestProgress(t *testing.T) {
+	assert.Nil(t, newProgress(nil, 2))
+	var p *progress
+	p.done("a.jpg")
+
+	var r progressRecorder
+	p = newProgress(r.record, 2)
+	p.done("a.jpg")
+	p.done("b.jpg")
+	assert.Equal(t, []int{1, 2}, r.completed)
+	assert.Equal(t, []int{2, 2}, r.totals)
+	assert.Equal(t, []string{"a.jpg", "b.jpg"}, r.files)
+}
+
+func TestExtractWithProgress(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	files := []string{"./testdata/20190404_131804.jpg", "./testdata/nonExisting"}
+	var r progressRecorder
+	e.Extract(files, WithProgress(r.record))
+	assert.Equal(t, []int{1, 2}, r.completed)
+	assert.Equal(t, []int{2, 2}, r.totals)
+	assert.Equal(t, files, r.files)
+}
+
+func TestPoolExtractWithProgress(t *testing.T) {
+	p, err := NewPool(2)
+	assert.Nil(t, err)
+	defer p.Close()
+
+	files := []string{"./testdata/20190404_131804.jpg", "./testdata/nonExisting", "./testdata/empty.jpg"}
+	var r progressRecorder
+	p.Extract(files, WithProgress(r.record))
+	assert.Equal(t, []int{1, 2, 3}, r.completed)
+	assert.Equal(t, []int{3, 3, 3}, r.totals)
+	assert.ElementsMatch(t, files, r.files)
+}
+
+func TestExtractDirWithProgress(t *testing.T) {
+	root, clean := createTestTree(t, "a.jpg", "b.png", "sub/c.jpg")
+	defer clean()
+
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	p, err := NewPool(2)
+	assert.Nil(t, err)
+	defer p.Close()
+
+	var tcs = []struct {
+		tcID    string
+		extract func(string, ...DirOption) (<-chan FileMetadata, error)
+	}{
+		{"exiftool", e.ExtractDir},
+		{"pool", p.ExtractDir},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			var r progressRecorder
+			c, err := tc.extract(root, Extensions("jpg"), DirProgress(r.record))
+			assert.Nil(t, err)
+			for range c {
+			}
+			assert.Equal(t, []int{1, 2}, r.completed)
+			assert.Equal(t, []int{2, 2}, r.totals)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package exiftool
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Keys of the rating and label tags: the XMP ones being the reference, the Microsoft
+// ones (IFD0 Rating and RatingPercent) being written by Windows
+var (
+	ratingKeys        = []string{"XMP-xmp:Rating", "XMP:Rating", "EXIF:Rating", "IFD0:Rating"}
+	ratingPercentKeys = []string{"EXIF:RatingPercent", "IFD0:RatingPercent", "XMP-microsoft:RatingPercent", "XMP:RatingPercent"}
+	labelKeys         = []string{"XMP-xmp:Label", "XMP:Label"}
+)
+
+// ratingPercents are the RatingPercent values written by Windows for each rating
+var ratingPercents = []int64{0, 1, 25, 50, 75, 99}
+
+// percentToRating converts a Microsoft RatingPercent (0-100) to a rating (0-5)
+func percentToRating(p int64) int {
+	switch {
+	case p <= 0:
+		return 0
+	case p < 13:
+		return 1
+	case p < 38:
+		return 2
+	case p < 63:
+		return 3
+	case p < 88:
+		return 4
+	default:
+		return 5
+	}
+}
+
+// GetRating returns the rating of the file, from 0 (unrated) to 5 stars, -1 meaning
+// rejected, read from XMP:Rating, the Microsoft Rating or RatingPercent. ErrKeyNotFound
+// will be returned if the file has no rating.
+func (fm FileMetadata) GetRating() (int, error) {
+	r, err := fm.lookupInt(ratingKeys...)
+	if err == nil {
+		if r < -1 || r > 5 {
+			return 0, fmt.Errorf("invalid rating (%v)", r)
+		}
+		return int(r), nil
+	}
+	if err != ErrKeyNotFound {
+		return 0, fmt.Errorf("rating parsing error: %w", err)
+	}
+
+	p, err := fm.lookupInt(ratingPercentKeys...)
+	if err == ErrKeyNotFound {
+		return 0, err
+	}
+	if err != nil {
+		return 0, fmt.Errorf("rating percent parsing error: %w", err)
+	}
+
+	return percentToRating(p), nil
+}
+
+// SetRating writes the rating of file, from 0 (unrated) to 5 stars, -1 meaning rejected,
+// into XMP:Rating and the Microsoft Rating and RatingPercent tags, so that every
+// application sees the same rating. If anything went wrong, a non empty error will be
+// returned.
+// Sample :
+//   err := e.SetRating("photo.jpg", 4)
+func (e *Exiftool) SetRating(file string, rating int) error {
+	if rating < -1 || rating > 5 {
+		return fmt.Errorf("invalid rating (%v)", rating)
+	}
+
+	stars := rating
+	if stars < 0 {
+		stars = 0
+	}
+
+	return e.Write(file, FileMetadataValues{
+		{"XMP-xmp:Rating", int64(rating)},
+		{"IFD0:Rating", int64(stars)},
+		{"IFD0:RatingPercent", ratingPercents[stars]},
+		{"XMP-microsoft:RatingPercent", ratingPercents[stars]},
+	})
+}
+
+// GetLabel returns the label of the file (XMP:Label), usually a color label (ie. "Red")
+// set by DAM applications. ErrKeyNotFound will be returned if the file has no label.
+func (fm FileMetadata) GetLabel() (string, error) {
+	l, found := fm.lookupString(labelKeys...)
+	if !found {
+		return "", ErrKeyNotFound
+	}
+
+	return strings.TrimSpace(l), nil
+}
+
+// SetLabel writes the label of file (XMP:Label), an empty label removing it. If anything
+// went wrong, a non empty error will be returned.
+// Sample :
+//   err := e.SetLabel("photo.jpg", "Red")
+func (e *Exiftool) SetLabel(file string, label string) error {
+	var v interface{}
+	if label != "" {
+		v = label
+	}
+
+	return e.Write(file, FileMetadataValues{{labelKeys[0], v}})
+}
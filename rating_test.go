@@ -0,0 +1,104 @@
+package exiftool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPercentToRating(t *testing.T) {
+	for p, exp := range map[int64]int{0: 0, 1: 1, 12: 1, 25: 2, 50: 3, 75: 4, 99: 5, 100: 5} {
+		assert.Equal(t, exp, percentToRating(p), "percent %v", p)
+	}
+	for r, p := range ratingPercents {
+		assert.Equal(t, r, percentToRating(p))
+	}
+}
+
+func TestGetRating(t *testing.T) {
+	var tcs = []struct {
+		tcID      string
+		in        map[string]FileMetadataValues
+		expOk     bool
+		expRating int
+	}{
+		{"xmp", map[string]FileMetadataValues{"XMP": {{"Rating", float64(4)}}}, true, 4},
+		{"rejected", map[string]FileMetadataValues{"XMP": {{"Rating", float64(-1)}}}, true, -1},
+		{"exif", map[string]FileMetadataValues{"EXIF": {{"Rating", "3"}}}, true, 3},
+		{"xmpFirst", map[string]FileMetadataValues{"XMP": {{"Rating", float64(5)}}, "EXIF": {{"Rating", float64(2)}}}, true, 5},
+		{"percent", map[string]FileMetadataValues{"EXIF": {{"RatingPercent", float64(75)}}}, true, 4},
+		{"outOfRange", map[string]FileMetadataValues{"XMP": {{"Rating", float64(7)}}}, false, 0},
+		{"invalid", map[string]FileMetadataValues{"XMP": {{"Rating", "a"}}}, false, 0},
+		{"none", map[string]FileMetadataValues{"EXIF": {{"Make", "samsung"}}}, false, 0},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			r, err := FileMetadata{Groups: tc.in}.GetRating()
+			assert.Equal(t, tc.expOk, err == nil)
+			assert.Equal(t, tc.expRating, r)
+		})
+	}
+
+	_, err := FileMetadata{}.GetRating()
+	assert.Equal(t, ErrKeyNotFound, err)
+}
+
+func TestGetLabel(t *testing.T) {
+	l, err := FileMetadata{Groups: map[string]FileMetadataValues{"XMP": {{"Label", "Red "}}}}.GetLabel()
+	assert.Nil(t, err)
+	assert.Equal(t, "Red", l)
+
+	_, err = FileMetadata{}.GetLabel()
+	assert.Equal(t, ErrKeyNotFound, err)
+}
+
+func TestSetRatingAndLabelArgs(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+
+	var r CommandRecorder
+	e, err := NewExiftool(DryRun(&r))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	assert.NotNil(t, e.SetRating(f, 6))
+	assert.NotNil(t, e.SetRating(f, -2))
+	assert.Nil(t, e.SetRating(f, 4))
+	assert.Nil(t, e.SetRating(f, -1))
+	assert.Nil(t, e.SetLabel(f, "Red"))
+	assert.Nil(t, e.SetLabel(f, ""))
+
+	cmds := r.Commands()
+	assert.Equal(t, 4, len(cmds))
+	args := func(i, n int) []string { return cmds[i][len(cmds[i])-n-1 : len(cmds[i])-1] }
+	assert.Equal(t, []string{"-XMP-xmp:Rating=4", "-IFD0:Rating=4", "-IFD0:RatingPercent=75", "-XMP-microsoft:RatingPercent=75"}, args(0, 4))
+	assert.Equal(t, []string{"-XMP-xmp:Rating=-1", "-IFD0:Rating=0", "-IFD0:RatingPercent=0", "-XMP-microsoft:RatingPercent=0"}, args(1, 4))
+	assert.Equal(t, []string{"-XMP-xmp:Label=Red"}, args(2, 1))
+	assert.Equal(t, []string{"-XMP-xmp:Label="}, args(3, 1))
+}
+
+func TestSetRatingAndLabel(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	assert.Nil(t, e.SetRating(f, 3))
+	assert.Nil(t, e.SetLabel(f, "Green"))
+
+	fms := e.ExtractMetadata(f)
+	assert.Nil(t, fms[0].Err)
+	r, err := fms[0].GetRating()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, r)
+	l, err := fms[0].GetLabel()
+	assert.Nil(t, err)
+	assert.Equal(t, "Green", l)
+	p, err := fms[0].Groups["EXIF"].GetInt("RatingPercent")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(50), p)
+}
@@ -0,0 +1,252 @@
+package exiftool
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Region is an area of an image, as defined by the Metadata Working Group (ie. a face
+// tag). Its rectangle is normalized: the center (X, Y) and the size (W, H) are fractions
+// of the image dimensions.
+type Region struct {
+	Name        string
+	Type        string
+	Description string
+	X           float64
+	Y           float64
+	W           float64
+	H           float64
+}
+
+// Types of regions defined by the Metadata Working Group
+const (
+	RegionFace    = "Face"
+	RegionPet     = "Pet"
+	RegionFocus   = "Focus"
+	RegionBarCode = "BarCode"
+)
+
+var (
+	regionInfoKeys   = []string{"XMP-mwg-rs:RegionInfo", "XMP:RegionInfo"}
+	regionWidthKeys  = []string{"File:ImageWidth", "EXIF:ExifImageWidth", "ImageWidth"}
+	regionHeightKeys = []string{"File:ImageHeight", "EXIF:ExifImageHeight", "ImageHeight"}
+)
+
+// regionField returns the keys of the flattened region tag named suffix (ie.
+// "RegionName")
+func regionField(suffix string) []string {
+	return []string{"XMP-mwg-rs:" + suffix, "XMP:" + suffix}
+}
+
+// Regions returns the MWG regions of the image (XMP-mwg-rs:RegionInfo), decoded from
+// the structure extracted with the Struct option or, if it isn't used, from the
+// flattened tags (XMP:RegionName, XMP:RegionAreaX...). ErrKeyNotFound will be returned if
+// the image has no region.
+func (fm FileMetadata) Regions() ([]Region, error) {
+	for _, k := range regionInfoKeys {
+		g, label, found := fm.lookup(k)
+		if !found {
+			continue
+		}
+		info, err := g.GetStruct(label)
+		if err != nil {
+			return nil, fmt.Errorf("region info parsing error: %w", err)
+		}
+		return structRegions(info)
+	}
+
+	return fm.flatRegions()
+}
+
+func structRegions(info FileMetadataValues) ([]Region, error) {
+	list, err := info.GetStructs("RegionList")
+	if err != nil {
+		return nil, err
+	}
+
+	regions := make([]Region, len(list))
+	for i, s := range list {
+		r := &regions[i]
+		r.Name, _ = s.GetString("Name")
+		r.Type, _ = s.GetString("Type")
+		r.Description, _ = s.GetString("Description")
+
+		area, err := s.GetStruct("Area")
+		if err != nil {
+			return nil, fmt.Errorf("region #%v area parsing error: %w", i, err)
+		}
+		for _, c := range []struct {
+			label string
+			dst   *float64
+		}{
+			{"X", &r.X}, {"Y", &r.Y}, {"W", &r.W}, {"H", &r.H},
+		} {
+			if *c.dst, err = area.GetFloat(c.label); err != nil {
+				return nil, fmt.Errorf("region #%v area parsing error (%v): %w", i, c.label, err)
+			}
+		}
+	}
+
+	return regions, nil
+}
+
+// flatRegions decodes the regions from the flattened tags, whose lists are expected to
+// be aligned
+func (fm FileMetadata) flatRegions() ([]Region, error) {
+	xs, found := fm.lookupStrings(regionField("RegionAreaX")...)
+	if !found {
+		return nil, ErrKeyNotFound
+	}
+
+	regions := make([]Region, len(xs))
+	for _, c := range []struct {
+		suffix string
+		set    func(r *Region, v string) error
+	}{
+		{"RegionAreaX", func(r *Region, v string) (err error) { r.X, err = toFloatFallback(v); return }},
+		{"RegionAreaY", func(r *Region, v string) (err error) { r.Y, err = toFloatFallback(v); return }},
+		{"RegionAreaW", func(r *Region, v string) (err error) { r.W, err = toFloatFallback(v); return }},
+		{"RegionAreaH", func(r *Region, v string) (err error) { r.H, err = toFloatFallback(v); return }},
+		{"RegionName", func(r *Region, v string) error { r.Name = v; return nil }},
+		{"RegionType", func(r *Region, v string) error { r.Type = v; return nil }},
+		{"RegionDescription", func(r *Region, v string) error { r.Description = v; return nil }},
+	} {
+		vs, _ := fm.lookupStrings(regionField(c.suffix)...)
+		for i, v := range vs {
+			if i >= len(regions) {
+				break
+			}
+			if err := c.set(&regions[i], v); err != nil {
+				return nil, fmt.Errorf("region #%v parsing error (%v): %w", i, c.suffix, err)
+			}
+		}
+	}
+
+	return regions, nil
+}
+
+// escapeStructValue escapes v for exiftool's serialized structures, see
+// https://exiftool.org/struct.html#Serialize
+func escapeStructValue(v string) string {
+	var sb strings.Builder
+	for i, c := range v {
+		if strings.ContainsRune("|,]}", c) || (i == 0 && strings.ContainsRune("[{ \t", c)) {
+			sb.WriteRune('|')
+		}
+		sb.WriteRune(c)
+	}
+	return sb.String()
+}
+
+// serializeRegions serializes regions into the RegionInfo structure of an image of
+// width x height pixels
+func serializeRegions(regions []Region, width, height int64) string {
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+
+	items := make([]string, len(regions))
+	for i, r := range regions {
+		fields := []string{fmt.Sprintf("Area={X=%v,Y=%v,W=%v,H=%v,Unit=normalized}", f(r.X), f(r.Y), f(r.W), f(r.H))}
+		for _, kv := range [][2]string{{"Name", r.Name}, {"Type", r.Type}, {"Description", r.Description}} {
+			if kv[1] != "" {
+				fields = append(fields, kv[0]+"="+escapeStructValue(kv[1]))
+			}
+		}
+		items[i] = "{" + strings.Join(fields, ",") + "}"
+	}
+
+	return fmt.Sprintf("{AppliedToDimensions={W=%v,H=%v,Unit=pixel},RegionList=[%v]}", width, height, strings.Join(items, ","))
+}
+
+func checkRegions(regions []Region) error {
+	for i, r := range regions {
+		if r.W <= 0 || r.H <= 0 || r.X < 0 || r.X > 1 || r.Y < 0 || r.Y > 1 || r.W > 1 || r.H > 1 {
+			return fmt.Errorf("region #%v is not normalized (%+v)", i, r)
+		}
+		for _, s := range []string{r.Name, r.Type, r.Description} {
+			if strings.ContainsAny(s, "\r\n") {
+				return fmt.Errorf("region #%v: line breaks are not supported (%q)", i, s)
+			}
+		}
+	}
+	return nil
+}
+
+// SetRegions replaces the MWG regions of file (XMP-mwg-rs:RegionInfo) by regions, their
+// dimensions being the current dimensions of the image. The regions are removed if none
+// is provided. If anything went wrong, a non empty error will be returned.
+// Sample :
+//
+//	err := e.SetRegions("photo.jpg", Region{Name: "Bob", Type: RegionFace, X: 0.5, Y: 0.4, W: 0.1, H: 0.15})
+func (e *Exiftool) SetRegions(file string, regions ...Region) error {
+	if err := checkRegions(regions); err != nil {
+		return err
+	}
+
+	if len(regions) == 0 {
+		return e.Write(file, FileMetadataValues{{Label: regionInfoKeys[0]}})
+	}
+
+	fm := e.ExtractMetadata(file)[0]
+	if fm.Err != nil {
+		return fm.Err
+	}
+
+	return e.writeRegions(fm, regions)
+}
+
+// AddRegions adds regions to the MWG regions of file, a region replacing the existing
+// one having the same name and type. If anything went wrong, a non empty error will be
+// returned.
+func (e *Exiftool) AddRegions(file string, regions ...Region) error {
+	if len(regions) == 0 {
+		return fmt.Errorf("no region to add")
+	}
+	if err := checkRegions(regions); err != nil {
+		return err
+	}
+
+	fm := e.ExtractMetadata(file)[0]
+	if fm.Err != nil {
+		return fm.Err
+	}
+
+	existing, err := fm.Regions()
+	if err != nil && err != ErrKeyNotFound {
+		return err
+	}
+
+	return e.writeRegions(fm, mergeRegions(existing, regions))
+}
+
+// mergeRegions adds regions to existing, replacing the ones having the same name and
+// type
+func mergeRegions(existing, regions []Region) []Region {
+	res := append([]Region{}, existing...)
+	for _, r := range regions {
+		replaced := false
+		for i, ex := range res {
+			if r.Name != "" && ex.Name == r.Name && ex.Type == r.Type {
+				res[i], replaced = r, true
+				break
+			}
+		}
+		if !replaced {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+func (e *Exiftool) writeRegions(fm FileMetadata, regions []Region) error {
+	w, err := fm.lookupInt(regionWidthKeys...)
+	if err != nil {
+		return fmt.Errorf("error while reading image width: %w", err)
+	}
+	h, err := fm.lookupInt(regionHeightKeys...)
+	if err != nil {
+		return fmt.Errorf("error while reading image height: %w", err)
+	}
+
+	return e.Write(fm.File, FileMetadataValues{{regionInfoKeys[0], serializeRegions(regions, w, h)}})
+}
@@ -0,0 +1,147 @@
+package exiftool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegions(t *testing.T) {
+	area := func(x, y, w, h float64) FileMetadataValues {
+		return FileMetadataValues{{"H", h}, {"Unit", "normalized"}, {"W", w}, {"X", x}, {"Y", y}}
+	}
+
+	var tcs = []struct {
+		tcID       string
+		in         map[string]FileMetadataValues
+		expOk      bool
+		expRegions []Region
+	}{
+		{"struct", map[string]FileMetadataValues{"XMP": {{"RegionInfo", FileMetadataValues{
+			{"AppliedToDimensions", FileMetadataValues{{"H", float64(3024)}, {"Unit", "pixel"}, {"W", float64(4032)}}},
+			{"RegionList", []interface{}{
+				FileMetadataValues{{"Area", area(0.5, 0.4, 0.1, 0.2)}, {"Name", "Bob"}, {"Type", "Face"}},
+				FileMetadataValues{{"Area", area(0.2, 0.3, 0.05, 0.05)}, {"Type", "Focus"}, {"Description", "d"}},
+			}},
+		}}}}, true, []Region{
+			{Name: "Bob", Type: "Face", X: 0.5, Y: 0.4, W: 0.1, H: 0.2},
+			{Type: "Focus", Description: "d", X: 0.2, Y: 0.3, W: 0.05, H: 0.05},
+		}},
+		{"flattened", map[string]FileMetadataValues{"XMP": {
+			{"RegionAppliedToDimensionsW", float64(4032)},
+			{"RegionAreaX", []interface{}{float64(0.5), float64(0.2)}},
+			{"RegionAreaY", []interface{}{float64(0.4), float64(0.3)}},
+			{"RegionAreaW", []interface{}{float64(0.1), float64(0.05)}},
+			{"RegionAreaH", []interface{}{float64(0.2), float64(0.05)}},
+			{"RegionName", []interface{}{"Bob", "Alice"}},
+			{"RegionType", []interface{}{"Face", "Face"}},
+		}}, true, []Region{
+			{Name: "Bob", Type: "Face", X: 0.5, Y: 0.4, W: 0.1, H: 0.2},
+			{Name: "Alice", Type: "Face", X: 0.2, Y: 0.3, W: 0.05, H: 0.05},
+		}},
+		{"flattenedSingle", map[string]FileMetadataValues{"XMP": {
+			{"RegionAreaX", float64(0.5)},
+			{"RegionAreaY", float64(0.4)},
+			{"RegionAreaW", float64(0.1)},
+			{"RegionAreaH", float64(0.2)},
+		}}, true, []Region{{X: 0.5, Y: 0.4, W: 0.1, H: 0.2}}},
+		{"invalidArea", map[string]FileMetadataValues{"XMP": {{"RegionInfo", FileMetadataValues{
+			{"RegionList", []interface{}{FileMetadataValues{{"Area", FileMetadataValues{{"X", "a"}}}}}},
+		}}}}, false, nil},
+		{"notStruct", map[string]FileMetadataValues{"XMP": {{"RegionInfo", "a"}}}, false, nil},
+		{"none", map[string]FileMetadataValues{"EXIF": {{"Make", "samsung"}}}, false, nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			regions, err := FileMetadata{Groups: tc.in}.Regions()
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.expRegions, regions)
+			}
+		})
+	}
+
+	_, err := FileMetadata{}.Regions()
+	assert.Equal(t, ErrKeyNotFound, err)
+}
+
+func TestEscapeStructValue(t *testing.T) {
+	assert.Equal(t, "Bob", escapeStructValue("Bob"))
+	assert.Equal(t, "a|, b|}|]||c", escapeStructValue("a, b}]|c"))
+	assert.Equal(t, "|[a|]", escapeStructValue("[a]"))
+	assert.Equal(t, "| a", escapeStructValue(" a"))
+}
+
+func TestSerializeRegions(t *testing.T) {
+	s := serializeRegions([]Region{
+		{Name: "Bob, Jr", Type: RegionFace, X: 0.5, Y: 0.4, W: 0.1, H: 0.2},
+		{Type: RegionFocus, X: 0.2, Y: 0.3, W: 0.05, H: 0.05},
+	}, 4032, 3024)
+	assert.Equal(t, "{AppliedToDimensions={W=4032,H=3024,Unit=pixel},RegionList=["+
+		"{Area={X=0.5,Y=0.4,W=0.1,H=0.2,Unit=normalized},Name=Bob|, Jr,Type=Face},"+
+		"{Area={X=0.2,Y=0.3,W=0.05,H=0.05,Unit=normalized},Type=Focus}]}", s)
+}
+
+func TestCheckRegions(t *testing.T) {
+	var tcs = []struct {
+		tcID  string
+		in    Region
+		expOk bool
+	}{
+		{"ok", Region{Name: "Bob", X: 0.5, Y: 0.5, W: 0.1, H: 0.1}, true},
+		{"emptySize", Region{X: 0.5, Y: 0.5}, false},
+		{"pixels", Region{X: 100, Y: 100, W: 10, H: 10}, false},
+		{"lineBreak", Region{Name: "a\nb", X: 0.5, Y: 0.5, W: 0.1, H: 0.1}, false},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			assert.Equal(t, tc.expOk, checkRegions([]Region{tc.in}) == nil)
+		})
+	}
+}
+
+func TestMergeRegions(t *testing.T) {
+	bob := Region{Name: "Bob", Type: RegionFace, X: 0.5, Y: 0.4, W: 0.1, H: 0.2}
+	bobPet := Region{Name: "Bob", Type: RegionPet, X: 0.1, Y: 0.1, W: 0.1, H: 0.1}
+	focus := Region{Type: RegionFocus, X: 0.2, Y: 0.3, W: 0.05, H: 0.05}
+	movedBob := bob
+	movedBob.X = 0.6
+
+	existing := []Region{bob, focus}
+	assert.Equal(t, []Region{movedBob, focus, bobPet, focus}, mergeRegions(existing, []Region{movedBob, bobPet, focus}))
+	assert.Equal(t, []Region{bob, focus}, existing)
+}
+
+func TestSetAndAddRegions(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+
+	e, err := NewExiftool(Struct())
+	assert.Nil(t, err)
+	defer e.Close()
+
+	bob := Region{Name: "Bob", Type: RegionFace, X: 0.5, Y: 0.4, W: 0.1, H: 0.2}
+	alice := Region{Name: "Alice", Type: RegionFace, X: 0.2, Y: 0.3, W: 0.05, H: 0.05}
+	assert.NotNil(t, e.SetRegions(f, Region{}))
+	assert.NotNil(t, e.AddRegions(f))
+	assert.Nil(t, e.SetRegions(f, bob))
+
+	movedBob := bob
+	movedBob.X = 0.6
+	assert.Nil(t, e.AddRegions(f, alice, movedBob))
+
+	fms := e.ExtractMetadata(f)
+	assert.Nil(t, fms[0].Err)
+	regions, err := fms[0].Regions()
+	assert.Nil(t, err)
+	assert.Equal(t, []Region{movedBob, alice}, regions)
+
+	assert.Nil(t, e.SetRegions(f))
+	fms = e.ExtractMetadata(f)
+	_, err = fms[0].Regions()
+	assert.Equal(t, ErrKeyNotFound, err)
+}
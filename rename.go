@@ -0,0 +1,138 @@
+package exiftool
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RenameOption is a configuration function of Rename
+type RenameOption func(*renameConfig) error
+
+type renameConfig struct {
+	tags   []string
+	dryRun bool
+}
+
+// renamedRegexp matches the "'OLD' --> 'NEW'" lines printed by exiftool when a file is
+// (or would be) renamed
+var renamedRegexp = regexp.MustCompile(`^'(.*)' --> '(.*)'$`)
+
+// RenameTags defines the tags whose value is formatted to build the new file names
+// (DateTimeOriginal by default). As with exiftool, the last tag existing in a file wins,
+// hence tags are expected from the less to the most preferred one.
+// Sample :
+//   m, err := e.Rename(files, "%Y%m%d_%H%M%S%%-c.%%e", RenameTags("FileModifyDate", "CreateDate", "DateTimeOriginal"))
+func RenameTags(tags ...string) RenameOption {
+	return func(c *renameConfig) error {
+		if len(tags) == 0 {
+			return fmt.Errorf("no tag")
+		}
+		for _, t := range tags {
+			if t == "" {
+				return fmt.Errorf("empty tag")
+			}
+		}
+		c.tags = tags
+		return nil
+	}
+}
+
+// RenameDryRun makes Rename return the planned renaming without touching any file
+// (relies on exiftool's TestName tag)
+func RenameDryRun() RenameOption {
+	return func(c *renameConfig) error {
+		c.dryRun = true
+		return nil
+	}
+}
+
+// renameArgs returns the arguments of a renaming command, files excluded, and whether
+// it is a dry run
+func renameArgs(template string, opts []RenameOption) ([]string, bool, error) {
+	if template == "" {
+		return nil, false, fmt.Errorf("empty template")
+	}
+	if strings.ContainsAny(template, "\r\n") {
+		return nil, false, fmt.Errorf("line breaks are not supported (%v)", template)
+	}
+
+	c := renameConfig{tags: []string{"DateTimeOriginal"}}
+	for _, opt := range opts {
+		if err := opt(&c); err != nil {
+			return nil, false, fmt.Errorf("error when configuring renaming: %w", err)
+		}
+	}
+
+	tag, args := "-FileName<", []string{"-v"}
+	if c.dryRun {
+		tag, args = "-TestName<", nil
+	}
+	args = append(args, "-d", template)
+	for _, t := range c.tags {
+		args = append(args, tag+t)
+	}
+
+	return args, c.dryRun, nil
+}
+
+// parseRenamed returns the old to new name mapping printed by exiftool
+func parseRenamed(out []byte) map[string]string {
+	m := map[string]string{}
+	for _, l := range strings.Split(string(out), "\n") {
+		if sm := renamedRegexp.FindStringSubmatch(strings.TrimSpace(l)); sm != nil {
+			m[sm[1]] = sm[2]
+		}
+	}
+	return m
+}
+
+// Rename renames files from their metadata (activates Exiftool's '-FileName<' and '-d'
+// parameters): the date of the tags (see RenameTags) is formatted with template, see
+// https://exiftool.org/filename.html. "%%e" is replaced by the extension of the file and
+// "%%-c" by a counter avoiding collisions. Rename returns the old to new name mapping
+// of the renamed files, files that can't be renamed (ie. because the tags are missing)
+// being absent. With RenameDryRun, the planned mapping is returned and no file is
+// touched. The cached extractions of the renamed files are dropped, see
+// CacheInvalidator. If anything went wrong, a non empty error will be returned.
+// Sample :
+//   m, err := e.Rename([]string{"IMG_0001.JPG"}, "%Y%m%d_%H%M%S.%%e", RenameDryRun())
+func (e *Exiftool) Rename(files []string, template string, opts ...RenameOption) (map[string]string, error) {
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no file to rename")
+	}
+	for _, f := range files {
+		if err := e.checkFile(f); err != nil {
+			return nil, err
+		}
+	}
+
+	args, dryRun, err := renameArgs(template, opts)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, files...)
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	out, err := e.executeWriting(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var u *FileUnchangedError
+	if err := checkWriteOutput("", out); err != nil && !errors.As(err, &u) {
+		return nil, err
+	}
+
+	m := parseRenamed(out)
+	if !dryRun {
+		for o, n := range m {
+			e.invalidateCache(o, n)
+		}
+	}
+
+	return m, nil
+}
@@ -0,0 +1,82 @@
+package exiftool
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenameArgs(t *testing.T) {
+	var tcs = []struct {
+		tcID       string
+		inTemplate string
+		inOpts     []RenameOption
+		expOk      bool
+		expArgs    []string
+	}{
+		{"default", "%Y%m%d.%%e", nil, true, []string{"-v", "-d", "%Y%m%d.%%e", "-FileName<DateTimeOriginal"}},
+		{"dryRun", "%Y%m%d.%%e", []RenameOption{RenameDryRun()}, true, []string{"-d", "%Y%m%d.%%e", "-TestName<DateTimeOriginal"}},
+		{"tags", "%Y.%%e", []RenameOption{RenameTags("FileModifyDate", "CreateDate")}, true,
+			[]string{"-v", "-d", "%Y.%%e", "-FileName<FileModifyDate", "-FileName<CreateDate"}},
+		{"emptyTemplate", "", nil, false, nil},
+		{"lineBreak", "%Y\n", nil, false, nil},
+		{"noTag", "%Y", []RenameOption{RenameTags()}, false, nil},
+		{"emptyTag", "%Y", []RenameOption{RenameTags("")}, false, nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			args, dryRun, err := renameArgs(tc.inTemplate, tc.inOpts)
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.expArgs, args)
+				assert.Equal(t, tc.tcID == "dryRun", dryRun)
+			}
+		})
+	}
+}
+
+func TestParseRenamed(t *testing.T) {
+	out := "======== a.jpg\n'a.jpg' --> '20190404_131804.jpg'\n'b c.jpg' --> 'd/e.jpg'\r\n    2 image files updated\n"
+	assert.Equal(t, map[string]string{"a.jpg": "20190404_131804.jpg", "b c.jpg": "d/e.jpg"}, parseRenamed([]byte(out)))
+	assert.Equal(t, map[string]string{}, parseRenamed([]byte("    0 image files updated\n")))
+}
+
+func TestRename(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+	dir := filepath.Dir(f)
+	exp := map[string]string{f: filepath.Join(dir, "20190404-131804.jpg")}
+
+	c := NewLRUCache(10)
+	e, err := NewExiftool(SetCache(c))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	_, err = e.Rename(nil, "%Y")
+	assert.NotNil(t, err)
+	_, err = e.Rename([]string{"./testdata/nonExisting"}, "%Y")
+	assert.True(t, errors.Is(err, ErrNotExist))
+	_, err = e.Rename([]string{f}, "")
+	assert.NotNil(t, err)
+
+	assert.Nil(t, e.ExtractMetadata(f)[0].Err)
+	m, err := e.Rename([]string{f}, dir+"/%Y%m%d-%H%M%S.%%e", RenameDryRun())
+	assert.Nil(t, err)
+	assert.Equal(t, exp, m)
+	assert.FileExists(t, f)
+	assert.Equal(t, 1, c.Len())
+
+	m, err = e.Rename([]string{f}, dir+"/%Y%m%d-%H%M%S.%%e")
+	assert.Nil(t, err)
+	assert.Equal(t, exp, m)
+	assert.FileExists(t, exp[f])
+	assert.Equal(t, 0, c.Len())
+
+	m, err = e.Rename([]string{exp[f]}, "%Y.%%e", RenameTags("XMP:CreateDate"))
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{}, m)
+}
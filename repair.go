@@ -0,0 +1,93 @@
+package exiftool
+
+import (
+	"fmt"
+	"sort"
+)
+
+// RepairOption is a configuration function of Repair
+type RepairOption func(*repairConfig) error
+
+type repairConfig struct {
+	dropMakerNotes bool
+}
+
+// RepairDropMakerNotes doesn't copy the maker notes back into the rebuilt metadata,
+// which is useful when the corruption lies in the maker notes themselves
+func RepairDropMakerNotes() RepairOption {
+	return func(c *repairConfig) error {
+		c.dropMakerNotes = true
+		return nil
+	}
+}
+
+// repairArgs returns the arguments of a repairing command, file excluded
+func repairArgs(opts []RepairOption) ([]string, error) {
+	var c repairConfig
+	for _, opt := range opts {
+		if err := opt(&c); err != nil {
+			return nil, fmt.Errorf("error when configuring repair: %w", err)
+		}
+	}
+
+	args := []string{"-all=", "-tagsFromFile", "@", "-all:all", "-unsafe", "-icc_profile"}
+	if c.dropMakerNotes {
+		args = append(args, "--MakerNotes")
+	}
+
+	return args, nil
+}
+
+// lostTags returns the "GROUP:LABEL" keys of the tags of before missing from after,
+// sorted alphabetically. The groups describing the file itself are ignored.
+func lostTags(before, after FileMetadata) []string {
+	var lost []string
+	for n, vs := range Diff(before, after).Removed {
+		if fileGroup(n) {
+			continue
+		}
+		for _, v := range vs {
+			lost = append(lost, n+":"+v.Label)
+		}
+	}
+	sort.Strings(lost)
+	return lost
+}
+
+// Repair rebuilds the metadata of file from scratch, which fixes most corrupted
+// metadata blocks: every tag is deleted then copied back from the original file (see
+// https://exiftool.org/faq.html#Q20), maker notes and ICC profile included. Repair
+// returns the "GROUP:LABEL" keys of the tags that couldn't be rebuilt, by comparing the
+// metadata extracted before and after the rebuild. If anything went wrong, a non empty
+// error will be returned.
+// Sample :
+//   lost, err := e.Repair("corrupted.jpg", RepairDropMakerNotes())
+func (e *Exiftool) Repair(file string, opts ...RepairOption) ([]string, error) {
+	if err := e.checkFile(file); err != nil {
+		return nil, err
+	}
+
+	args, err := repairArgs(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	before := e.ExtractMetadata(file)[0]
+	if before.Err != nil {
+		return nil, fmt.Errorf("error while extracting metadata before repair: %w", before.Err)
+	}
+
+	if err := e.executeWrite(file, args); err != nil {
+		return nil, err
+	}
+
+	// the rebuild may keep the size and the modification time of the file, hence its
+	// cache key: whatever the Cache, the "before" extraction must not be returned again
+	e.invalidateCache(file)
+	after := e.Extract([]string{file}, refreshCache())[0]
+	if after.Err != nil {
+		return nil, fmt.Errorf("error while extracting metadata after repair: %w", after.Err)
+	}
+
+	return lostTags(before, after), nil
+}
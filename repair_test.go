@@ -0,0 +1,65 @@
+package exiftool
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepairArgs(t *testing.T) {
+	args, err := repairArgs(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"-all=", "-tagsFromFile", "@", "-all:all", "-unsafe", "-icc_profile"}, args)
+
+	args, err = repairArgs([]RepairOption{RepairDropMakerNotes()})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"-all=", "-tagsFromFile", "@", "-all:all", "-unsafe", "-icc_profile", "--MakerNotes"}, args)
+}
+
+func TestLostTags(t *testing.T) {
+	before := FileMetadata{Groups: map[string]FileMetadataValues{
+		"File":       {{"FileSize", "26 kB"}, {"FileModifyDate", "2019:04:04 13:18:04"}},
+		"ExifTool":   {{"Warning", "Bad MakerNotes directory"}},
+		"EXIF":       {{"Make", "samsung"}, {"Model", "SM-G930F"}},
+		"MakerNotes": {{"SerialNumber", "123"}},
+	}}
+	after := FileMetadata{Groups: map[string]FileMetadataValues{
+		"File": {{"FileSize", "25 kB"}},
+		"EXIF": {{"Make", "samsung"}, {"Model", "SM-G930F"}, {"Software", "new"}},
+	}}
+
+	assert.Equal(t, []string{"MakerNotes:SerialNumber"}, lostTags(before, after))
+	assert.Nil(t, lostTags(after, after))
+}
+
+func TestRepair(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+
+	e, err := NewExiftool(SetCache(NewLRUCache(10)))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	_, err = e.Repair("./testdata/nonExisting")
+	assert.True(t, errors.Is(err, ErrNotExist))
+
+	lost, err := e.Repair(f)
+	assert.Nil(t, err)
+	assert.Empty(t, lost)
+
+	lost, err = e.Repair(f, RepairDropMakerNotes())
+	assert.Nil(t, err)
+	for _, k := range lost {
+		assert.Contains(t, k, "MakerNotes:")
+	}
+
+	fms := e.ExtractMetadata(f)
+	assert.Equal(t, 1, len(fms))
+	assert.Nil(t, fms[0].Err)
+	mk, err := fms[0].Groups["EXIF"].GetString("Make")
+	assert.Nil(t, err)
+	assert.Equal(t, "samsung", mk)
+	_, found := fms[0].Groups["MakerNotes"]
+	assert.False(t, found)
+}
@@ -0,0 +1,112 @@
+package exiftool
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Nice runs the exiftool processes with the niceness n, from -20 (highest priority) to
+// 19 (lowest priority), so that background extractions don't starve the other
+// workloads. Negative values usually require privileges. It isn't supported on Windows.
+// Sample :
+//   e, err := NewExiftool(Nice(10))
+func Nice(n int) Option {
+	return func(e *Exiftool) error {
+		if !resourceLimitsSupported {
+			return fmt.Errorf("niceness is not supported on %v", runtime.GOOS)
+		}
+		if n < -20 || n > 19 {
+			return fmt.Errorf("invalid niceness (%v)", n)
+		}
+		e.nice = &n
+		return nil
+	}
+}
+
+// MemoryLimit limits the virtual memory of the exiftool processes to bytes (ulimit -v):
+// exiftool then fails on the files requiring more memory, and may terminate, which is
+// why it is usually combined with AutoRestart. It isn't supported on Windows.
+// Sample :
+//   e, err := NewExiftool(MemoryLimit(512<<20), AutoRestart(3, time.Second))
+func MemoryLimit(bytes int64) Option {
+	return func(e *Exiftool) error {
+		if !resourceLimitsSupported {
+			return fmt.Errorf("memory limit is not supported on %v", runtime.GOOS)
+		}
+		if bytes < 1024 {
+			return fmt.Errorf("invalid memory limit (%v)", bytes)
+		}
+		e.ulimits = append(e.ulimits, "-v", strconv.FormatInt(bytes/1024, 10))
+		return nil
+	}
+}
+
+// CPUTimeLimit limits the CPU time of the exiftool processes to d, rounded up to the
+// second (ulimit -t). The limit applies to the whole lifetime of a process: the
+// stay_open process is killed once it used d, hence it must be combined with
+// AutoRestart. It isn't supported on Windows.
+func CPUTimeLimit(d time.Duration) Option {
+	return func(e *Exiftool) error {
+		if !resourceLimitsSupported {
+			return fmt.Errorf("cpu time limit is not supported on %v", runtime.GOOS)
+		}
+		if d <= 0 {
+			return fmt.Errorf("invalid cpu time limit (%v)", d)
+		}
+		secs := int64((d + time.Second - 1) / time.Second)
+		e.ulimits = append(e.ulimits, "-t", strconv.FormatInt(secs, 10))
+		return nil
+	}
+}
+
+// LargeFileSupport enables the support of files larger than 2GB, ie. long videos
+// (activates Exiftool's '-api LargeFileSupport=1' parameter)
+func LargeFileSupport() Option {
+	return API("LargeFileSupport", "1")
+}
+
+// DefaultPoolSize returns the Pool size matching the number of goroutines that can run
+// simultaneously (GOMAXPROCS), which caps the CPU used by the exiftool processes like
+// GOMAXPROCS does for Go code
+// Sample :
+//   p, err := NewPool(DefaultPoolSize(), Nice(10))
+func DefaultPoolSize() int {
+	return runtime.GOMAXPROCS(0)
+}
+
+// limitCommand wraps cmd, running exiftool, into a shell applying the resource limits
+func (e *Exiftool) limitCommand(cmd *exec.Cmd) *exec.Cmd {
+	if len(e.ulimits) == 0 {
+		return cmd
+	}
+
+	var script []string
+	for i := 0; i < len(e.ulimits); i += 2 {
+		script = append(script, fmt.Sprintf("ulimit %v %v", e.ulimits[i], e.ulimits[i+1]))
+	}
+	script = append(script, `exec "$0" "$@"`)
+
+	args := append([]string{"-c", strings.Join(script, " && ")}, cmd.Args...)
+	return exec.Command("/bin/sh", args...)
+}
+
+// startCommand starts cmd, applying the niceness to its process
+func (e *Exiftool) startCommand(cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	if e.nice != nil {
+		if err := setPriority(cmd.Process.Pid, *e.nice); err != nil {
+			cmd.Process.Kill()
+			cmd.Wait()
+			return fmt.Errorf("error while setting niceness: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package exiftool
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceOptions(t *testing.T) {
+	five := 5
+	var tcs = []struct {
+		tcID       string
+		in         Option
+		expOk      bool
+		expNice    *int
+		expUlimits []string
+	}{
+		{"nice", Nice(5), true, &five, nil},
+		{"niceTooHigh", Nice(20), false, nil, nil},
+		{"niceTooLow", Nice(-21), false, nil, nil},
+		{"memory", MemoryLimit(1 << 30), true, nil, []string{"-v", "1048576"}},
+		{"memoryTooLow", MemoryLimit(0), false, nil, nil},
+		{"cpu", CPUTimeLimit(1500 * time.Millisecond), true, nil, []string{"-t", "2"}},
+		{"cpuZero", CPUTimeLimit(0), false, nil, nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			e := Exiftool{}
+			err := tc.in(&e)
+			assert.Equal(t, tc.expOk && resourceLimitsSupported, err == nil)
+			if err == nil {
+				assert.Equal(t, tc.expNice, e.nice)
+				assert.Equal(t, tc.expUlimits, e.ulimits)
+			}
+		})
+	}
+}
+
+func TestLimitCommand(t *testing.T) {
+	e := Exiftool{}
+	cmd := exec.Command("exiftool", "-ver")
+	assert.Equal(t, cmd, e.limitCommand(cmd))
+
+	e.ulimits = []string{"-v", "1024", "-t", "2"}
+	assert.Equal(t, []string{"/bin/sh", "-c", `ulimit -v 1024 && ulimit -t 2 && exec "$0" "$@"`, "exiftool", "-ver"}, e.limitCommand(cmd).Args)
+}
+
+func TestNewExifTool_WithResourceLimits(t *testing.T) {
+	if !resourceLimitsSupported {
+		return
+	}
+
+	e, err := NewExiftool(Nice(5), MemoryLimit(1<<30), CPUTimeLimit(time.Minute))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	fms := e.ExtractMetadata("./testdata/20190404_131804.jpg")
+	assert.Equal(t, 1, len(fms))
+	assert.Nil(t, fms[0].Err)
+}
+
+func TestLargeFileSupport(t *testing.T) {
+	e := Exiftool{}
+	assert.Nil(t, LargeFileSupport()(&e))
+	assert.Equal(t, []string{"-api", "LargeFileSupport=1"}, e.extraInitArgs)
+}
+
+func TestDefaultPoolSize(t *testing.T) {
+	assert.True(t, DefaultPoolSize() >= 1)
+}
@@ -0,0 +1,125 @@
+package exiftool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// RetryPolicy defines how the extraction of a file failing because of a transient error
+// is retried, see Retry
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts for a file, including the first one
+	MaxAttempts int
+	// Backoff returns the delay before the attempt #attempt (starting at 2), no delay
+	// being applied if it is nil
+	Backoff func(attempt int) time.Duration
+	// Retryable returns true if the extraction failing with err must be retried,
+	// IsTransient being used if it is nil
+	Retryable func(err error) bool
+}
+
+// ExponentialBackoff returns a RetryPolicy.Backoff doubling the delay at each attempt,
+// from base up to max
+func ExponentialBackoff(base, max time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 2; i < attempt && d < max; i++ {
+			d *= 2
+		}
+		if d > max {
+			d = max
+		}
+		return d
+	}
+}
+
+// IsTransient returns true if err may not occur again: the exiftool process crashed
+// (ProcessCrashedError), the extraction timed out (FileTimeoutError) or the file couldn't
+// be accessed for another reason than its absence (ie. a network file system hiccup)
+func IsTransient(err error) bool {
+	var crashed *ProcessCrashedError
+	var timeout *FileTimeoutError
+	var pathErr *os.PathError
+	switch {
+	case errors.As(err, &crashed), errors.As(err, &timeout):
+		return true
+	case errors.As(err, &pathErr):
+		return !os.IsNotExist(err)
+	default:
+		return false
+	}
+}
+
+func checkRetryPolicy(p RetryPolicy) error {
+	if p.MaxAttempts < 1 {
+		return fmt.Errorf("invalid max attempts (%v)", p.MaxAttempts)
+	}
+	return nil
+}
+
+// Retry retries the extraction of the files failing with a transient error according
+// to p. The exiftool process is restarted before retrying if it terminated.
+// Sample :
+//   e, err := NewExiftool(Retry(RetryPolicy{MaxAttempts: 3, Backoff: ExponentialBackoff(100*time.Millisecond, time.Second)}))
+func Retry(p RetryPolicy) Option {
+	return func(e *Exiftool) error {
+		if err := checkRetryPolicy(p); err != nil {
+			return err
+		}
+		e.retry = &p
+		return nil
+	}
+}
+
+// WithRetry overrides the policy set with Retry for a single extraction
+// Sample :
+//   fms := e.Extract(files, WithRetry(RetryPolicy{MaxAttempts: 5}))
+func WithRetry(p RetryPolicy) ExtractOption {
+	return func(c *extractConfig) error {
+		if err := checkRetryPolicy(p); err != nil {
+			return err
+		}
+		c.retry = &p
+		return nil
+	}
+}
+
+// extractFileRetry extracts f configured by cfg, retrying according to its policy. The
+// caller must hold e.lock.
+func (e *Exiftool) extractFileRetry(ctx context.Context, cfg extractConfig, f string) FileMetadata {
+	fm := e.extractFile(ctx, cfg, f)
+	if cfg.retry == nil {
+		return fm
+	}
+
+	retryable := cfg.retry.Retryable
+	if retryable == nil {
+		retryable = IsTransient
+	}
+
+	for attempt := 2; attempt <= cfg.retry.MaxAttempts && fm.Err != nil && retryable(fm.Err); attempt++ {
+		e.log("retry", "file", f, "attempt", attempt, "err", fm.Err)
+
+		if cfg.retry.Backoff != nil {
+			select {
+			case <-time.After(cfg.retry.Backoff(attempt)):
+			case <-ctx.Done():
+				return fm
+			}
+		}
+
+		if !e.alive() && !e.stopping() {
+			if err := e.respawn(1); err != nil {
+				fm.Err = err
+				return fm
+			}
+		}
+
+		fm = e.extractFile(ctx, cfg, f)
+	}
+
+	return fm
+}
@@ -0,0 +1,93 @@
+package exiftool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExponentialBackoff(t *testing.T) {
+	b := ExponentialBackoff(100*time.Millisecond, time.Second)
+	for attempt, exp := range map[int]time.Duration{
+		2:  100 * time.Millisecond,
+		3:  200 * time.Millisecond,
+		4:  400 * time.Millisecond,
+		5:  800 * time.Millisecond,
+		6:  time.Second,
+		20: time.Second,
+	} {
+		assert.Equal(t, exp, b(attempt), "attempt %v", attempt)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	var tcs = []struct {
+		tcID  string
+		in    error
+		expOk bool
+	}{
+		{"crashed", &ProcessCrashedError{}, true},
+		{"wrappedTimeout", fmt.Errorf("w: %w", &FileTimeoutError{}), true},
+		{"pathErr", &os.PathError{Op: "stat", Path: "a", Err: errors.New("stale NFS file handle")}, true},
+		{"notFound", &FileNotFoundError{File: "a"}, false},
+		{"notExist", &os.PathError{Op: "stat", Path: "a", Err: os.ErrNotExist}, false},
+		{"canceled", context.Canceled, false},
+		{"closed", ErrClosed, false},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			assert.Equal(t, tc.expOk, IsTransient(tc.in))
+		})
+	}
+}
+
+func TestRetryOptions(t *testing.T) {
+	e := Exiftool{}
+	assert.NotNil(t, Retry(RetryPolicy{})(&e))
+	assert.Nil(t, Retry(RetryPolicy{MaxAttempts: 2})(&e))
+	assert.Equal(t, 2, e.retry.MaxAttempts)
+	assert.Equal(t, 2, e.extractConfig().retry.MaxAttempts)
+
+	c := extractConfig{}
+	assert.NotNil(t, WithRetry(RetryPolicy{MaxAttempts: -1})(&c))
+	assert.Nil(t, WithRetry(RetryPolicy{MaxAttempts: 3})(&c))
+	assert.Equal(t, 3, c.retry.MaxAttempts)
+}
+
+func TestExtractWithRetry(t *testing.T) {
+	var retried, delayed int
+	e, err := NewExiftool(Retry(RetryPolicy{
+		MaxAttempts: 3,
+		Backoff:     func(int) time.Duration { delayed++; return time.Millisecond },
+		Retryable:   func(err error) bool { retried++; return IsTransient(err) },
+	}))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	fms := e.ExtractMetadata("./testdata/nonExisting")
+	assert.True(t, errors.Is(fms[0].Err, ErrNotExist))
+	assert.Equal(t, 1, retried)
+	assert.Equal(t, 0, delayed)
+
+	// the process is restarted before retrying
+	retried = 0
+	assert.Nil(t, e.cmd.Process.Kill())
+	<-e.exited
+	fms = e.ExtractMetadata("./testdata/20190404_131804.jpg")
+	assert.Nil(t, fms[0].Err)
+	assert.Equal(t, 1, retried)
+	assert.Equal(t, 1, delayed)
+
+	assert.Nil(t, e.cmd.Process.Kill())
+	<-e.exited
+	fms = e.Extract([]string{"./testdata/20190404_131804.jpg"}, WithRetry(RetryPolicy{MaxAttempts: 1}))
+	var crashed *ProcessCrashedError
+	assert.True(t, errors.As(fms[0].Err, &crashed))
+}
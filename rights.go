@@ -0,0 +1,113 @@
+package exiftool
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rights gathers the credit and rights management information of a file, as defined by
+// the IPTC Core schema. Fields are zero when the corresponding tags can't be found.
+type Rights struct {
+	Creators        []string
+	CopyrightNotice string
+	UsageTerms      string
+	CreditLine      string
+	LicensorURL     string
+}
+
+// rightsTag lists the keys a rights field is read from, XMP first since it isn't
+// limited in length nor charset, and the paired tags it is written to
+type rightsTag struct {
+	keys   []string
+	writes []string
+}
+
+var (
+	rightsCreatorTag   = rightsTag{[]string{"XMP-dc:Creator", "XMP:Creator", "IPTC:By-line"}, []string{"XMP-dc:Creator", "IPTC:By-line"}}
+	rightsCopyrightTag = rightsTag{[]string{"XMP-dc:Rights", "XMP:Rights", "IPTC:CopyrightNotice"}, []string{"XMP-dc:Rights", "IPTC:CopyrightNotice"}}
+	rightsUsageTag     = rightsTag{[]string{"XMP-xmpRights:UsageTerms", "XMP:UsageTerms"}, []string{"XMP-xmpRights:UsageTerms"}}
+	rightsCreditTag    = rightsTag{[]string{"XMP-photoshop:Credit", "XMP:Credit", "IPTC:Credit"}, []string{"XMP-photoshop:Credit", "IPTC:Credit"}}
+	rightsLicensorTag  = rightsTag{[]string{"XMP-plus:LicensorURL", "XMP:LicensorURL"}, []string{"XMP-plus:LicensorURL"}}
+)
+
+// Rights returns the credit and rights management information of the file, from the
+// XMP tags or their IPTC IIM equivalents. ErrKeyNotFound will be returned if none of
+// them can be found.
+func (fm FileMetadata) Rights() (Rights, error) {
+	var r Rights
+	found := false
+
+	if s, ok := fm.lookupStrings(rightsCreatorTag.keys...); ok {
+		r.Creators, found = s, true
+	}
+
+	for _, i := range []struct {
+		tag rightsTag
+		dst *string
+	}{
+		{rightsCopyrightTag, &r.CopyrightNotice},
+		{rightsUsageTag, &r.UsageTerms},
+		{rightsCreditTag, &r.CreditLine},
+		{rightsLicensorTag, &r.LicensorURL},
+	} {
+		if s, ok := fm.lookupString(i.tag.keys...); ok {
+			*i.dst, found = strings.TrimSpace(s), true
+		}
+	}
+
+	if !found {
+		return Rights{}, ErrKeyNotFound
+	}
+
+	return r, nil
+}
+
+// rightsValues returns the values writing r into the paired IPTC and XMP tags, zero
+// fields being ignored
+func rightsValues(r Rights) (FileMetadataValues, error) {
+	var values FileMetadataValues
+
+	if len(r.Creators) > 0 {
+		for _, t := range rightsCreatorTag.writes {
+			values = append(values, FileMetadataValue{Label: t, Value: r.Creators})
+		}
+	}
+
+	for _, i := range []struct {
+		tag rightsTag
+		v   string
+	}{
+		{rightsCopyrightTag, r.CopyrightNotice},
+		{rightsUsageTag, r.UsageTerms},
+		{rightsCreditTag, r.CreditLine},
+		{rightsLicensorTag, r.LicensorURL},
+	} {
+		if i.v == "" {
+			continue
+		}
+		for _, t := range i.tag.writes {
+			values = append(values, FileMetadataValue{Label: t, Value: i.v})
+		}
+	}
+
+	if len(values) == 0 {
+		return nil, fmt.Errorf("no rights to write")
+	}
+
+	return values, nil
+}
+
+// SetRights writes the non zero fields of r into file, each field being written into
+// both its XMP tag and its IPTC IIM equivalent (when there is one) to keep the two
+// schemas in sync. The previous creators are replaced. If anything went wrong, a non
+// empty error will be returned.
+// Sample :
+//   err := e.SetRights("photo.jpg", Rights{Creators: []string{"me"}, CopyrightNotice: "© 2020 me"})
+func (e *Exiftool) SetRights(file string, r Rights) error {
+	values, err := rightsValues(r)
+	if err != nil {
+		return err
+	}
+
+	return e.Write(file, values)
+}
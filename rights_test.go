@@ -0,0 +1,96 @@
+package exiftool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRights(t *testing.T) {
+	var tcs = []struct {
+		tcID  string
+		in    map[string]FileMetadataValues
+		expOk bool
+		expR  Rights
+	}{
+		{"xmp", map[string]FileMetadataValues{"XMP": {
+			{"Creator", []interface{}{"a", "b"}},
+			{"Rights", "© a"},
+			{"UsageTerms", "no reuse"},
+			{"Credit", "agency"},
+			{"LicensorURL", "https://example.com"},
+		}}, true, Rights{
+			Creators:        []string{"a", "b"},
+			CopyrightNotice: "© a",
+			UsageTerms:      "no reuse",
+			CreditLine:      "agency",
+			LicensorURL:     "https://example.com",
+		}},
+		{"iptc", map[string]FileMetadataValues{"IPTC": {
+			{"By-line", "a"},
+			{"CopyrightNotice", "© a "},
+			{"Credit", "agency"},
+		}}, true, Rights{Creators: []string{"a"}, CopyrightNotice: "© a", CreditLine: "agency"}},
+		{"xmpFirst", map[string]FileMetadataValues{
+			"IPTC": {{"CopyrightNotice", "© iptc"}},
+			"XMP":  {{"Rights", "© xmp"}},
+		}, true, Rights{CopyrightNotice: "© xmp"}},
+		{"none", map[string]FileMetadataValues{"EXIF": {{"Make", "samsung"}}}, false, Rights{}},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			r, err := FileMetadata{Groups: tc.in}.Rights()
+			assert.Equal(t, tc.expOk, err == nil)
+			if !tc.expOk {
+				assert.Equal(t, ErrKeyNotFound, err)
+			}
+			assert.Equal(t, tc.expR, r)
+		})
+	}
+}
+
+func TestRightsValues(t *testing.T) {
+	values, err := rightsValues(Rights{Creators: []string{"a", "b"}, CopyrightNotice: "© a", LicensorURL: "https://example.com"})
+	assert.Nil(t, err)
+	assert.Equal(t, FileMetadataValues{
+		{"XMP-dc:Creator", []string{"a", "b"}},
+		{"IPTC:By-line", []string{"a", "b"}},
+		{"XMP-dc:Rights", "© a"},
+		{"IPTC:CopyrightNotice", "© a"},
+		{"XMP-plus:LicensorURL", "https://example.com"},
+	}, values)
+
+	_, err = rightsValues(Rights{})
+	assert.NotNil(t, err)
+}
+
+func TestSetRights(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	assert.NotNil(t, e.SetRights(f, Rights{}))
+	exp := Rights{
+		Creators:        []string{"a", "b"},
+		CopyrightNotice: "(c) a",
+		UsageTerms:      "no reuse",
+		CreditLine:      "agency",
+		LicensorURL:     "https://example.com",
+	}
+	assert.Nil(t, e.SetRights(f, exp))
+
+	fms := e.ExtractMetadata(f)
+	assert.Equal(t, 1, len(fms))
+	assert.Nil(t, fms[0].Err)
+	r, err := fms[0].Rights()
+	assert.Nil(t, err)
+	assert.Equal(t, exp, r)
+	copyright, err := fms[0].Groups["IPTC"].GetString("CopyrightNotice")
+	assert.Nil(t, err)
+	assert.Equal(t, "(c) a", copyright)
+}
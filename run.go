@@ -0,0 +1,148 @@
+package exiftool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// stderrPrefixes are the prefixes of the messages printed by exiftool on stderr
+var stderrPrefixes = []string{"Warning:", "Error:"}
+
+// checkRunArgs returns an error if one of args would break the stay_open protocol
+func checkRunArgs(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no argument")
+	}
+	for _, a := range args {
+		if strings.ContainsAny(a, "\r\n") {
+			return fmt.Errorf("line breaks are not supported (%q)", a)
+		}
+		l := strings.ToLower(a)
+		if l == "-stay_open" || l == "-common_args" || strings.HasPrefix(l, "-execute") {
+			return fmt.Errorf("unsupported argument (%v)", a)
+		}
+	}
+	return nil
+}
+
+// splitStderr splits the merged output of a command into what exiftool printed on
+// stdout and on stderr, the latter being recognized by the prefixes of the exiftool
+// messages
+func splitStderr(out []byte) ([]byte, []byte) {
+	var stdout, stderr []byte
+	for _, l := range strings.SplitAfter(string(out), "\n") {
+		isErr := false
+		for _, p := range stderrPrefixes {
+			if strings.HasPrefix(l, p) {
+				isErr = true
+			}
+		}
+		if isErr {
+			stderr = append(stderr, l...)
+		} else {
+			stdout = append(stdout, l...)
+		}
+	}
+	return stdout, stderr
+}
+
+// Run sends args to the exiftool process as a single command and returns its output,
+// which is an escape hatch for the exiftool features that aren't modeled by the library.
+// The arguments set by the options of the Exiftool are applied too. As the stay_open
+// process merges stdout and stderr, the lines printed on stderr are recognized by their
+// "Warning:" and "Error:" prefixes. err is only set if the command couldn't be run,
+// exiftool errors being reported in stderr.
+// Sample :
+//   stdout, stderr, err := e.Run("-listx", "-EXIF:All")
+func (e *Exiftool) Run(args ...string) ([]byte, []byte, error) {
+	if err := checkRunArgs(args); err != nil {
+		return nil, nil, err
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	out, err := e.execute(args...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stdout, stderr := splitStderr(out)
+	return stdout, stderr, nil
+}
+
+// RunJSON behaves like Run, the output being printed as JSON ('-j -g' parameters, see
+// GroupFamily) and decoded into a FileMetadata per file. The messages printed on stderr
+// are reported as warnings when a single file is extracted. If anything went wrong, a
+// non empty error will be returned.
+// Sample :
+//   fms, err := e.RunJSON("-ext", "jpg", "-r", "photos")
+func (e *Exiftool) RunJSON(args ...string) ([]FileMetadata, error) {
+	if err := checkRunArgs(args); err != nil {
+		return nil, err
+	}
+
+	args = append(e.extractArgs(e.extractConfig()), args...)
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	out, err := e.execute(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.decodeAll(out)
+}
+
+// decodeAll decodes the merged output of a command printing several files as JSON
+func (e *Exiftool) decodeAll(out []byte) ([]FileMetadata, error) {
+	js, messages := splitMessages(out)
+	if len(js) == 0 || js[0] != '[' {
+		return nil, fmt.Errorf("no metadata in output (%v)", strings.TrimSpace(string(out)))
+	}
+
+	var raws []json.RawMessage
+	if err := json.Unmarshal(js, &raws); err != nil {
+		return nil, fmt.Errorf("error during unmarshaling (%v): %w", string(js), err)
+	}
+	if len(raws) != 1 {
+		messages = nil
+	}
+
+	fms := make([]FileMetadata, len(raws))
+	for i, raw := range raws {
+		var sf struct {
+			SourceFile string
+		}
+		json.Unmarshal(raw, &sf)
+		fms[i].File = sf.SourceFile
+		e.decodeRaw(&fms[i], raw, messages)
+	}
+
+	return fms, nil
+}
+
+// Run behaves like Exiftool.Run, on the first available worker
+func (p *Pool) Run(args ...string) ([]byte, []byte, error) {
+	e, err := p.acquire(context.Background())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer p.release(e)
+
+	return e.Run(args...)
+}
+
+// RunJSON behaves like Exiftool.RunJSON, on the first available worker
+func (p *Pool) RunJSON(args ...string) ([]FileMetadata, error) {
+	e, err := p.acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer p.release(e)
+
+	return e.RunJSON(args...)
+}
@@ -0,0 +1,100 @@
+package exiftool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckRunArgs(t *testing.T) {
+	var tcs = []struct {
+		tcID  string
+		in    []string
+		expOk bool
+	}{
+		{"ok", []string{"-ver"}, true},
+		{"none", nil, false},
+		{"lineBreak", []string{"-Artist=a\nb"}, false},
+		{"execute", []string{"-execute42"}, false},
+		{"stayOpen", []string{"-stay_open", "False"}, false},
+		{"commonArgs", []string{"-Common_Args"}, false},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			assert.Equal(t, tc.expOk, checkRunArgs(tc.in) == nil)
+		})
+	}
+}
+
+func TestSplitStderr(t *testing.T) {
+	out := "Warning: [minor] Bad MakerNotes - a.jpg\n    1 image files updated\nError: File not found - b.jpg\n12.40"
+	stdout, stderr := splitStderr([]byte(out))
+	assert.Equal(t, "    1 image files updated\n12.40", string(stdout))
+	assert.Equal(t, "Warning: [minor] Bad MakerNotes - a.jpg\nError: File not found - b.jpg\n", string(stderr))
+}
+
+func TestDecodeAll(t *testing.T) {
+	e := Exiftool{}
+
+	fms, err := e.decodeAll([]byte("Warning: w\n[{\"SourceFile\":\"a.jpg\",\"EXIF\":{\"Make\":\"samsung\"}}]"))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(fms))
+	assert.Equal(t, "a.jpg", fms[0].File)
+	assert.Equal(t, []string{"w"}, fms[0].Warnings)
+	mk, err := fms[0].Groups["EXIF"].GetString("Make")
+	assert.Nil(t, err)
+	assert.Equal(t, "samsung", mk)
+
+	fms, err = e.decodeAll([]byte("Warning: w\n[{\"SourceFile\":\"a.jpg\"},{\"SourceFile\":\"b.jpg\"}]"))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(fms))
+	assert.Equal(t, "b.jpg", fms[1].File)
+	assert.Empty(t, fms[1].Warnings)
+
+	_, err = e.decodeAll([]byte("Error: File not found - a.jpg\n"))
+	assert.NotNil(t, err)
+	_, err = e.decodeAll([]byte("[{\"SourceFile\""))
+	assert.NotNil(t, err)
+}
+
+func TestRun(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	stdout, stderr, err := e.Run("-ver")
+	assert.Nil(t, err)
+	assert.Regexp(t, `^\d+\.\d+\s*$`, string(stdout))
+	assert.Empty(t, stderr)
+
+	_, _, err = e.Run("-execute")
+	assert.NotNil(t, err)
+
+	fms, err := e.RunJSON("./testdata/20190404_131804.jpg", "./testdata/empty.jpg")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(fms))
+	assert.Equal(t, "./testdata/20190404_131804.jpg", fms[0].File)
+	assert.Equal(t, "./testdata/empty.jpg", fms[1].File)
+	_, found := fms[0].Groups["File"]
+	assert.True(t, found)
+
+	_, err = e.RunJSON()
+	assert.NotNil(t, err)
+}
+
+func TestPoolRun(t *testing.T) {
+	p, err := NewPool(2)
+	assert.Nil(t, err)
+	defer p.Close()
+
+	stdout, _, err := p.Run("-ver")
+	assert.Nil(t, err)
+	assert.NotEmpty(t, stdout)
+
+	fms, err := p.RunJSON("./testdata/20190404_131804.jpg")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(fms))
+	assert.Nil(t, fms[0].Err)
+}
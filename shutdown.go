@@ -0,0 +1,158 @@
+package exiftool
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Shutdown closes exiftool gracefully: the new commands are refused with ErrClosed (the
+// files of a running extraction that have not been sent yet included), the running
+// command is waited for, then the exiftool process is asked to terminate and waited for
+// too, so that no output is truncated. If ctx is done before, the exiftool process is
+// killed and ctx.Err() is returned.
+// Sample :
+//   ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//   defer cancel()
+//   err := e.Shutdown(ctx)
+func (e *Exiftool) Shutdown(ctx context.Context) error {
+	atomic.StoreInt32(&e.draining, 1)
+
+	locked := make(chan struct{})
+	go func() {
+		e.lock.Lock()
+		close(locked)
+	}()
+
+	var err error
+	select {
+	case <-locked:
+	case <-ctx.Done():
+		// interrupting the running command
+		err = ctx.Err()
+		e.kill()
+		<-locked
+	}
+	defer e.lock.Unlock()
+
+	if e.closed {
+		return err
+	}
+	e.closed = true
+
+	if err == nil && e.alive() {
+		err = e.terminate(ctx)
+	}
+
+	// the process terminated (its stdin being closed on exit): closing stdMergedOut can't
+	// truncate anything anymore
+	e.stdMergedOut.Close()
+
+	return err
+}
+
+// terminate asks the exiftool process to terminate and waits for it, killing it if ctx
+// is done before. The caller must hold e.lock.
+func (e *Exiftool) terminate(ctx context.Context) error {
+	// exiftool must be able to print what it has to until it terminates
+	go io.Copy(ioutil.Discard, e.stdMergedOut)
+
+	for _, v := range closeArgs {
+		if _, err := fmt.Fprintln(e.stdin, v); err != nil {
+			e.kill()
+			return fmt.Errorf("error while writing to stdin: %w", err)
+		}
+	}
+
+	select {
+	case <-e.exited:
+		return nil
+	case <-ctx.Done():
+		e.kill()
+		return ctx.Err()
+	}
+}
+
+// kill kills the running exiftool process and waits for it to terminate
+func (e *Exiftool) kill() {
+	p, ok := e.process.Load().(process)
+	if !ok {
+		return
+	}
+	p.cmd.Process.Kill()
+	<-p.exited
+}
+
+// process is a started exiftool process
+type process struct {
+	cmd       *exec.Cmd
+	exited    chan struct{}
+	startedAt time.Time
+}
+
+// stopping returns true once Close or Shutdown has been called, the commands being
+// refused from then. The caller must hold e.lock.
+func (e *Exiftool) stopping() bool {
+	return e.closed || atomic.LoadInt32(&e.draining) == 1
+}
+
+// Shutdown closes the pool gracefully: the new extractions are refused with
+// ErrPoolClosed and every worker is shut down (see Exiftool.Shutdown) once its running
+// extraction completed. If ctx is done before, the workers still running are killed as
+// soon as they are released and ctx.Err() is returned.
+// Sample :
+//   err := p.Shutdown(ctx)
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.lock.Lock()
+	if p.closed {
+		p.lock.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.done)
+	p.lock.Unlock()
+
+	errs := make([]error, p.size)
+	var wg sync.WaitGroup
+	wg.Add(p.size)
+	for i := 0; i < p.size; i++ {
+		go func(i int) {
+			defer wg.Done()
+			e := <-p.workers
+			if err := e.Shutdown(ctx); err != nil && err != ctx.Err() {
+				errs[i] = fmt.Errorf("error while shutting down worker #%v: %w", i, err)
+			}
+		}(i)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	var failed []error
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("error while shutting down pool: %v", failed)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
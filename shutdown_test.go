@@ -0,0 +1,107 @@
+package exiftool
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShutdown(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+
+	fms := e.ExtractMetadata("./testdata/20190404_131804.jpg")
+	assert.Nil(t, fms[0].Err)
+
+	assert.Nil(t, e.Shutdown(context.Background()))
+	assert.False(t, e.alive())
+	assert.False(t, e.Healthy())
+	assert.Equal(t, ErrClosed, e.ExtractMetadata("./testdata/20190404_131804.jpg")[0].Err)
+	assert.Nil(t, e.Shutdown(context.Background()))
+}
+
+func TestShutdownDrains(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+
+	files := make([]string, 50)
+	for i := range files {
+		files[i] = "./testdata/20190404_131804.jpg"
+	}
+
+	var wg sync.WaitGroup
+	var fms []FileMetadata
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		fms = e.ExtractMetadata(files...)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	assert.Nil(t, e.Shutdown(context.Background()))
+	wg.Wait()
+
+	// the files are either extracted or refused, never truncated
+	for _, fm := range fms {
+		if fm.Err != nil {
+			assert.Equal(t, ErrClosed, fm.Err)
+		}
+	}
+}
+
+func TestShutdownDeadline(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+
+	// simulating a running command
+	e.lock.Lock()
+	released := make(chan struct{})
+	go func() {
+		defer close(released)
+		time.Sleep(20 * time.Millisecond)
+		e.lock.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, e.Shutdown(ctx))
+	<-released
+	assert.False(t, e.alive())
+	assert.Equal(t, ErrClosed, e.ExtractMetadata("./testdata/20190404_131804.jpg")[0].Err)
+}
+
+func TestPoolShutdown(t *testing.T) {
+	p, err := NewPool(2)
+	assert.Nil(t, err)
+
+	fms := p.ExtractMetadata("./testdata/20190404_131804.jpg")
+	assert.Nil(t, fms[0].Err)
+
+	assert.Nil(t, p.Shutdown(context.Background()))
+	assert.Equal(t, ErrPoolClosed, p.ExtractMetadata("./testdata/20190404_131804.jpg")[0].Err)
+	assert.Nil(t, p.Shutdown(context.Background()))
+}
+
+func TestPoolShutdownDeadline(t *testing.T) {
+	p, err := NewPool(2)
+	assert.Nil(t, err)
+
+	// simulating a running extraction
+	e, err := p.acquire(context.Background())
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, p.Shutdown(ctx))
+	assert.True(t, e.alive())
+
+	// the worker is killed once released
+	p.release(e)
+	for i := 0; i < 100 && e.alive(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.False(t, e.alive())
+}
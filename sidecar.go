@@ -0,0 +1,50 @@
+package exiftool
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SidecarPath returns the path of the XMP sidecar of file, which is file with its
+// extension replaced by ".xmp" (exiftool's %d%f.xmp)
+func SidecarPath(file string) string {
+	return strings.TrimSuffix(file, filepath.Ext(file)) + ".xmp"
+}
+
+// WriteSidecar creates the XMP sidecar of file (see SidecarPath) from the metadata of
+// file (activates Exiftool's '-o' parameter), or updates it if it already exists
+// ('-tagsFromFile' parameter). If anything went wrong, a non empty error will be
+// returned.
+// Sample :
+//   err := e.WriteSidecar("photo.cr2")
+func (e *Exiftool) WriteSidecar(file string) error {
+	if err := e.checkFile(file); err != nil {
+		return err
+	}
+
+	sidecar := SidecarPath(file)
+	_, err := e.fileSystem().Stat(sidecar)
+	create := os.IsNotExist(err)
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	var out []byte
+	if create {
+		out, err = e.executeWriting("-o", sidecar, "-tagsFromFile", "@", "-all", file)
+	} else {
+		out, err = e.executeWriteFiles([]string{"-tagsFromFile", file, "-all"}, sidecar)
+	}
+	if err != nil {
+		return err
+	}
+
+	return checkWriteOutput(sidecar, out)
+}
+
+// ReadSidecar extracts metadata from the XMP sidecar of file (see SidecarPath).
+// FileMetadata.Err will be a FileNotFoundError if file has no sidecar.
+func (e *Exiftool) ReadSidecar(file string) FileMetadata {
+	return e.ExtractMetadata(SidecarPath(file))[0]
+}
@@ -0,0 +1,58 @@
+package exiftool
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSidecarPath(t *testing.T) {
+	var tcs = []struct {
+		tcID   string
+		in     string
+		expOut string
+	}{
+		{"raw", "/photos/IMG_1.CR2", "/photos/IMG_1.xmp"},
+		{"relative", "a.b.jpg", "a.b.xmp"},
+		{"noExt", "photo", "photo.xmp"},
+		{"xmp", "photo.xmp", "photo.xmp"},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			assert.Equal(t, tc.expOut, SidecarPath(tc.in))
+		})
+	}
+}
+
+func TestWriteAndReadSidecar(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	fm := e.ReadSidecar(f)
+	assert.True(t, errors.Is(fm.Err, ErrNotExist))
+
+	assert.Nil(t, e.Write(f, FileMetadataValues{{"XMP:Title", "first"}}))
+	assert.Nil(t, e.WriteSidecar(f))
+	fm = e.ReadSidecar(f)
+	assert.Nil(t, fm.Err)
+	title, err := fm.Groups["XMP"].GetString("Title")
+	assert.Nil(t, err)
+	assert.Equal(t, "first", title)
+
+	assert.Nil(t, e.Write(f, FileMetadataValues{{"XMP:Title", "second"}}))
+	assert.Nil(t, e.WriteSidecar(f))
+	fm = e.ReadSidecar(f)
+	assert.Nil(t, fm.Err)
+	title, err = fm.Groups["XMP"].GetString("Title")
+	assert.Nil(t, err)
+	assert.Equal(t, "second", title)
+
+	assert.True(t, errors.Is(e.WriteSidecar("./testdata/nonExisting"), ErrNotExist))
+}
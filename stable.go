@@ -0,0 +1,125 @@
+package exiftool
+
+import "fmt"
+
+// volatileTags are the tags depending on the machine, on the location of the file or on
+// its copy rather than on its content
+var volatileTags = []string{
+	"Directory",
+	"FileAccessDate",
+	"FileModifyDate",
+	"FileInodeChangeDate",
+	"FileCreateDate",
+	"FilePermissions",
+	"ExifToolVersion",
+}
+
+// fileSystemTags are the tags of the File:System group, describing the file on the file
+// system rather than its content
+var fileSystemTags = []string{
+	"FileName",
+	"Directory",
+	"FileSize",
+	"FileModifyDate",
+	"FileAccessDate",
+	"FileInodeChangeDate",
+	"FileCreateDate",
+	"FilePermissions",
+	"FileAttributes",
+	"FileDeviceID",
+	"FileDeviceNumber",
+	"FileInodeNumber",
+	"FileHardLinks",
+	"FileUserID",
+	"FileGroupID",
+	"FileBlockSize",
+	"FileBlockCount",
+}
+
+// SortTags sorts the tags of each group alphabetically (activates Exiftool's '-sort'
+// parameter) instead of in the order they are stored in the file
+// Sample :
+//   e, err := NewExiftool(SortTags())
+func SortTags() Option {
+	return func(e *Exiftool) error {
+		e.extraInitArgs = append(e.extraInitArgs, "-sort")
+		return nil
+	}
+}
+
+// StripTags removes the tags from the extracted metadata, each tag being either
+// "GROUP:LABEL" or "LABEL" (to remove the tag from every group), GROUP being a key of
+// FileMetadata.Groups or one of its components. The groups left empty are removed.
+// Sample :
+//   e, err := NewExiftool(StripTags("File:FileName", "ExifToolVersion"))
+func StripTags(tags ...string) Option {
+	return func(e *Exiftool) error {
+		if len(tags) == 0 {
+			return fmt.Errorf("no tag to strip")
+		}
+		for _, t := range tags {
+			m, err := newTagMatcher(t)
+			if err != nil {
+				return err
+			}
+			e.stripped = append(e.stripped, m)
+		}
+		return nil
+	}
+}
+
+// StableOutput makes the extracted metadata identical across machines and copies of the
+// files, for golden-file tests: the tags are sorted (see SortTags) and the volatile tags
+// are stripped (see StripTags): Directory, FileAccessDate, FileModifyDate,
+// FileInodeChangeDate, FileCreateDate, FilePermissions and ExifToolVersion.
+// Sample :
+//   e, err := NewExiftool(StableOutput())
+func StableOutput() Option {
+	return func(e *Exiftool) error {
+		if err := SortTags()(e); err != nil {
+			return err
+		}
+		return StripTags(volatileTags...)(e)
+	}
+}
+
+// IgnoreFileSystemTags removes the tags of the File:System group (FileName, Directory,
+// FileSize, FileModifyDate, FilePermissions, ...) from the extracted metadata, only the
+// metadata describing the content of the files being kept (ie. for content based
+// deduplication). The other tags of the File group (ie. FileType, MIMEType) are kept.
+// Sample :
+//   e, err := NewExiftool(IgnoreFileSystemTags())
+func IgnoreFileSystemTags() Option {
+	tags := make([]string, 0, 2*len(fileSystemTags))
+	for _, t := range fileSystemTags {
+		tags = append(tags, "File:"+t, "System:"+t)
+	}
+	return StripTags(tags...)
+}
+
+// stripTags removes the stripped tags from fm
+func (e *Exiftool) stripTags(fm *FileMetadata) {
+	for n, g := range fm.Groups {
+		kept := make(FileMetadataValues, 0, len(g))
+		for _, v := range g {
+			if !e.isStripped(n, v.Label) {
+				kept = append(kept, v)
+			}
+		}
+		if len(kept) == 0 {
+			delete(fm.Groups, n)
+			continue
+		}
+		fm.Groups[n] = kept
+	}
+}
+
+// isStripped returns true if the tag label of the group g is stripped
+func (e *Exiftool) isStripped(g, label string) bool {
+	for _, m := range e.stripped {
+		if m.matches(g, label) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package exiftool
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortTags(t *testing.T) {
+	e := Exiftool{}
+	assert.Nil(t, SortTags()(&e))
+	assert.Equal(t, []string{"-sort"}, e.extraInitArgs)
+}
+
+func TestStripTagsOption(t *testing.T) {
+	e := Exiftool{}
+	assert.Nil(t, StripTags("File:FileName", "ExifToolVersion")(&e))
+	assert.Equal(t, []tagMatcher{{"File", "FileName"}, {"", "ExifToolVersion"}}, e.stripped)
+
+	assert.NotNil(t, StripTags()(&e))
+	assert.NotNil(t, StripTags("File:")(&e))
+}
+
+func TestStableOutputOption(t *testing.T) {
+	e := Exiftool{}
+	assert.Nil(t, StableOutput()(&e))
+	assert.Equal(t, []string{"-sort"}, e.extraInitArgs)
+	assert.Len(t, e.stripped, len(volatileTags))
+}
+
+func TestStripTags(t *testing.T) {
+	var tcs = []struct {
+		tcID   string
+		inOpt  Option
+		inRaw  string
+		expGrp map[string]FileMetadataValues
+	}{
+		{"volatile", StableOutput(), `{"SourceFile": "a.jpg",
+			"ExifTool": {"ExifToolVersion": 12.4},
+			"File": {"FileName": "a.jpg", "Directory": "/tmp", "FileAccessDate": "2020:01:01 00:00:00+01:00"},
+			"EXIF": {"Make": "Canon"}}`, map[string]FileMetadataValues{
+			"File": {{"FileName", "a.jpg"}},
+			"EXIF": {{"Make", "Canon"}},
+		}},
+		{"component", StripTags("System:Directory"), `{"SourceFile": "a.jpg",
+			"File:System": {"FileName": "a.jpg", "Directory": "/tmp"},
+			"EXIF:IFD0": {"Directory": "x"}}`, map[string]FileMetadataValues{
+			"File:System": {{"FileName", "a.jpg"}},
+			"EXIF:IFD0":   {{"Directory", "x"}},
+		}},
+		{"fileSystem", IgnoreFileSystemTags(), `{"SourceFile": "a.jpg",
+			"File": {"FileName": "a.jpg", "FileSize": 1024, "FilePermissions": "-rw-r--r--", "FileType": "JPEG"},
+			"EXIF": {"Make": "Canon"}}`, map[string]FileMetadataValues{
+			"File": {{"FileType", "JPEG"}},
+			"EXIF": {{"Make", "Canon"}},
+		}},
+		{"fileSystemFamilies", IgnoreFileSystemTags(), `{"SourceFile": "a.jpg",
+			"File:System": {"FileName": "a.jpg", "FileSize": 1024},
+			"File:File": {"FileType": "JPEG"},
+			"System": {"FileModifyDate": "2020:01:01 00:00:00+01:00"}}`, map[string]FileMetadataValues{
+			"File:File": {{"FileType", "JPEG"}},
+		}},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			e := Exiftool{}
+			assert.Nil(t, tc.inOpt(&e))
+			fm := FileMetadata{File: "a.jpg"}
+			e.decodeRaw(&fm, json.RawMessage(tc.inRaw), nil)
+			assert.Nil(t, fm.Err)
+			assert.Equal(t, tc.expGrp, fm.Groups)
+		})
+	}
+}
+
+func TestStableOutput(t *testing.T) {
+	f1, clean1 := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean1()
+	f2, clean2 := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean2()
+
+	e, err := NewExiftool(StableOutput())
+	assert.Nil(t, err)
+	defer e.Close()
+
+	fms := e.ExtractMetadata(f1, f2)
+	assert.Nil(t, fms[0].Err)
+	assert.Nil(t, fms[1].Err)
+	assert.NotEmpty(t, fms[0].Groups)
+	assert.Equal(t, fms[0].Groups, fms[1].Groups)
+	_, _, found := fms[0].lookup("FileAccessDate")
+	assert.False(t, found)
+}
+
+func TestIgnoreFileSystemTags(t *testing.T) {
+	e, err := NewExiftool(IgnoreFileSystemTags())
+	assert.Nil(t, err)
+	defer e.Close()
+
+	fms := e.ExtractMetadata("./testdata/20190404_131804.jpg")
+	assert.Nil(t, fms[0].Err)
+	for _, k := range []string{"FileName", "Directory", "FileSize", "FileModifyDate"} {
+		_, _, found := fms[0].lookup(k)
+		assert.False(t, found, k)
+	}
+	ft, err := fms[0].Groups["File"].GetString("FileType")
+	assert.Nil(t, err)
+	assert.Equal(t, "JPEG", ft)
+}
@@ -0,0 +1,134 @@
+package exiftool
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// counters are the measures reported by Stats, updated atomically
+type counters struct {
+	commands     int64
+	latency      int64
+	restarts     int64
+	decodedBytes int64
+}
+
+// ProcessStats is the telemetry of an Exiftool and of its exiftool process
+type ProcessStats struct {
+	// Running is false if the exiftool process terminated
+	Running bool
+	// StartedAt is when the current exiftool process started
+	StartedAt time.Time
+	// Uptime is the duration the current exiftool process has been running for
+	Uptime time.Duration
+	// Commands is the number of commands sent to the exiftool processes
+	Commands int64
+	// Restarts is the number of times the exiftool process was restarted
+	Restarts int64
+	// AverageLatency is the average duration of the commands
+	AverageLatency time.Duration
+	// DecodedBytes is the size of the JSON printed by exiftool that was decoded
+	DecodedBytes int64
+	// Deduplicated is the number of files whose extraction was skipped, see Deduplicate
+	Deduplicated int64
+}
+
+// PoolStats is the telemetry of a Pool
+type PoolStats struct {
+	// Workers are the stats of the current workers, in no particular order
+	Workers []ProcessStats
+	// QueueLength is the number of extractions waiting for an idle worker
+	QueueLength int
+	// Restarts is the number of times a worker was restarted, either by itself (see
+	// AutoRestart) or replaced by the pool
+	Restarts int64
+	// Commands is the number of commands sent to the current workers
+	Commands int64
+	// AverageLatency is the average duration of the commands of the current workers
+	AverageLatency time.Duration
+	// DecodedBytes is the size of the JSON decoded by the current workers
+	DecodedBytes int64
+	// Deduplicated is the number of files whose extraction was skipped, either by the
+	// pool or by the current workers, see Deduplicate
+	Deduplicated int64
+}
+
+// observeCommand reports a command sent to the exiftool process, that took d
+func (e *Exiftool) observeCommand(d time.Duration) {
+	atomic.AddInt64(&e.counters.commands, 1)
+	atomic.AddInt64(&e.counters.latency, int64(d))
+}
+
+// Stats returns the telemetry of the Exiftool (uptime, commands, restarts, average
+// latency, decoded bytes and deduplicated files). It doesn't wait for the running command to complete.
+// Sample :
+//   s := e.Stats()
+//   fmt.Printf("%v commands, %v on average\n", s.Commands, s.AverageLatency)
+func (e *Exiftool) Stats() ProcessStats {
+	s := ProcessStats{
+		Commands:     atomic.LoadInt64(&e.counters.commands),
+		Restarts:     atomic.LoadInt64(&e.counters.restarts),
+		DecodedBytes: atomic.LoadInt64(&e.counters.decodedBytes),
+		Deduplicated: atomic.LoadInt64(&e.dedups),
+	}
+	if s.Commands > 0 {
+		s.AverageLatency = time.Duration(atomic.LoadInt64(&e.counters.latency) / s.Commands)
+	}
+
+	if p, ok := e.process.Load().(process); ok {
+		s.StartedAt = p.startedAt
+		select {
+		case <-p.exited:
+		default:
+			s.Running = true
+			s.Uptime = time.Since(p.startedAt)
+		}
+	}
+	return s
+}
+
+// Stats returns the telemetry of the pool: the stats of each worker, the queue length
+// and the totals. It doesn't wait for the running extractions to complete.
+// Sample :
+//   s := p.Stats()
+//   fmt.Printf("%v extractions waiting\n", s.QueueLength)
+func (p *Pool) Stats() PoolStats {
+	p.lock.Lock()
+	members := make([]*Exiftool, 0, len(p.members))
+	for e := range p.members {
+		members = append(members, e)
+	}
+	p.lock.Unlock()
+
+	s := PoolStats{
+		QueueLength:  int(atomic.LoadInt64(&p.waiting)),
+		Restarts:     atomic.LoadInt64(&p.replaced),
+		Deduplicated: atomic.LoadInt64(&p.dedups),
+	}
+	var latency int64
+	for _, e := range members {
+		ws := e.Stats()
+		s.Workers = append(s.Workers, ws)
+		s.Restarts += ws.Restarts
+		s.Commands += ws.Commands
+		s.DecodedBytes += ws.DecodedBytes
+		s.Deduplicated += ws.Deduplicated
+		latency += int64(ws.AverageLatency) * ws.Commands
+	}
+	if s.Commands > 0 {
+		s.AverageLatency = time.Duration(latency / s.Commands)
+	}
+	return s
+}
+
+// replace replaces the worker old by new in the members of the pool
+func (p *Pool) replace(old, new *Exiftool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if old != nil {
+		delete(p.members, old)
+		atomic.AddInt64(&p.replaced, 1)
+	}
+	p.members[new] = true
+}
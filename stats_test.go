@@ -0,0 +1,102 @@
+package exiftool
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExiftoolStats(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	s := e.Stats()
+	assert.True(t, s.Running)
+	assert.False(t, s.StartedAt.IsZero())
+	assert.Equal(t, int64(0), s.Commands)
+
+	fms := e.ExtractMetadata("./testdata/20190404_131804.jpg")
+	assert.Nil(t, fms[0].Err)
+
+	s = e.Stats()
+	assert.True(t, s.Running)
+	assert.True(t, s.Uptime > 0)
+	assert.Equal(t, int64(1), s.Commands)
+	assert.True(t, s.AverageLatency > 0)
+	assert.True(t, s.DecodedBytes > 0)
+	assert.Equal(t, int64(0), s.Restarts)
+	assert.Equal(t, int64(0), s.Deduplicated)
+
+	fms = e.Extract([]string{"./testdata/20190404_131804.jpg", "./testdata/20190404_131804.jpg"}, WithDeduplication(true))
+	assert.Nil(t, fms[1].Err)
+	assert.Equal(t, int64(1), e.Stats().Deduplicated)
+}
+
+func TestExiftoolStatsRestarts(t *testing.T) {
+	e, err := NewExiftool(AutoRestart(1, 0))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	e.cmd.Process.Kill()
+	<-e.exited
+	assert.False(t, e.Stats().Running)
+	assert.Equal(t, time.Duration(0), e.Stats().Uptime)
+
+	assert.Nil(t, e.Ping())
+	s := e.Stats()
+	assert.True(t, s.Running)
+	assert.Equal(t, int64(1), s.Restarts)
+}
+
+func TestStatsDecodedBytes(t *testing.T) {
+	e := Exiftool{}
+	assert.Equal(t, ProcessStats{}, e.Stats())
+
+	raw := json.RawMessage(`{"SourceFile": "a.jpg", "EXIF": {"Make": "Canon"}}`)
+	e.decodeRaw(&FileMetadata{File: "a.jpg"}, raw, nil)
+	e.decodeRaw(&FileMetadata{File: "a.jpg"}, raw, nil)
+	assert.Equal(t, int64(2*len(raw)), e.Stats().DecodedBytes)
+}
+
+func TestPoolStats(t *testing.T) {
+	p, err := NewPool(2, Deduplicate())
+	assert.Nil(t, err)
+	defer p.Close()
+
+	s := p.Stats()
+	assert.Len(t, s.Workers, 2)
+	assert.Equal(t, 0, s.QueueLength)
+
+	fms := p.ExtractMetadata("./testdata/20190404_131804.jpg", "./testdata/20190404_131804.jpg")
+	assert.Nil(t, fms[0].Err)
+
+	s = p.Stats()
+	assert.Len(t, s.Workers, 2)
+	assert.Equal(t, int64(1), s.Commands)
+	assert.True(t, s.AverageLatency > 0)
+	assert.True(t, s.DecodedBytes > 0)
+	assert.Equal(t, int64(1), s.Deduplicated)
+	assert.Equal(t, int64(0), s.Restarts)
+}
+
+func TestPoolStatsRestarts(t *testing.T) {
+	p, err := NewPool(1)
+	assert.Nil(t, err)
+	defer p.Close()
+
+	e := <-p.workers
+	assert.Nil(t, e.Close())
+	<-e.exited
+	p.release(e)
+
+	fms := p.ExtractMetadata("./testdata/20190404_131804.jpg")
+	assert.Nil(t, fms[0].Err)
+
+	s := p.Stats()
+	assert.Len(t, s.Workers, 1)
+	assert.Equal(t, int64(1), s.Restarts)
+	assert.Equal(t, int64(1), s.Commands)
+}
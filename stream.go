@@ -0,0 +1,201 @@
+package exiftool
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// StreamDecoder decodes the JSON array printed by exiftool ('-j -g' parameters) element
+// by element, so that the memory used does not depend on the number of files
+type StreamDecoder struct {
+	dec     *json.Decoder
+	started bool
+}
+
+// NewStreamDecoder instanciates a StreamDecoder reading from r
+// Sample :
+//   d := NewStreamDecoder(r)
+//   for {
+//     fm, err := d.Next()
+//     if err == io.EOF {
+//       break
+//     }
+//     ...
+//   }
+func NewStreamDecoder(r io.Reader) *StreamDecoder {
+	return &StreamDecoder{dec: json.NewDecoder(r)}
+}
+
+// Next decodes the next element of the array. io.EOF is returned once the whole array
+// has been read.
+func (d *StreamDecoder) Next() (FileMetadata, error) {
+	var fm FileMetadata
+
+	raw, err := d.nextRaw()
+	if err != nil {
+		return fm, err
+	}
+
+	if err := json.Unmarshal(raw, &fm); err != nil {
+		return fm, fmt.Errorf("error during unmarshaling (%v): %w", string(raw), err)
+	}
+
+	return fm, nil
+}
+
+// nextRaw returns the next element of the array, without decoding it
+func (d *StreamDecoder) nextRaw() (json.RawMessage, error) {
+	if !d.started {
+		t, err := d.dec.Token()
+		if err == io.EOF {
+			return nil, io.EOF
+		} else if err != nil {
+			return nil, fmt.Errorf("read array start: %w", err)
+		} else if t != json.Delim('[') {
+			return nil, errors.New("expected [")
+		}
+		d.started = true
+	}
+
+	if !d.dec.More() {
+		if _, err := d.dec.Token(); err != nil {
+			return nil, fmt.Errorf("read array end: %w", err)
+		}
+		return nil, io.EOF
+	}
+
+	var raw json.RawMessage
+	if err := d.dec.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("read array element: %w", err)
+	}
+
+	return raw, nil
+}
+
+// DecodeStream decodes the JSON array printed by exiftool read from r and streams the
+// decoded elements. Decoding stops at the first error, which is sent as the Err of a
+// last FileMetadata. The returned channel is closed once r has been read.
+func DecodeStream(r io.Reader) <-chan FileMetadata {
+	res := make(chan FileMetadata)
+	go func() {
+		defer close(res)
+		d := NewStreamDecoder(r)
+		for {
+			fm, err := d.Next()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				res <- FileMetadata{Err: err}
+				return
+			}
+			res <- fm
+		}
+	}()
+
+	return res
+}
+
+// ExtractMetadataStream extracts metadata from files with a single command of a
+// dedicated exiftool process, whose output is decoded and streamed file by file to keep
+// memory flat for huge batches. Results are streamed in the order of files, non
+// existing files first. As the warnings printed on stderr can't be matched with files,
+// only the ones stored in the ExifTool:Warning tag are reported. The returned channel is
+// closed once every file has been processed.
+func (e *Exiftool) ExtractMetadataStream(files ...string) <-chan FileMetadata {
+	res := make(chan FileMetadata)
+	go func() {
+		defer close(res)
+
+		var existing []string
+		for _, f := range files {
+			if err := e.checkFile(f); err != nil {
+				res <- FileMetadata{File: f, Err: err}
+				continue
+			}
+			existing = append(existing, f)
+		}
+		if len(existing) == 0 {
+			return
+		}
+
+		// exiftool prints the paths with slashes, whatever the platform
+		paths := make(map[string]string, len(existing))
+		for _, f := range existing {
+			paths[filepath.ToSlash(f)] = f
+		}
+		seen := map[string]bool{}
+		err := e.runStream(existing, func(raw json.RawMessage) {
+			var sf struct {
+				SourceFile string
+			}
+			json.Unmarshal(raw, &sf)
+			fm := FileMetadata{File: sf.SourceFile}
+			if f, found := paths[filepath.ToSlash(sf.SourceFile)]; found {
+				fm.File = f
+			}
+			e.decodeRaw(&fm, raw, nil)
+			seen[filepath.ToSlash(sf.SourceFile)] = true
+			res <- fm
+		})
+		if err == nil {
+			err = fmt.Errorf("no metadata in output")
+		}
+
+		for _, f := range existing {
+			if !seen[filepath.ToSlash(f)] {
+				res <- FileMetadata{File: f, Err: err}
+			}
+		}
+	}()
+
+	return res
+}
+
+// runStream runs a dedicated exiftool process extracting files, the file list being read
+// by exiftool from stdin (-@ -), and calls fn with each element of its output
+func (e *Exiftool) runStream(files []string, fn func(raw json.RawMessage)) error {
+	args := append([]string{}, e.extraInitArgs...)
+	args = append(args, e.extractArgs(e.extractConfig())...)
+	args = append(args, "-@", "-")
+
+	var stderr bytes.Buffer
+	cmd := e.command(args...)
+	cmd.Stdin = strings.NewReader(strings.Join(files, "\n") + "\n")
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("error when piping stdout: %w", err)
+	}
+
+	if err := e.startCommand(cmd); err != nil {
+		return fmt.Errorf("error when executing command: %w", err)
+	}
+
+	d := NewStreamDecoder(stdout)
+	var decErr error
+	for {
+		raw, err := d.nextRaw()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			decErr = err
+			io.Copy(ioutil.Discard, stdout)
+			break
+		}
+		fn(raw)
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("error when executing command (%v): %w", strings.TrimSpace(stderr.String()), err)
+	}
+
+	return decErr
+}
@@ -0,0 +1,96 @@
+package exiftool
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamDecoder(t *testing.T) {
+	in := `[{
+  "SourceFile": "a.jpg",
+  "File": {"FileName": "a.jpg"}
+},
+{
+  "SourceFile": "b.jpg",
+  "EXIF": {"ISO": 100}
+}]
+`
+	d := NewStreamDecoder(strings.NewReader(in))
+
+	fm, err := d.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, "a.jpg", fm.File)
+	assert.Equal(t, map[string]FileMetadataValues{"File": {{"FileName", "a.jpg"}}}, fm.Groups)
+
+	fm, err = d.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, "b.jpg", fm.File)
+	assert.Equal(t, map[string]FileMetadataValues{"EXIF": {{"ISO", float64(100)}}}, fm.Groups)
+
+	_, err = d.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestStreamDecoderKo(t *testing.T) {
+	var tcs = []struct {
+		tcID  string
+		in    string
+		expOk bool
+	}{
+		{"empty", ``, true},
+		{"emptyArray", `[]`, true},
+		{"notArray", `{}`, false},
+		{"truncated", `[{"SourceFile": "a.jpg"`, false},
+		{"notObject", `["a"]`, false},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			_, err := NewStreamDecoder(strings.NewReader(tc.in)).Next()
+			assert.Equal(t, tc.expOk, err == io.EOF, "unexpected error: %v", err)
+		})
+	}
+}
+
+func TestDecodeStream(t *testing.T) {
+	var files []string
+	var errs int
+	for fm := range DecodeStream(strings.NewReader(`[{"SourceFile":"a.jpg"},{"SourceFile":"b.jpg"},`)) {
+		if fm.Err != nil {
+			errs++
+			continue
+		}
+		files = append(files, fm.File)
+	}
+	assert.Equal(t, []string{"a.jpg", "b.jpg"}, files)
+	assert.Equal(t, 1, errs)
+}
+
+func TestExtractMetadataStream(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	files := []string{
+		"./testdata/20190404_131804.jpg",
+		"./testdata/nonExisting",
+		"./testdata/extractEmbedded.mp4",
+	}
+	var got []string
+	for fm := range e.ExtractMetadataStream(files...) {
+		got = append(got, fm.File)
+		if fm.File == "./testdata/nonExisting" {
+			assert.True(t, errors.Is(fm.Err, ErrNotExist))
+			continue
+		}
+		assert.Nil(t, fm.Err)
+		_, found := fm.Groups["File"]
+		assert.True(t, found)
+	}
+	assert.Equal(t, []string{"./testdata/nonExisting", "./testdata/20190404_131804.jpg", "./testdata/extractEmbedded.mp4"}, got)
+}
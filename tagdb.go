@@ -0,0 +1,229 @@
+package exiftool
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// TagInfo describes a tag known by exiftool, as listed by exiftool -listx. Table is the
+// exiftool table defining the tag (ie. "Exif::Main") and Groups its groups of families 0,
+// 1 and 2 (ie. "EXIF", "IFD0", "Image"). A tag can be defined by several tables.
+type TagInfo struct {
+	Name        string
+	Table       string
+	Groups      [3]string
+	Type        string
+	Writable    bool
+	Description string
+}
+
+// TagDatabase is the list of the tags known by exiftool, see Exiftool.TagDatabase
+type TagDatabase struct {
+	tags   []TagInfo
+	byName map[string][]int
+}
+
+// TagDatabase returns the list of the tags known by exiftool (exiftool -listx), which is
+// loaded once, by a dedicated exiftool process, on the first call. If anything went
+// wrong, a non empty error will be returned.
+// Sample :
+//   db, err := e.TagDatabase()
+//   infos := db.Lookup("EXIF:Artist")
+func (e *Exiftool) TagDatabase() (*TagDatabase, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.tagDB != nil {
+		return e.tagDB, nil
+	}
+
+	var stderr bytes.Buffer
+	cmd := e.command("-listx")
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("error when piping stdout: %w", err)
+	}
+
+	if err := e.startCommand(cmd); err != nil {
+		return nil, fmt.Errorf("error when executing command: %w", err)
+	}
+
+	db, decErr := parseTagDatabase(stdout)
+	if decErr != nil {
+		io.Copy(ioutil.Discard, stdout)
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return nil, fmt.Errorf("error when executing command (%v): %w", strings.TrimSpace(stderr.String()), err)
+	}
+	if decErr != nil {
+		return nil, decErr
+	}
+
+	e.tagDB = db
+	return db, nil
+}
+
+type listxTable struct {
+	Name string `xml:"name,attr"`
+	G0   string `xml:"g0,attr"`
+	G1   string `xml:"g1,attr"`
+	G2   string `xml:"g2,attr"`
+}
+
+type listxTag struct {
+	Name     string `xml:"name,attr"`
+	Type     string `xml:"type,attr"`
+	Writable string `xml:"writable,attr"`
+	G0       string `xml:"g0,attr"`
+	G1       string `xml:"g1,attr"`
+	G2       string `xml:"g2,attr"`
+	Descs    []struct {
+		Lang string `xml:"lang,attr"`
+		Text string `xml:",chardata"`
+	} `xml:"desc"`
+}
+
+// parseTagDatabase parses the output of exiftool -listx
+func parseTagDatabase(r io.Reader) (*TagDatabase, error) {
+	db := TagDatabase{byName: map[string][]int{}}
+
+	d := xml.NewDecoder(r)
+	var table listxTable
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing tag list: %w", err)
+		}
+
+		se, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch se.Name.Local {
+		case "table":
+			table = listxTable{}
+			for _, a := range se.Attr {
+				switch a.Name.Local {
+				case "name":
+					table.Name = a.Value
+				case "g0":
+					table.G0 = a.Value
+				case "g1":
+					table.G1 = a.Value
+				case "g2":
+					table.G2 = a.Value
+				}
+			}
+		case "tag":
+			var t listxTag
+			if err := d.DecodeElement(&t, &se); err != nil {
+				return nil, fmt.Errorf("error while parsing tag list: %w", err)
+			}
+			db.add(tagInfo(table, t))
+		}
+	}
+
+	if len(db.tags) == 0 {
+		return nil, fmt.Errorf("no tag in tag list")
+	}
+
+	return &db, nil
+}
+
+// tagInfo returns the TagInfo of t, defined by table
+func tagInfo(table listxTable, t listxTag) TagInfo {
+	info := TagInfo{
+		Name:     t.Name,
+		Table:    table.Name,
+		Groups:   [3]string{table.G0, table.G1, table.G2},
+		Type:     t.Type,
+		Writable: t.Writable == "true",
+	}
+	for i, g := range []string{t.G0, t.G1, t.G2} {
+		if g != "" {
+			info.Groups[i] = g
+		}
+	}
+	for i, desc := range t.Descs {
+		if i == 0 || desc.Lang == "en" {
+			info.Description = strings.TrimSpace(desc.Text)
+		}
+		if desc.Lang == "en" {
+			break
+		}
+	}
+	return info
+}
+
+func (db *TagDatabase) add(info TagInfo) {
+	k := strings.ToLower(info.Name)
+	db.byName[k] = append(db.byName[k], len(db.tags))
+	db.tags = append(db.tags, info)
+}
+
+// Lookup returns the definitions of the tag k, which can be either "LABEL" or
+// "GROUP:LABEL" (GROUP being a group of any family, ie. "EXIF:Artist" or "IFD0:Artist").
+// Tag names and groups are case insensitive, and the print conversion suffix ("#") is
+// ignored. Nil is returned if the tag is unknown.
+func (db *TagDatabase) Lookup(k string) []TagInfo {
+	var grp string
+	if idx := strings.LastIndex(k, ":"); idx != -1 {
+		grp, k = k[:idx], k[idx+1:]
+	}
+
+	var infos []TagInfo
+	for _, i := range db.byName[strings.ToLower(strings.TrimSuffix(k, "#"))] {
+		info := db.tags[i]
+		if grp != "" && !info.inGroup(grp) {
+			continue
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// Writable returns true if the tag k (see Lookup) is known and writable
+func (db *TagDatabase) Writable(k string) bool {
+	for _, info := range db.Lookup(k) {
+		if info.Writable {
+			return true
+		}
+	}
+	return false
+}
+
+// Names returns the names of the known tags, sorted alphabetically
+func (db *TagDatabase) Names() []string {
+	names := make([]string, 0, len(db.byName))
+	for _, is := range db.byName {
+		names = append(names, db.tags[is[0]].Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Len returns the number of tag definitions
+func (db *TagDatabase) Len() int {
+	return len(db.tags)
+}
+
+// inGroup returns true if grp (case insensitive) is one of the groups of the tag
+func (info TagInfo) inGroup(grp string) bool {
+	for _, g := range info.Groups {
+		if strings.EqualFold(g, grp) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package exiftool
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const listxSample = `<?xml version='1.0' encoding='UTF-8'?>
+<!-- Generated by Image::ExifTool 12.40 -->
+<taginfo>
+
+<table name='Exif::Main' g0='EXIF' g1='IFD0' g2='Image'>
+ <desc lang='en'>Exif</desc>
+ <tag id='1' name='InteropIndex' type='string' writable='true' g1='InteropIFD'>
+  <desc lang='en'>Interoperability Index</desc>
+  <desc lang='fr'>Indice d'interopérabilité</desc>
+  <values>
+   <key id='R03'>
+    <val lang='en'>R03 - DCF option file (Adobe RGB)</val>
+   </key>
+  </values>
+ </tag>
+ <tag id='315' name='Artist' type='string' writable='true'>
+  <desc lang='fr'>Artiste</desc>
+  <desc lang='en'>Artist</desc>
+ </tag>
+ <tag id='271' name='Make' type='string' writable='true'>
+  <desc lang='en'>Make</desc>
+ </tag>
+</table>
+
+<table name='XMP::dc' g0='XMP' g1='XMP-dc' g2='Other'>
+ <desc lang='en'>XMP Dublin Core</desc>
+ <tag id='creator' name='Creator' type='lang-alt' writable='true' g2='Author'>
+  <desc lang='en'>Creator</desc>
+ </tag>
+</table>
+
+<table name='Composite' g0='Composite' g1='Composite' g2='Other'>
+ <tag id='ImageSize' name='ImageSize' writable='false'>
+  <desc lang='en'>Image Size</desc>
+ </tag>
+ <tag id='Make' name='Make' writable='false'>
+  <desc lang='en'>Make</desc>
+ </tag>
+</table>
+
+</taginfo>
+`
+
+func TestParseTagDatabase(t *testing.T) {
+	db, err := parseTagDatabase(strings.NewReader(listxSample))
+	assert.Nil(t, err)
+	assert.Equal(t, 6, db.Len())
+	assert.Equal(t, []string{"Artist", "Creator", "ImageSize", "InteropIndex", "Make"}, db.Names())
+
+	assert.Equal(t, []TagInfo{{
+		Name:        "InteropIndex",
+		Table:       "Exif::Main",
+		Groups:      [3]string{"EXIF", "InteropIFD", "Image"},
+		Type:        "string",
+		Writable:    true,
+		Description: "Interoperability Index",
+	}}, db.Lookup("InteropIndex"))
+	assert.Equal(t, "Artist", db.Lookup("artist")[0].Description)
+	assert.Equal(t, [3]string{"XMP", "XMP-dc", "Author"}, db.Lookup("Creator")[0].Groups)
+
+	_, err = parseTagDatabase(strings.NewReader("<taginfo></taginfo>"))
+	assert.NotNil(t, err)
+	_, err = parseTagDatabase(strings.NewReader("<taginfo><table>"))
+	assert.NotNil(t, err)
+}
+
+func TestTagDatabaseLookup(t *testing.T) {
+	db, err := parseTagDatabase(strings.NewReader(listxSample))
+	assert.Nil(t, err)
+
+	var tcs = []struct {
+		tcID        string
+		in          string
+		expTables   []string
+		expWritable bool
+	}{
+		{"name", "Make", []string{"Exif::Main", "Composite"}, true},
+		{"family0", "EXIF:Make", []string{"Exif::Main"}, true},
+		{"family1", "ifd0:make", []string{"Exif::Main"}, true},
+		{"printConversion", "Composite:Make#", []string{"Composite"}, false},
+		{"tagGroup", "InteropIFD:InteropIndex", []string{"Exif::Main"}, true},
+		{"otherGroup", "XMP:Make", nil, false},
+		{"unknown", "Unknown", nil, false},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			var tables []string
+			for _, info := range db.Lookup(tc.in) {
+				tables = append(tables, info.Table)
+			}
+			assert.Equal(t, tc.expTables, tables)
+			assert.Equal(t, tc.expWritable, db.Writable(tc.in))
+		})
+	}
+}
+
+func TestTagDatabase(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	db, err := e.TagDatabase()
+	assert.Nil(t, err)
+	assert.True(t, db.Writable("EXIF:Artist"))
+	assert.False(t, db.Writable("Composite:ImageSize"))
+
+	again, err := e.TagDatabase()
+	assert.Nil(t, err)
+	assert.True(t, db == again)
+}
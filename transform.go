@@ -0,0 +1,133 @@
+package exiftool
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValueTransformer transforms the value of a tag at decode time (see TransformValue)
+type ValueTransformer func(v interface{}) (interface{}, error)
+
+// tagMatcher matches the tags of a key, either "GROUP:LABEL" or "LABEL"
+type tagMatcher struct {
+	group string
+	label string
+}
+
+// newTagMatcher returns the tagMatcher of the key k
+func newTagMatcher(k string) (tagMatcher, error) {
+	m := tagMatcher{label: k}
+	if idx := strings.LastIndex(k, ":"); idx != -1 {
+		m.group, m.label = k[:idx], k[idx+1:]
+	}
+	if m.label == "" {
+		return tagMatcher{}, fmt.Errorf("invalid tag (%q)", k)
+	}
+	return m, nil
+}
+
+// matches returns true if the tag label of the group g is matched by m
+func (m tagMatcher) matches(g, label string) bool {
+	if label != m.label {
+		return false
+	}
+	return m.group == "" || g == m.group || hasComponent(g, m.group)
+}
+
+// valueTransformer is a ValueTransformer registered for the tags matched by tagMatcher
+type valueTransformer struct {
+	tagMatcher
+	t ValueTransformer
+}
+
+// TransformValue registers t to be applied at decode time to the values of the tag k,
+// which can be either "GROUP:LABEL" or "LABEL" (to transform the tag of every group),
+// GROUP being a key of FileMetadata.Groups or one of its components. The transformers
+// of a tag are chained in registration order. If a transformer fails, the error is
+// returned in FileMetadata.Err.
+// Sample :
+//   e, err := NewExiftool(
+//     TransformValue("EXIF:ExposureTime", RationalToFloat()),
+//     TransformValue("Orientation", OrientationName()),
+//   )
+func TransformValue(k string, t ValueTransformer) Option {
+	return func(e *Exiftool) error {
+		if t == nil {
+			return fmt.Errorf("nil transformer")
+		}
+		m, err := newTagMatcher(k)
+		if err != nil {
+			return err
+		}
+		e.transformers = append(e.transformers, valueTransformer{tagMatcher: m, t: t})
+		return nil
+	}
+}
+
+// transformValues applies the registered transformers to every group of fm
+func (e *Exiftool) transformValues(fm *FileMetadata) error {
+	for _, n := range fm.groupNames() {
+		g := fm.Groups[n]
+		for i := range g {
+			for _, vt := range e.transformers {
+				if !vt.matches(n, g[i].Label) {
+					continue
+				}
+				v, err := vt.t(g[i].Value)
+				if err != nil {
+					return fmt.Errorf("error while transforming %v:%v: %w", n, g[i].Label, err)
+				}
+				g[i].Value = v
+			}
+		}
+	}
+	return nil
+}
+
+// RationalToFloat returns a ValueTransformer converting rationals (ie. "1/250") and
+// numbers printed as strings to float64
+func RationalToFloat() ValueTransformer {
+	return func(v interface{}) (interface{}, error) {
+		if f, ok := v.(float64); ok {
+			return f, nil
+		}
+		return toFloatFallback(strings.TrimSpace(toString(v)))
+	}
+}
+
+// TrimNulls returns a ValueTransformer trimming the NUL characters and the spaces padding
+// strings (ie. maker notes), string lists included
+func TrimNulls() ValueTransformer {
+	trim := func(s string) string {
+		return strings.Trim(s, "\x00 \t\r\n")
+	}
+	return func(v interface{}) (interface{}, error) {
+		switch v := v.(type) {
+		case string:
+			return trim(v), nil
+		case []interface{}:
+			res := make([]interface{}, len(v))
+			for i, iv := range v {
+				if s, ok := iv.(string); ok {
+					iv = trim(s)
+				}
+				res[i] = iv
+			}
+			return res, nil
+		default:
+			return v, nil
+		}
+	}
+}
+
+// OrientationName returns a ValueTransformer converting EXIF orientations, numeric or
+// not, to the names printed by exiftool (ie. 6 to "Rotate 90 CW")
+func OrientationName() ValueTransformer {
+	return func(v interface{}) (interface{}, error) {
+		o, err := parseOrientation(toString(v))
+		if err != nil {
+			return nil, err
+		}
+		return o.String(), nil
+	}
+}
@@ -0,0 +1,146 @@
+package exiftool
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformValueOption(t *testing.T) {
+	e := Exiftool{}
+	assert.Nil(t, TransformValue("EXIF:ExposureTime", RationalToFloat())(&e))
+	assert.Nil(t, TransformValue("Make", TrimNulls())(&e))
+	assert.Equal(t, "EXIF", e.transformers[0].group)
+	assert.Equal(t, "ExposureTime", e.transformers[0].label)
+	assert.Equal(t, "", e.transformers[1].group)
+	assert.Equal(t, "Make", e.transformers[1].label)
+
+	assert.NotNil(t, TransformValue("Make", nil)(&e))
+	assert.NotNil(t, TransformValue("", TrimNulls())(&e))
+	assert.NotNil(t, TransformValue("EXIF:", TrimNulls())(&e))
+	assert.Len(t, e.transformers, 2)
+}
+
+func TestValueTransformerMatches(t *testing.T) {
+	var tcs = []struct {
+		tcID     string
+		inKey    string
+		inGroup  string
+		inLabel  string
+		expMatch bool
+	}{
+		{"group", "EXIF:Make", "EXIF", "Make", true},
+		{"component", "IFD0:Make", "EXIF:IFD0", "Make", true},
+		{"anyGroup", "Make", "MakerNotes", "Make", true},
+		{"otherGroup", "XMP:Make", "EXIF", "Make", false},
+		{"otherLabel", "EXIF:Make", "EXIF", "Model", false},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			e := Exiftool{}
+			assert.Nil(t, TransformValue(tc.inKey, TrimNulls())(&e))
+			assert.Equal(t, tc.expMatch, e.transformers[0].matches(tc.inGroup, tc.inLabel))
+		})
+	}
+}
+
+func TestTransformValues(t *testing.T) {
+	e := Exiftool{}
+	assert.Nil(t, TransformValue("EXIF:ExposureTime", RationalToFloat())(&e))
+	assert.Nil(t, TransformValue("Make", TrimNulls())(&e))
+	assert.Nil(t, TransformValue("Make", func(v interface{}) (interface{}, error) {
+		return fmt.Sprintf("[%v]", v), nil
+	})(&e))
+
+	raw := json.RawMessage(`{"SourceFile": "a.jpg",
+		"EXIF": {"Make": "Canon\u0000\u0000", "ExposureTime": "1/250"},
+		"MakerNotes": {"Make": " Canon ", "ExposureTime": "1/250"}}`)
+	fm := FileMetadata{File: "a.jpg"}
+	e.decodeRaw(&fm, raw, nil)
+	assert.Nil(t, fm.Err)
+	assert.Equal(t, FileMetadataValues{{"Make", "[Canon]"}, {"ExposureTime", 0.004}}, fm.Groups["EXIF"])
+	assert.Equal(t, FileMetadataValues{{"Make", "[Canon]"}, {"ExposureTime", "1/250"}}, fm.Groups["MakerNotes"])
+
+	assert.Nil(t, TransformValue("EXIF:ExposureTime", func(v interface{}) (interface{}, error) {
+		return nil, fmt.Errorf("failure")
+	})(&e))
+	fm = FileMetadata{File: "a.jpg"}
+	e.decodeRaw(&fm, raw, nil)
+	assert.NotNil(t, fm.Err)
+}
+
+func TestRationalToFloat(t *testing.T) {
+	var tcs = []struct {
+		tcID  string
+		in    interface{}
+		expOk bool
+		expV  interface{}
+	}{
+		{"rational", "1/250", true, 0.004},
+		{"string", " 2.5 ", true, 2.5},
+		{"float", float64(8), true, float64(8)},
+		{"unit", "100 mm", true, float64(100)},
+		{"invalid", "fast", false, nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			v, err := RationalToFloat()(tc.in)
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.expV, v)
+			}
+		})
+	}
+}
+
+func TestTrimNulls(t *testing.T) {
+	var tcs = []struct {
+		tcID string
+		in   interface{}
+		expV interface{}
+	}{
+		{"string", "Canon\x00\x00 ", "Canon"},
+		{"list", []interface{}{" a\x00", float64(1)}, []interface{}{"a", float64(1)}},
+		{"float", float64(1), float64(1)},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			v, err := TrimNulls()(tc.in)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expV, v)
+		})
+	}
+}
+
+func TestOrientationName(t *testing.T) {
+	var tcs = []struct {
+		tcID  string
+		in    interface{}
+		expOk bool
+		expV  interface{}
+	}{
+		{"float", float64(6), true, "Rotate 90 CW"},
+		{"string", "1", true, "Horizontal (normal)"},
+		{"name", "Rotate 180", true, "Rotate 180"},
+		{"invalid", float64(9), false, nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			v, err := OrientationName()(tc.in)
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.expV, v)
+			}
+		})
+	}
+}
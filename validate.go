@@ -0,0 +1,74 @@
+package exiftool
+
+import (
+	"fmt"
+	"strings"
+)
+
+// unchangedMessage is printed by exiftool when a write command didn't update any file
+const unchangedMessage = "0 image files updated"
+
+// ValidateWrites makes Write (and the functions relying on it, like Delete) check the
+// tags to write against the list of writable tags (exiftool -listw) before invoking
+// exiftool, an UnwritableTagError being returned for unknown or unwritable tags. The list
+// is loaded once, on the first write.
+// Sample :
+//   e, err := NewExiftool(ValidateWrites())
+func ValidateWrites() Option {
+	return func(e *Exiftool) error {
+		e.validate = true
+		return nil
+	}
+}
+
+// checkWritable returns an UnwritableTagError if the label of one of values is not a
+// writable tag, when ValidateWrites is used. The caller must hold e.lock.
+func (e *Exiftool) checkWritable(values FileMetadataValues) error {
+	if !e.validate {
+		return nil
+	}
+
+	if e.writable == nil {
+		out, err := e.execute("-listw")
+		if err != nil {
+			return fmt.Errorf("error while listing writable tags: %w", err)
+		}
+		e.writable = parseTagList(out)
+	}
+
+	for _, v := range values {
+		n := tagName(v.Label)
+		if n == "all" || strings.ContainsAny(n, "*?") {
+			continue
+		}
+		if !e.writable[n] {
+			return &UnwritableTagError{Tag: v.Label}
+		}
+	}
+
+	return nil
+}
+
+// parseTagList parses the output of exiftool's -list parameters into the set of the
+// lowercased tag names
+func parseTagList(out []byte) map[string]bool {
+	tags := map[string]bool{}
+	for _, l := range strings.Split(string(out), "\n") {
+		if l = strings.TrimSpace(l); strings.HasSuffix(l, ":") {
+			continue
+		}
+		for _, t := range strings.Fields(l) {
+			tags[strings.ToLower(t)] = true
+		}
+	}
+	return tags
+}
+
+// tagName returns the lowercased tag name of label, without its groups (ie. "XMP-dc:")
+// nor its print conversion suffix ("#")
+func tagName(label string) string {
+	if idx := strings.LastIndex(label, ":"); idx != -1 {
+		label = label[idx+1:]
+	}
+	return strings.ToLower(strings.TrimSuffix(label, "#"))
+}
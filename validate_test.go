@@ -0,0 +1,50 @@
+package exiftool
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTagList(t *testing.T) {
+	out := "Writable tags:\n  AAE AboutCvTerm Artist\n  XMP-dc:Title Title\n"
+	assert.Equal(t, map[string]bool{"aae": true, "aboutcvterm": true, "artist": true, "xmp-dc:title": true, "title": true}, parseTagList([]byte(out)))
+}
+
+func TestTagName(t *testing.T) {
+	assert.Equal(t, "title", tagName("XMP-dc:Title"))
+	assert.Equal(t, "orientation", tagName("EXIF:IFD0:Orientation#"))
+	assert.Equal(t, "artist", tagName("Artist"))
+}
+
+func TestCheckWritable(t *testing.T) {
+	e := Exiftool{}
+	assert.Nil(t, e.checkWritable(FileMetadataValues{{"Unknown", "a"}}))
+
+	assert.Nil(t, ValidateWrites()(&e))
+	e.writable = map[string]bool{"artist": true, "title": true}
+	assert.Nil(t, e.checkWritable(FileMetadataValues{{"Artist", "a"}, {"XMP:Title", "t"}, {"GPS:all", nil}, {"*Date", nil}}))
+
+	err := e.checkWritable(FileMetadataValues{{"Artist", "a"}, {"EXIF:FileSize", "1"}})
+	var u *UnwritableTagError
+	assert.True(t, errors.As(err, &u))
+	assert.Equal(t, "EXIF:FileSize", u.Tag)
+}
+
+func TestNewExifTool_WithValidateWrites(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+
+	e, err := NewExiftool(ValidateWrites())
+	assert.Nil(t, err)
+	defer e.Close()
+
+	var u *UnwritableTagError
+	assert.True(t, errors.As(e.Write(f, FileMetadataValues{{"NotATag", "a"}}), &u))
+	assert.True(t, errors.As(e.Write(f, FileMetadataValues{{"FileSize", "1"}}), &u))
+	assert.Nil(t, e.Write(f, FileMetadataValues{{"Artist", "go-exiftool"}}))
+
+	var fu *FileUnchangedError
+	assert.True(t, errors.As(e.Delete(f, "XMP:Title"), &fu))
+}
@@ -0,0 +1,145 @@
+package exiftool
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VideoInfo gathers the main characteristics of a video. Fields are zero when the
+// corresponding tag can't be found. BitRate is in bits per second and Rotation in
+// degrees (clockwise).
+type VideoInfo struct {
+	Duration     time.Duration
+	FrameRate    float64
+	Codec        string
+	Rotation     int
+	BitRate      int64
+	CreationTime time.Time
+}
+
+// Keys of the video tags, in QuickTime (MP4, MOV), Matroska (MKV, WebM) and RIFF (AVI)
+// files
+var (
+	videoDurationKeys     = []string{"QuickTime:Duration", "Matroska:Duration", "RIFF:Duration", "Composite:Duration", "Duration"}
+	videoFrameRateKeys    = []string{"QuickTime:VideoFrameRate", "Matroska:VideoFrameRate", "RIFF:VideoFrameRate", "RIFF:FrameRate", "VideoFrameRate"}
+	videoCodecKeys        = []string{"QuickTime:CompressorID", "QuickTime:CompressorName", "Matroska:CodecID", "RIFF:VideoCodec", "VideoCodec"}
+	videoRotationKeys     = []string{"QuickTime:Rotation", "Composite:Rotation", "Rotation"}
+	videoBitRateKeys      = []string{"QuickTime:AvgBitrate", "Composite:AvgBitrate", "AvgBitrate", "RIFF:MaxDataRate"}
+	videoCreationTimeKeys = []string{"QuickTime:CreateDate", "QuickTime:MediaCreateDate", "Matroska:DateTimeOriginal", "RIFF:DateTimeOriginal", "CreateDate"}
+)
+
+// VideoInfo returns the main characteristics of the video, whatever its container.
+// ErrKeyNotFound will be returned if none of them can be found.
+func (fm FileMetadata) VideoInfo() (VideoInfo, error) {
+	var vi VideoInfo
+	found := false
+
+	if s, ok := fm.lookupString(videoDurationKeys...); ok {
+		d, err := parseDuration(s)
+		if err != nil {
+			return VideoInfo{}, fmt.Errorf("Duration parsing error: %w", err)
+		}
+		vi.Duration, found = d, true
+	}
+
+	if s, ok := fm.lookupString(videoFrameRateKeys...); ok {
+		f, err := toFloatFallback(s)
+		if err != nil {
+			return VideoInfo{}, fmt.Errorf("VideoFrameRate parsing error: %w", err)
+		}
+		vi.FrameRate, found = f, true
+	}
+
+	if s, ok := fm.lookupString(videoCodecKeys...); ok {
+		vi.Codec, found = strings.TrimSpace(s), true
+	}
+
+	if s, ok := fm.lookupString(videoRotationKeys...); ok {
+		r, err := toIntFallback(s)
+		if err != nil {
+			return VideoInfo{}, fmt.Errorf("Rotation parsing error: %w", err)
+		}
+		vi.Rotation, found = int(r), true
+	}
+
+	if s, ok := fm.lookupString(videoBitRateKeys...); ok {
+		b, err := parseBitRate(s)
+		if err != nil {
+			return VideoInfo{}, fmt.Errorf("AvgBitrate parsing error: %w", err)
+		}
+		vi.BitRate, found = b, true
+	}
+
+	if s, ok := fm.lookupString(videoCreationTimeKeys...); ok {
+		// an unset QuickTime date is printed as "0000:00:00 00:00:00"
+		if t, err := toDateLayout(s, fm.DateLayout); err == nil {
+			vi.CreationTime, found = t, true
+		}
+	}
+
+	if !found {
+		return VideoInfo{}, ErrKeyNotFound
+	}
+
+	return vi, nil
+}
+
+// parseDuration parses a duration printed by exiftool: seconds ("12.5" with -n,
+// "12.5 s"), "H:MM:SS" or "H:MM:SS.ss", optionally followed by " (approx)"
+func parseDuration(s string) (time.Duration, error) {
+	str := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "(approx)"))
+	str = strings.TrimSpace(strings.TrimSuffix(str, " s"))
+
+	if f, err := strconv.ParseFloat(str, 64); err == nil {
+		return secondsToDuration(f), nil
+	}
+
+	parts := strings.Split(str, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid duration (%v)", s)
+	}
+	var secs float64
+	for _, p := range parts {
+		f, err := strconv.ParseFloat(p, 64)
+		if err != nil || f < 0 {
+			return 0, fmt.Errorf("invalid duration (%v)", s)
+		}
+		secs = secs*60 + f
+	}
+
+	return secondsToDuration(secs), nil
+}
+
+func secondsToDuration(secs float64) time.Duration {
+	return time.Duration(math.Round(secs * float64(time.Second)))
+}
+
+// bitRateUnits are the multipliers of the bit rate units printed by exiftool
+var bitRateUnits = map[string]float64{"bps": 1, "kbps": 1e3, "mbps": 1e6, "gbps": 1e9}
+
+// parseBitRate parses a bit rate printed by exiftool, either in bits per second (-n) or
+// with a unit ("4.56 Mbps"), and returns it in bits per second
+func parseBitRate(s string) (int64, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 || len(fields) > 2 {
+		return 0, fmt.Errorf("invalid bit rate (%v)", s)
+	}
+
+	f, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid bit rate (%v): %w", s, err)
+	}
+
+	if len(fields) == 2 {
+		m, found := bitRateUnits[strings.ToLower(fields[1])]
+		if !found {
+			return 0, fmt.Errorf("invalid bit rate unit (%v)", s)
+		}
+		f *= m
+	}
+
+	return int64(math.Round(f)), nil
+}
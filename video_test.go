@@ -0,0 +1,137 @@
+package exiftool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDuration(t *testing.T) {
+	var tcs = []struct {
+		tcID  string
+		in    string
+		expOk bool
+		expD  time.Duration
+	}{
+		{"numeric", "12.5", true, 12500 * time.Millisecond},
+		{"seconds", "12.5 s", true, 12500 * time.Millisecond},
+		{"approx", "3.04 s (approx)", true, 3040 * time.Millisecond},
+		{"clock", "0:01:23", true, 83 * time.Second},
+		{"clockFraction", "1:00:00.5", true, time.Hour + 500*time.Millisecond},
+		{"minutes", "2:30", true, 150 * time.Second},
+		{"invalid", "long", false, 0},
+		{"invalidClock", "0:a:23", false, 0},
+		{"tooManyParts", "1:2:3:4", false, 0},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			d, err := parseDuration(tc.in)
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.expD, d)
+			}
+		})
+	}
+}
+
+func TestParseBitRate(t *testing.T) {
+	var tcs = []struct {
+		tcID  string
+		in    string
+		expOk bool
+		expB  int64
+	}{
+		{"numeric", "4562096", true, 4562096},
+		{"bps", "850 bps", true, 850},
+		{"kbps", "128 kbps", true, 128000},
+		{"mbps", "4.56 Mbps", true, 4560000},
+		{"unknownUnit", "4.56 Mbit", false, 0},
+		{"invalid", "fast", false, 0},
+		{"empty", "", false, 0},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			b, err := parseBitRate(tc.in)
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.expB, b)
+			}
+		})
+	}
+}
+
+func TestVideoInfo(t *testing.T) {
+	var tcs = []struct {
+		tcID  string
+		in    map[string]FileMetadataValues
+		expOk bool
+		expVi VideoInfo
+	}{
+		{"quicktime", map[string]FileMetadataValues{"QuickTime": {
+			{"Duration", "0:01:23"},
+			{"VideoFrameRate", float64(29.97)},
+			{"CompressorID", "avc1"},
+			{"Rotation", float64(90)},
+			{"AvgBitrate", "4.56 Mbps"},
+			{"CreateDate", "2019:04:04 13:18:04"},
+		}}, true, VideoInfo{
+			Duration:     83 * time.Second,
+			FrameRate:    29.97,
+			Codec:        "avc1",
+			Rotation:     90,
+			BitRate:      4560000,
+			CreationTime: time.Date(2019, 4, 4, 13, 18, 4, 0, time.UTC),
+		}},
+		{"quicktimeNumeric", map[string]FileMetadataValues{"QuickTime": {
+			{"Duration", float64(3.5)},
+			{"AvgBitrate", float64(128000)},
+		}}, true, VideoInfo{Duration: 3500 * time.Millisecond, BitRate: 128000}},
+		{"matroska", map[string]FileMetadataValues{"Matroska": {
+			{"Duration", "12.5 s"},
+			{"CodecID", "V_MPEG4/ISO/AVC"},
+			{"VideoFrameRate", float64(25)},
+			{"DateTimeOriginal", "2020:01:02 03:04:05Z"},
+		}}, true, VideoInfo{
+			Duration:     12500 * time.Millisecond,
+			FrameRate:    25,
+			Codec:        "V_MPEG4/ISO/AVC",
+			CreationTime: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		}},
+		{"riff", map[string]FileMetadataValues{"RIFF": {
+			{"Duration", "5.00 s"},
+			{"FrameRate", float64(30)},
+			{"VideoCodec", "XVID"},
+		}}, true, VideoInfo{Duration: 5 * time.Second, FrameRate: 30, Codec: "XVID"}},
+		{"unsetDate", map[string]FileMetadataValues{"QuickTime": {
+			{"Duration", "1 s"},
+			{"CreateDate", "0000:00:00 00:00:00"},
+		}}, true, VideoInfo{Duration: time.Second}},
+		{"invalidDuration", map[string]FileMetadataValues{"QuickTime": {{"Duration", "long"}}}, false, VideoInfo{}},
+		{"invalidBitRate", map[string]FileMetadataValues{"QuickTime": {{"AvgBitrate", "fast"}}}, false, VideoInfo{}},
+		{"image", map[string]FileMetadataValues{"EXIF": {{"Make", "samsung"}}}, false, VideoInfo{}},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			vi, err := FileMetadata{Groups: tc.in}.VideoInfo()
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.expVi.Duration, vi.Duration)
+				assert.Equal(t, tc.expVi.FrameRate, vi.FrameRate)
+				assert.Equal(t, tc.expVi.Codec, vi.Codec)
+				assert.Equal(t, tc.expVi.Rotation, vi.Rotation)
+				assert.Equal(t, tc.expVi.BitRate, vi.BitRate)
+				assert.True(t, tc.expVi.CreationTime.Equal(vi.CreationTime), "%v != %v", tc.expVi.CreationTime, vi.CreationTime)
+			}
+		})
+	}
+
+	_, err := FileMetadata{}.VideoInfo()
+	assert.Equal(t, ErrKeyNotFound, err)
+}
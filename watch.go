@@ -0,0 +1,236 @@
+package exiftool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// WatchOption is a configuration function of Watch
+type WatchOption func(*watchConfig) error
+
+type watchConfig struct {
+	interval time.Duration
+	debounce time.Duration
+	dirOpts  []DirOption
+}
+
+// WatchInterval defines how often the directories are scanned (1s by default)
+func WatchInterval(d time.Duration) WatchOption {
+	return func(c *watchConfig) error {
+		if d <= 0 {
+			return fmt.Errorf("invalid interval (%v)", d)
+		}
+		c.interval = d
+		return nil
+	}
+}
+
+// WatchDebounce defines how long the size and modification time of a file must stay
+// unchanged before its metadata is extracted (2s by default), so that files being
+// written aren't read too early
+func WatchDebounce(d time.Duration) WatchOption {
+	return func(c *watchConfig) error {
+		if d < 0 {
+			return fmt.Errorf("invalid debounce (%v)", d)
+		}
+		c.debounce = d
+		return nil
+	}
+}
+
+// WatchFilter only watches the files kept by opts, see ExtractDir
+// Sample :
+//   c, err := e.Watch(ctx, []string{"inbox"}, WatchFilter(Extensions("jpg"), ExcludeGlob(".*")))
+func WatchFilter(opts ...DirOption) WatchOption {
+	return func(c *watchConfig) error {
+		c.dirOpts = append(c.dirOpts, opts...)
+		return nil
+	}
+}
+
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+type pendingFile struct {
+	state fileState
+	since time.Time
+}
+
+// watcher polls directories and detects the files created or modified since the
+// previous scans
+type watcher struct {
+	dirs     []string
+	interval time.Duration
+	debounce time.Duration
+	dir      dirConfig
+	known    map[string]fileState
+	pending  map[string]pendingFile
+}
+
+// newWatcher returns a watcher of dirs, directories of fsys (the OSFileSystem if nil)
+func newWatcher(fsys FileSystem, dirs []string, opts []WatchOption) (*watcher, error) {
+	fsys = orOS(fsys)
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no directory to watch")
+	}
+	for _, d := range dirs {
+		if err := checkFileFS(fsys, d); err != nil {
+			return nil, err
+		}
+	}
+
+	c := watchConfig{interval: time.Second, debounce: 2 * time.Second}
+	for _, opt := range opts {
+		if err := opt(&c); err != nil {
+			return nil, fmt.Errorf("error when configuring watch: %w", err)
+		}
+	}
+
+	dc, err := newDirConfig(c.dirOpts)
+	if err != nil {
+		return nil, err
+	}
+	dc.fsys = fsys
+
+	w := watcher{
+		dirs:     dirs,
+		interval: c.interval,
+		debounce: c.debounce,
+		dir:      dc,
+		pending:  map[string]pendingFile{},
+	}
+	w.known = w.states()
+
+	return &w, nil
+}
+
+// states returns the current state of every watched file. Errors are ignored, as files
+// can legitimately vanish while being walked.
+func (w *watcher) states() map[string]fileState {
+	states := map[string]fileState{}
+	for _, d := range w.dirs {
+		w.dir.walk(d, func(path string, err error) {
+			if err != nil {
+				return
+			}
+			if info, err := w.dir.fsys.Stat(path); err == nil {
+				states[path] = fileState{size: info.Size(), modTime: info.ModTime()}
+			}
+		})
+	}
+	return states
+}
+
+// scan returns, sorted, the files created or modified whose state didn't change for the
+// debounce duration at now
+func (w *watcher) scan(now time.Time) []string {
+	states := w.states()
+
+	var ready []string
+	for path, s := range states {
+		if k, found := w.known[path]; found && k == s {
+			delete(w.pending, path)
+			continue
+		}
+		p, found := w.pending[path]
+		if !found || p.state != s {
+			w.pending[path] = pendingFile{state: s, since: now}
+			if w.debounce > 0 {
+				continue
+			}
+		}
+		if now.Sub(w.pending[path].since) >= w.debounce {
+			ready = append(ready, path)
+			w.known[path] = s
+			delete(w.pending, path)
+		}
+	}
+
+	for path := range w.known {
+		if _, found := states[path]; !found {
+			delete(w.known, path)
+		}
+	}
+	for path := range w.pending {
+		if _, found := states[path]; !found {
+			delete(w.pending, path)
+		}
+	}
+
+	sort.Strings(ready)
+	return ready
+}
+
+// run scans the directories every interval until ctx is done, calling fn with the files
+// to extract
+func (w *watcher) run(ctx context.Context, fn func(files []string)) {
+	t := time.NewTicker(w.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-t.C:
+			if files := w.scan(now); len(files) > 0 {
+				fn(files)
+			}
+		}
+	}
+}
+
+// watch streams the metadata extracted by extract from the files detected by w
+func watch(ctx context.Context, w *watcher, extract func(context.Context, []string) []FileMetadata) <-chan FileMetadata {
+	res := make(chan FileMetadata)
+	go func() {
+		defer close(res)
+		w.run(ctx, func(files []string) {
+			for _, fm := range extract(ctx, files) {
+				select {
+				case res <- fm:
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+	}()
+	return res
+}
+
+// Watch polls dirs recursively and streams the metadata of the files created or
+// modified after the call, once they have been left untouched for the debounce duration
+// (see WatchDebounce). Files already existing when Watch is called are not reported
+// until they are modified. The returned channel is closed once ctx is done. If anything
+// went wrong with dirs or opts, a non empty error will be returned.
+// Sample :
+//   c, err := e.Watch(ctx, []string{"inbox"}, WatchInterval(500*time.Millisecond))
+//   for fm := range c {
+//     ...
+//   }
+func (e *Exiftool) Watch(ctx context.Context, dirs []string, opts ...WatchOption) (<-chan FileMetadata, error) {
+	w, err := newWatcher(e.fsys, dirs, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return watch(ctx, w, func(ctx context.Context, files []string) []FileMetadata {
+		return e.ExtractContext(ctx, files)
+	}), nil
+}
+
+// Watch behaves like Exiftool.Watch, dispatching the files detected during a scan across
+// the workers of the pool
+func (p *Pool) Watch(ctx context.Context, dirs []string, opts ...WatchOption) (<-chan FileMetadata, error) {
+	w, err := newWatcher(p.fsys, dirs, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return watch(ctx, w, func(ctx context.Context, files []string) []FileMetadata {
+		return p.ExtractContext(ctx, files)
+	}), nil
+}
@@ -0,0 +1,147 @@
+package exiftool
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeWatchedFile atomically creates the file f, so that it can't be detected while
+// being written
+func writeWatchedFile(t *testing.T, f string, b []byte) {
+	tmp := filepath.Join(filepath.Dir(filepath.Dir(f)), filepath.Base(f)+".tmp")
+	assert.Nil(t, ioutil.WriteFile(tmp, b, 0644))
+	assert.Nil(t, os.Rename(tmp, f))
+}
+
+func TestNewWatcher(t *testing.T) {
+	var tcs = []struct {
+		tcID   string
+		inDirs []string
+		inOpts []WatchOption
+		expOk  bool
+	}{
+		{"default", []string{"./testdata"}, nil, true},
+		{"options", []string{"./testdata"}, []WatchOption{WatchInterval(time.Millisecond), WatchDebounce(0), WatchFilter(Extensions("jpg"))}, true},
+		{"noDir", nil, nil, false},
+		{"nonExisting", []string{"./testdata/nonExisting"}, nil, false},
+		{"invalidInterval", []string{"./testdata"}, []WatchOption{WatchInterval(0)}, false},
+		{"invalidDebounce", []string{"./testdata"}, []WatchOption{WatchDebounce(-time.Second)}, false},
+		{"invalidFilter", []string{"./testdata"}, []WatchOption{WatchFilter(IncludeGlob("["))}, false},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			_, err := newWatcher(nil, tc.inDirs, tc.inOpts)
+			assert.Equal(t, tc.expOk, err == nil)
+		})
+	}
+}
+
+func TestWatcherScan(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-exiftool")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	existing := filepath.Join(dir, "existing.jpg")
+	assert.Nil(t, ioutil.WriteFile(existing, []byte("a"), 0644))
+
+	w, err := newWatcher(nil, []string{dir}, []WatchOption{WatchDebounce(2 * time.Second), WatchFilter(Extensions("jpg"))})
+	assert.Nil(t, err)
+
+	now := time.Now()
+	assert.Empty(t, w.scan(now))
+
+	created := filepath.Join(dir, "created.jpg")
+	assert.Nil(t, ioutil.WriteFile(created, []byte("a"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "ignored.txt"), []byte("a"), 0644))
+	assert.Empty(t, w.scan(now.Add(time.Second)))
+
+	// still being written
+	assert.Nil(t, ioutil.WriteFile(created, []byte("ab"), 0644))
+	assert.Empty(t, w.scan(now.Add(2*time.Second)))
+	assert.Empty(t, w.scan(now.Add(3*time.Second)))
+	assert.Equal(t, []string{created}, w.scan(now.Add(4*time.Second)))
+	assert.Empty(t, w.scan(now.Add(5*time.Second)))
+
+	assert.Nil(t, ioutil.WriteFile(existing, []byte("modified"), 0644))
+	assert.Empty(t, w.scan(now.Add(6*time.Second)))
+	assert.Equal(t, []string{existing}, w.scan(now.Add(8*time.Second)))
+
+	assert.Nil(t, os.Remove(created))
+	assert.Empty(t, w.scan(now.Add(9*time.Second)))
+	_, found := w.known[created]
+	assert.False(t, found)
+}
+
+func TestWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-exiftool")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	_, err = e.Watch(context.Background(), []string{"./testdata/nonExisting"})
+	assert.True(t, errors.Is(err, ErrNotExist))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c, err := e.Watch(ctx, []string{dir}, WatchInterval(10*time.Millisecond), WatchDebounce(0))
+	assert.Nil(t, err)
+
+	b, err := ioutil.ReadFile("./testdata/20190404_131804.jpg")
+	assert.Nil(t, err)
+	f := filepath.Join(dir, "a.jpg")
+	writeWatchedFile(t, f, b)
+
+	fm := <-c
+	assert.Equal(t, f, fm.File)
+	assert.Nil(t, fm.Err)
+
+	cancel()
+	for range c {
+	}
+}
+
+func TestPoolWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-exiftool")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	p, err := NewPool(2)
+	assert.Nil(t, err)
+	defer p.Close()
+
+	_, err = p.Watch(context.Background(), nil)
+	assert.NotNil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c, err := p.Watch(ctx, []string{dir}, WatchInterval(10*time.Millisecond), WatchDebounce(0))
+	assert.Nil(t, err)
+
+	b, err := ioutil.ReadFile("./testdata/20190404_131804.jpg")
+	assert.Nil(t, err)
+	files := map[string]bool{filepath.Join(dir, "a.jpg"): true, filepath.Join(dir, "b.jpg"): true}
+	for f := range files {
+		writeWatchedFile(t, f, b)
+	}
+
+	for len(files) > 0 {
+		fm := <-c
+		assert.Nil(t, fm.Err)
+		assert.True(t, files[fm.File], "unexpected file %v", fm.File)
+		delete(files, fm.File)
+	}
+
+	cancel()
+	for range c {
+	}
+}
@@ -0,0 +1,63 @@
+package exiftool
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrConditionNotMet is a sentinel error used when a file doesn't match the conditions
+// of an extraction, see Where
+var ErrConditionNotMet = errors.New("file does not match condition")
+
+// Where only extracts the files matching condition (activates Exiftool's '-if'
+// parameter, see https://exiftool.org/exiftool_pod.html#if-NUM-EXPR), the other ones
+// getting ErrConditionNotMet. Several conditions must all be met.
+// Sample :
+//   fms := e.Extract(files, Where("$ISO > 1600"), Where("$Make eq 'Canon'"))
+func Where(condition string) ExtractOption {
+	return func(c *extractConfig) error {
+		if err := checkCondition(condition); err != nil {
+			return err
+		}
+		c.conditions = append(c.conditions, condition)
+		return nil
+	}
+}
+
+// DirWhere only sends the files of the directory matching condition, the other ones
+// being skipped by exiftool, see Where
+// Sample :
+//   c, err := e.ExtractDir("photos", DirWhere("$ISO > 1600"))
+func DirWhere(condition string) DirOption {
+	return func(c *dirConfig) error {
+		if err := checkCondition(condition); err != nil {
+			return err
+		}
+		c.conditions = append(c.conditions, condition)
+		return nil
+	}
+}
+
+func checkCondition(condition string) error {
+	if strings.TrimSpace(condition) == "" || strings.ContainsAny(condition, "\r\n") {
+		return fmt.Errorf("invalid condition (%q)", condition)
+	}
+	return nil
+}
+
+// conditionArgs returns the arguments of conditions
+func conditionArgs(conditions []string) []string {
+	var args []string
+	for _, c := range conditions {
+		args = append(args, "-if", c)
+	}
+	return args
+}
+
+// failedCondition returns true if out, the output of an extraction, reports that the
+// file didn't match the conditions
+func failedCondition(out []byte) bool {
+	return !bytes.Contains(out, []byte("[")) && bytes.Contains(out, []byte("failed condition"))
+}
@@ -0,0 +1,112 @@
+package exiftool
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWhere(t *testing.T) {
+	var tcs = []struct {
+		tcID    string
+		in      []string
+		expOk   bool
+		expArgs []string
+	}{
+		{"one", []string{"$ISO > 1600"}, true, []string{"-j", "-g", "-if", "$ISO > 1600"}},
+		{"several", []string{"$ISO > 1600", "$Make eq 'Canon'"}, true, []string{"-j", "-g", "-if", "$ISO > 1600", "-if", "$Make eq 'Canon'"}},
+		{"empty", []string{" "}, false, nil},
+		{"lineBreak", []string{"$ISO\n> 1600"}, false, nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			var opts []ExtractOption
+			for _, c := range tc.in {
+				opts = append(opts, Where(c))
+			}
+			e := Exiftool{}
+			cfg, err := e.newExtractConfig(opts)
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.expArgs, e.extractArgs(cfg))
+			}
+
+			var dopts []DirOption
+			for _, c := range tc.in {
+				dopts = append(dopts, DirWhere(c))
+			}
+			dc, err := newDirConfig(dopts)
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.in, dc.conditions)
+				assert.Equal(t, len(tc.in), len(dc.extractOptions()))
+			}
+		})
+	}
+}
+
+func TestFailedCondition(t *testing.T) {
+	var tcs = []struct {
+		tcID      string
+		in        string
+		expFailed bool
+	}{
+		{"failed", "    1 files failed condition\n", true},
+		{"matched", "[{\"SourceFile\":\"a.jpg\"}]\n", false},
+		{"other", "Error: File not found - a.jpg\n", false},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			assert.Equal(t, tc.expFailed, failedCondition([]byte(tc.in)))
+		})
+	}
+}
+
+func TestExtractWhere(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	fms := e.Extract([]string{"./testdata/20190404_131804.jpg"}, Where("$Make eq 'samsung'"))
+	assert.Equal(t, 1, len(fms))
+	assert.Nil(t, fms[0].Err)
+
+	fms = e.Extract([]string{"./testdata/20190404_131804.jpg"}, Where("$Make eq 'Canon'"))
+	assert.Equal(t, 1, len(fms))
+	assert.Equal(t, ErrConditionNotMet, fms[0].Err)
+}
+
+func TestExtractDirWhere(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	c, err := e.ExtractDir("./testdata", Extensions("jpg"), DirWhere("$Make eq 'samsung'"))
+	assert.Nil(t, err)
+
+	files := []string{}
+	for fm := range c {
+		assert.Nil(t, fm.Err)
+		files = append(files, filepath.Base(fm.File))
+	}
+	assert.Equal(t, []string{"20190404_131804.jpg"}, files)
+
+	p, err := NewPool(2)
+	assert.Nil(t, err)
+	defer p.Close()
+
+	c, err = p.ExtractDir("./testdata", Extensions("jpg"), DirWhere("$Make eq 'samsung'"))
+	assert.Nil(t, err)
+
+	files = []string{}
+	for fm := range c {
+		assert.Nil(t, fm.Err)
+		files = append(files, filepath.Base(fm.File))
+	}
+	assert.Equal(t, []string{"20190404_131804.jpg"}, files)
+}
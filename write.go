@@ -0,0 +1,238 @@
+package exiftool
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Write writes values into the tags of file, each value being mapped to a -TAG=VALUE
+// argument. Labels can be prefixed by a group (ie. "XMP:Title"). A nil value deletes
+// the tag and a []interface{} value sets every item of a list tag. If anything went
+// wrong, a non empty error will be returned.
+// Sample :
+//   err := e.Write("photo.jpg", FileMetadataValues{{"Artist", "me"}, {"XMP:Subject", []interface{}{"a", "b"}}})
+func (e *Exiftool) Write(file string, values FileMetadataValues) error {
+	if err := e.checkFile(file); err != nil {
+		return err
+	}
+
+	args, err := writeArgs(values)
+	if err != nil {
+		return err
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if err := e.checkWritable(values); err != nil {
+		return err
+	}
+
+	out, err := e.executeWriteFiles(args, file)
+	if err != nil {
+		return err
+	}
+
+	return checkWriteOutput(file, out)
+}
+
+func writeArgs(values FileMetadataValues) ([]string, error) {
+	var args []string
+
+	for _, v := range values {
+		if v.Label == "" {
+			return nil, fmt.Errorf("empty label")
+		}
+
+		var vals []string
+		switch val := v.Value.(type) {
+		case nil:
+			vals = []string{""}
+		case []interface{}:
+			for _, item := range val {
+				vals = append(vals, toString(item))
+			}
+		case []string:
+			vals = val
+		default:
+			vals = []string{toString(val)}
+		}
+
+		for _, val := range vals {
+			if strings.ContainsAny(val, "\r\n") {
+				return nil, fmt.Errorf("line breaks are not supported (%v)", v.Label)
+			}
+			args = append(args, fmt.Sprintf("-%v=%v", v.Label, val))
+		}
+	}
+
+	return args, nil
+}
+
+// executeWrite runs a writing command made of args and file, and returns an error if
+// exiftool reported one while writing file
+func (e *Exiftool) executeWrite(file string, args []string) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	out, err := e.executeWriteFiles(args, file)
+	if err != nil {
+		return err
+	}
+
+	return checkWriteOutput(file, out)
+}
+
+// checkWriteOutput returns an error if exiftool reported one while writing file, or a
+// FileUnchangedError if no file was updated
+func checkWriteOutput(file string, out []byte) error {
+	for _, l := range strings.Split(string(out), "\n") {
+		l = strings.TrimSpace(l)
+		if !strings.HasPrefix(l, "Error") {
+			continue
+		}
+		msg := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(l, "Error"), ":"))
+		msg = strings.TrimSuffix(msg, " - "+file)
+		if err := exiftoolError(file, msg); err != nil {
+			return err
+		}
+		return fmt.Errorf("error while writing: %v", l)
+	}
+
+	for _, l := range strings.Split(string(out), "\n") {
+		if l = strings.TrimSpace(l); l == unchangedMessage {
+			return &FileUnchangedError{File: file, Message: l}
+		}
+	}
+
+	return nil
+}
+
+// Delete removes tags from file (-TAG=). Tags can be prefixed by a group, and group
+// wildcards are supported by exiftool (ie. "XMP:all"). If anything went wrong, a non
+// empty error will be returned.
+// Sample :
+//   err := e.Delete("photo.jpg", "GPS:all", "Artist")
+func (e *Exiftool) Delete(file string, tags ...string) error {
+	if len(tags) == 0 {
+		return fmt.Errorf("no tag to delete")
+	}
+
+	values := make(FileMetadataValues, len(tags))
+	for i, t := range tags {
+		values[i] = FileMetadataValue{Label: t}
+	}
+
+	return e.Write(file, values)
+}
+
+// StripAll removes every writable tag from file (-all=). If anything went wrong, a non
+// empty error will be returned.
+func (e *Exiftool) StripAll(file string) error {
+	return e.Delete(file, "all")
+}
+
+// StripGPS removes the GPS tags from file: the EXIF GPS directory (-GPS:all=) and the
+// XMP GPS tags (-XMP:Geotag=), the other tags being kept. If anything went wrong, a non
+// empty error will be returned.
+func (e *Exiftool) StripGPS(file string) error {
+	return e.Delete(file, "GPS:all", "XMP:Geotag")
+}
+
+// StripThumbnail removes the EXIF thumbnail from file, along with the EXIF directory
+// describing it (-IFD1:all=). If anything went wrong, a non empty error will be
+// returned.
+func (e *Exiftool) StripThumbnail(file string) error {
+	return e.Delete(file, "IFD1:all")
+}
+
+// StripXMP removes every XMP tag from file (-XMP:all=). If anything went wrong, a non
+// empty error will be returned.
+func (e *Exiftool) StripXMP(file string) error {
+	return e.Delete(file, "XMP:all")
+}
+
+// StripIPTC removes every IPTC tag from file (-IPTC:all=). If anything went wrong, a non
+// empty error will be returned.
+func (e *Exiftool) StripIPTC(file string) error {
+	return e.Delete(file, "IPTC:all")
+}
+
+// CopyTags copies tags from src to dst (activates Exiftool's '-tagsFromFile' parameter).
+// Tags can be prefixed by a group (ie. "GPS:all"), every tag is copied if none is
+// provided. If anything went wrong, a non empty error will be returned.
+// Sample :
+//   err := e.CopyTags("raw.cr2", "export.jpg", "EXIF:all", "GPS:all")
+func (e *Exiftool) CopyTags(src, dst string, tags ...string) error {
+	for _, f := range []string{src, dst} {
+		if err := e.checkFile(f); err != nil {
+			return err
+		}
+	}
+
+	args := []string{"-tagsFromFile", src}
+	for _, t := range tags {
+		args = append(args, "-"+t)
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	out, err := e.executeWriteFiles(args, dst)
+	if err != nil {
+		return err
+	}
+
+	return checkWriteOutput(dst, out)
+}
+
+// ShiftDates shifts the date/time tags of file by delta (-TAG+=SHIFT or -TAG-=SHIFT),
+// which is typically used to fix a wrong camera clock. Every date/time tag is shifted
+// (AllDates) if none is provided. If anything went wrong, a non empty error will be
+// returned.
+// Sample :
+//   err := e.ShiftDates("photo.jpg", -90*time.Minute)
+func (e *Exiftool) ShiftDates(file string, delta time.Duration, tags ...string) error {
+	if delta == 0 {
+		return fmt.Errorf("no shift")
+	}
+	if err := e.checkFile(file); err != nil {
+		return err
+	}
+
+	if len(tags) == 0 {
+		tags = []string{"AllDates"}
+	}
+
+	op, shift := shiftArg(delta)
+	args := make([]string, 0, len(tags)+1)
+	for _, t := range tags {
+		if t == "" {
+			return fmt.Errorf("empty tag")
+		}
+		args = append(args, fmt.Sprintf("-%v%v=%v", t, op, shift))
+	}
+
+	return e.executeWrite(file, args)
+}
+
+// shiftArg converts d into an exiftool shift operator ("+" or "-") and a "Y:M:D h:m:s"
+// shift. Durations have no calendar semantics, hence years and months are always 0.
+func shiftArg(d time.Duration) (string, string) {
+	op := "+"
+	if d < 0 {
+		op, d = "-", -d
+	}
+
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	h := d / time.Hour
+	d -= h * time.Hour
+	m := d / time.Minute
+	d -= m * time.Minute
+	sec := strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+
+	return op, fmt.Sprintf("0:0:%d %d:%d:%v", days, h, m, sec)
+}
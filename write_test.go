@@ -0,0 +1,277 @@
+package exiftool
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// copyTestFile copies a testdata file into a temporary directory and returns the path
+// of the copy along with a cleaning function
+func copyTestFile(t *testing.T, src string) (string, func()) {
+	dir, err := ioutil.TempDir("", "go-exiftool")
+	assert.Nil(t, err)
+
+	b, err := ioutil.ReadFile(src)
+	assert.Nil(t, err)
+
+	dst := filepath.Join(dir, filepath.Base(src))
+	assert.Nil(t, ioutil.WriteFile(dst, b, 0644))
+
+	return dst, func() { os.RemoveAll(dir) }
+}
+
+func TestWriteArgs(t *testing.T) {
+	var tcs = []struct {
+		tcID    string
+		in      FileMetadataValues
+		expOk   bool
+		expArgs []string
+	}{
+		{"string", FileMetadataValues{{"Artist", "me"}}, true, []string{"-Artist=me"}},
+		{"group", FileMetadataValues{{"XMP:Title", "t"}}, true, []string{"-XMP:Title=t"}},
+		{"float", FileMetadataValues{{"FNumber", float64(1.7)}}, true, []string{"-FNumber=1.7"}},
+		{"int", FileMetadataValues{{"ISO", int64(100)}}, true, []string{"-ISO=100"}},
+		{"nil", FileMetadataValues{{"Artist", nil}}, true, []string{"-Artist="}},
+		{"list", FileMetadataValues{{"Keywords", []interface{}{"a", "b"}}}, true, []string{"-Keywords=a", "-Keywords=b"}},
+		{"strings", FileMetadataValues{{"Keywords", []string{"a", "b"}}}, true, []string{"-Keywords=a", "-Keywords=b"}},
+		{"multiple", FileMetadataValues{{"Artist", "me"}, {"ISO", int64(100)}}, true, []string{"-Artist=me", "-ISO=100"}},
+		{"emptyLabel", FileMetadataValues{{"", "me"}}, false, nil},
+		{"lineBreak", FileMetadataValues{{"Artist", "a\nb"}}, false, nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			args, err := writeArgs(tc.in)
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.expOk {
+				assert.Equal(t, tc.expArgs, args)
+			}
+		})
+	}
+}
+
+func TestCheckWriteOutput(t *testing.T) {
+	var tcs = []struct {
+		tcID  string
+		in    string
+		expOk bool
+		check func(error) bool
+	}{
+		{"updated", "    1 image files updated\n", true, nil},
+		{"updatedCRLF", "    1 image files updated\r\n", true, nil},
+		{"notFound", "Error: File not found - a.jpg\n", false, func(err error) bool {
+			return errors.Is(err, ErrNotExist)
+		}},
+		{"minor", "Error: [minor] Bad MakerNotes directory - a.jpg\n", false, func(err error) bool {
+			var w *ExiftoolMinorWarning
+			return errors.As(err, &w) && w.Message == "Bad MakerNotes directory" && w.File == "a.jpg"
+		}},
+		{"unsupported", "Error: Unknown file type - a.jpg\n", false, func(err error) bool {
+			var u *UnsupportedFormatError
+			return errors.As(err, &u)
+		}},
+		{"other", "Error: Can't write - a.jpg\n", false, nil},
+		{"unchanged", "    0 image files updated\n    1 image files unchanged\n", false, func(err error) bool {
+			var u *FileUnchangedError
+			return errors.As(err, &u) && u.File == "a.jpg"
+		}},
+		{"created", "    1 image files created\n", true, nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			err := checkWriteOutput("a.jpg", []byte(tc.in))
+			assert.Equal(t, tc.expOk, err == nil)
+			if tc.check != nil {
+				assert.True(t, tc.check(err), "unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	assert.Nil(t, e.Write(f, FileMetadataValues{{"Artist", "go-exiftool"}}))
+
+	fms := e.ExtractMetadata(f)
+	assert.Equal(t, 1, len(fms))
+	assert.Nil(t, fms[0].Err)
+	artist, err := fms[0].Groups["EXIF"].GetString("Artist")
+	assert.Nil(t, err)
+	assert.Equal(t, "go-exiftool", artist)
+}
+
+func TestWriteNonExisting(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	err = e.Write("./testdata/nonExisting", FileMetadataValues{{"Artist", "a"}})
+	assert.True(t, errors.Is(err, ErrNotExist))
+}
+
+func TestDelete(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	assert.NotNil(t, e.Delete(f))
+	assert.Nil(t, e.Delete(f, "Make", "EXIF:Model"))
+
+	fms := e.ExtractMetadata(f)
+	assert.Equal(t, 1, len(fms))
+	assert.Nil(t, fms[0].Err)
+	_, err = fms[0].Groups["EXIF"].GetString("Make")
+	assert.Equal(t, ErrKeyNotFound, err)
+	_, err = fms[0].Groups["EXIF"].GetString("Model")
+	assert.Equal(t, ErrKeyNotFound, err)
+	_, err = fms[0].Groups["EXIF"].GetString("DateTimeOriginal")
+	assert.Nil(t, err)
+}
+
+func TestStripAll(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	assert.Nil(t, e.StripAll(f))
+
+	fms := e.ExtractMetadata(f)
+	assert.Equal(t, 1, len(fms))
+	assert.Nil(t, fms[0].Err)
+	_, found := fms[0].Groups["EXIF"]
+	assert.False(t, found)
+}
+
+func TestStripGroups(t *testing.T) {
+	var tcs = []struct {
+		tcID    string
+		strip   func(e *Exiftool, file string) error
+		values  FileMetadataValues
+		absent  string
+		present string
+	}{
+		{"gps", (*Exiftool).StripGPS, FileMetadataValues{{"GPSLatitude", "48.8"}, {"GPSLatitudeRef", "N"}}, "GPS", "EXIF"},
+		{"thumbnail", (*Exiftool).StripThumbnail, nil, "IFD1", "IFD0"},
+		{"xmp", (*Exiftool).StripXMP, FileMetadataValues{{"XMP:Title", "t"}}, "XMP-dc", "IFD0"},
+		{"iptc", (*Exiftool).StripIPTC, FileMetadataValues{{"IPTC:Keywords", "k"}}, "IPTC", "IFD0"},
+	}
+
+	e, err := NewExiftool(GroupFamily(1))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+			defer clean()
+
+			if tc.values != nil {
+				assert.Nil(t, e.Write(f, tc.values))
+			}
+			assert.True(t, errors.Is(tc.strip(e, "./testdata/nonExisting"), ErrNotExist))
+			assert.Nil(t, tc.strip(e, f))
+
+			fms := e.ExtractMetadata(f)
+			assert.Equal(t, 1, len(fms))
+			assert.Nil(t, fms[0].Err)
+			_, found := fms[0].Groups[tc.absent]
+			assert.False(t, found)
+			_, found = fms[0].Groups[tc.present]
+			assert.True(t, found)
+		})
+	}
+}
+
+func TestCopyTags(t *testing.T) {
+	dst, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	assert.Nil(t, e.StripAll(dst))
+	assert.Nil(t, e.CopyTags("./testdata/20190404_131804.jpg", dst, "Make", "EXIF:Model"))
+
+	fms := e.ExtractMetadata(dst)
+	assert.Equal(t, 1, len(fms))
+	assert.Nil(t, fms[0].Err)
+	mk, err := fms[0].Groups["EXIF"].GetString("Make")
+	assert.Nil(t, err)
+	assert.Equal(t, "samsung", mk)
+	model, err := fms[0].Groups["EXIF"].GetString("Model")
+	assert.Nil(t, err)
+	assert.Equal(t, "SM-G930F", model)
+	_, err = fms[0].Groups["EXIF"].GetString("DateTimeOriginal")
+	assert.Equal(t, ErrKeyNotFound, err)
+
+	err = e.CopyTags("./testdata/nonExisting", dst)
+	assert.True(t, errors.Is(err, ErrNotExist))
+	err = e.CopyTags("./testdata/20190404_131804.jpg", "./testdata/nonExisting")
+	assert.True(t, errors.Is(err, ErrNotExist))
+}
+
+func TestShiftArg(t *testing.T) {
+	var tcs = []struct {
+		tcID     string
+		in       time.Duration
+		expOp    string
+		expShift string
+	}{
+		{"hour", time.Hour, "+", "0:0:0 1:0:0"},
+		{"negative", -90 * time.Minute, "-", "0:0:0 1:30:0"},
+		{"days", 49*time.Hour + 2*time.Second, "+", "0:0:2 1:0:2"},
+		{"subSecond", 1500 * time.Millisecond, "+", "0:0:0 0:0:1.5"},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			op, shift := shiftArg(tc.in)
+			assert.Equal(t, tc.expOp, op)
+			assert.Equal(t, tc.expShift, shift)
+		})
+	}
+}
+
+func TestShiftDates(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	assert.NotNil(t, e.ShiftDates(f, 0))
+	assert.True(t, errors.Is(e.ShiftDates("./testdata/nonExisting", time.Hour), ErrNotExist))
+	assert.Nil(t, e.ShiftDates(f, 25*time.Hour+30*time.Minute))
+
+	fms := e.ExtractMetadata(f)
+	assert.Equal(t, 1, len(fms))
+	assert.Nil(t, fms[0].Err)
+	d, err := fms[0].Groups["EXIF"].GetString("DateTimeOriginal")
+	assert.Nil(t, err)
+	assert.Equal(t, "2019:04:05 14:48:04", d)
+}
@@ -0,0 +1,240 @@
+package exiftool
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	keepOriginal = iota
+	overwriteOriginal
+	backupDir
+	atomicWrite
+)
+
+// WritePolicy defines how the write operations (Write, Delete, CopyTags, ShiftDates,
+// Geotag...) deal with the original file, see SetWritePolicy
+type WritePolicy struct {
+	mode int
+	dir  string
+}
+
+// KeepOriginal is exiftool's default policy: the original file is kept next to the
+// written one, with the "_original" suffix
+func KeepOriginal() WritePolicy {
+	return WritePolicy{mode: keepOriginal}
+}
+
+// OverwriteOriginal overwrites the file without keeping any backup (activates Exiftool's
+// '-overwrite_original' parameter)
+func OverwriteOriginal() WritePolicy {
+	return WritePolicy{mode: overwriteOriginal}
+}
+
+// BackupDir copies the original file into dir (which must exist) before overwriting it,
+// under its absolute path so that files of the same name don't collide (ie.
+// /photos/a.jpg being backed up as dir/photos/a.jpg). As exiftool does with "_original"
+// files, an existing backup is never replaced, hence dir keeps the very first version of
+// each file.
+func BackupDir(dir string) WritePolicy {
+	return WritePolicy{mode: backupDir, dir: dir}
+}
+
+// AtomicWrite makes exiftool write into a temporary file of the directory of the
+// original one, which then replaces the original file by a rename: readers never see a
+// partially written file and no backup is kept.
+func AtomicWrite() WritePolicy {
+	return WritePolicy{mode: atomicWrite}
+}
+
+// SetWritePolicy sets the policy of the write operations, KeepOriginal being the default.
+// The backups and the replacements of files go through the FileSystem of the Exiftool,
+// the directory of BackupDir being checked with it: SetFileSystem must hence be provided
+// before SetWritePolicy.
+// Sample :
+//   e, err := NewExiftool(SetWritePolicy(BackupDir("/backups")))
+func SetWritePolicy(p WritePolicy) Option {
+	return func(e *Exiftool) error {
+		if p.mode == backupDir {
+			fi, err := e.fileSystem().Stat(p.dir)
+			if err != nil {
+				return fmt.Errorf("error while checking backup directory: %w", err)
+			}
+			if !fi.IsDir() {
+				return fmt.Errorf("backup directory is not a directory (%v)", p.dir)
+			}
+		}
+		e.policy = p
+		return nil
+	}
+}
+
+// executeWriteFiles sends the writing command made of args and files to exiftool,
+// according to the write policy. The caller must hold e.lock.
+func (e *Exiftool) executeWriteFiles(args []string, files ...string) ([]byte, error) {
+	switch e.policy.mode {
+	case overwriteOriginal:
+		return e.executeWriting(append(append([]string{"-overwrite_original"}, args...), files...)...)
+	case backupDir:
+		if e.dryRun == nil {
+			for _, f := range files {
+				if err := backupFile(e.fileSystem(), f, e.policy.dir); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return e.executeWriting(append(append([]string{"-overwrite_original"}, args...), files...)...)
+	case atomicWrite:
+		var out []byte
+		for _, f := range files {
+			o, err := e.executeAtomicWrite(args, f)
+			out = append(out, o...)
+			if err != nil {
+				return out, err
+			}
+		}
+		return out, nil
+	default:
+		return e.executeWriting(append(append([]string{}, args...), files...)...)
+	}
+}
+
+// executeAtomicWrite writes file into a temporary file (-o) that then replaces file,
+// with the mode of file
+func (e *Exiftool) executeAtomicWrite(args []string, file string) ([]byte, error) {
+	fsys := e.fileSystem()
+	fi, err := fsys.Stat(file)
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := tempPath(fsys, file)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := e.executeWriting(append(append([]string{}, args...), "-o", tmp, file)...)
+	if _, statErr := fsys.Stat(tmp); statErr != nil {
+		// nothing written (error, dry-run...)
+		return out, err
+	}
+	if err != nil {
+		fsys.Remove(tmp)
+		return out, err
+	}
+
+	if err := replaceFile(fsys, tmp, file, fi.Mode()); err != nil {
+		return out, err
+	}
+
+	return out, nil
+}
+
+// replaceFile replaces file by tmp through fsys, tmp getting mode beforehand. tmp is
+// removed if anything went wrong.
+func replaceFile(fsys FileSystem, tmp, file string, mode os.FileMode) error {
+	if err := fsys.Chmod(tmp, mode); err != nil {
+		fsys.Remove(tmp)
+		return fmt.Errorf("error while replacing %v: %w", file, err)
+	}
+	if err := fsys.Rename(tmp, file); err != nil {
+		fsys.Remove(tmp)
+		return fmt.Errorf("error while replacing %v: %w", file, err)
+	}
+	return nil
+}
+
+// tempPath returns the path of a non existing temporary file of fsys in the directory of
+// file, with the same extension since exiftool infers the output format from it
+func tempPath(fsys FileSystem, file string) (string, error) {
+	ext := filepath.Ext(file)
+	base := strings.TrimSuffix(filepath.Base(file), ext)
+	f, err := fsys.TempFile(filepath.Dir(file), "."+base+"-*"+ext)
+	if err != nil {
+		return "", fmt.Errorf("error while creating temporary file: %w", err)
+	}
+	f.Close()
+
+	// exiftool refuses to write into an existing file
+	if err := fsys.Remove(f.Name()); err != nil {
+		return "", fmt.Errorf("error while creating temporary file: %w", err)
+	}
+
+	return f.Name(), nil
+}
+
+// backupOriginal backs file up as exiftool would under the write policy, for files
+// replaced without going through exiftool
+func (e *Exiftool) backupOriginal(file string) error {
+	switch e.policy.mode {
+	case keepOriginal:
+		return copyOriginal(e.fileSystem(), file, file+"_original")
+	case backupDir:
+		return backupFile(e.fileSystem(), file, e.policy.dir)
+	}
+	return nil
+}
+
+// backupFile copies file into dir, an existing directory of fsys (see backupPath), unless
+// it has already been backed up
+func backupFile(fsys FileSystem, file, dir string) error {
+	if fi, err := fsys.Stat(dir); err != nil || !fi.IsDir() {
+		return fmt.Errorf("invalid backup directory (%v)", dir)
+	}
+	dst, err := backupPath(dir, file)
+	if err != nil {
+		return err
+	}
+	if err := fsys.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("error while creating backup directory of %v: %w", file, err)
+	}
+	return copyOriginal(fsys, file, dst)
+}
+
+// backupPath returns the path of the backup of file in dir, the absolute path of file
+// (volume included) being mirrored under dir
+func backupPath(dir, file string) (string, error) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return "", fmt.Errorf("error while resolving %v for backup: %w", file, err)
+	}
+	vol := filepath.VolumeName(abs)
+	return filepath.Join(dir, strings.Trim(vol, `\/:`), abs[len(vol):]), nil
+}
+
+// copyOriginal copies file to dst through fsys, unless dst already exists
+func copyOriginal(fsys FileSystem, file, dst string) error {
+	if _, err := fsys.Stat(dst); err == nil {
+		return nil
+	}
+
+	fi, err := fsys.Stat(file)
+	if err != nil {
+		return fmt.Errorf("error while opening %v for backup: %w", file, err)
+	}
+	src, err := fsys.Open(file)
+	if err != nil {
+		return fmt.Errorf("error while opening %v for backup: %w", file, err)
+	}
+	defer src.Close()
+
+	w, err := fsys.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, fi.Mode().Perm())
+	if err != nil {
+		return fmt.Errorf("error while creating backup of %v: %w", file, err)
+	}
+
+	if _, err := io.Copy(w, src); err != nil {
+		w.Close()
+		fsys.Remove(dst)
+		return fmt.Errorf("error while copying backup of %v: %w", file, err)
+	}
+
+	if err := w.Close(); err != nil {
+		fsys.Remove(dst)
+		return fmt.Errorf("error while copying backup of %v: %w", file, err)
+	}
+
+	return nil
+}
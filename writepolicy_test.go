@@ -0,0 +1,316 @@
+package exiftool
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetWritePolicy(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+
+	var tcs = []struct {
+		tcID    string
+		in      WritePolicy
+		expOk   bool
+		expArgs []string
+	}{
+		{"keep", KeepOriginal(), true, []string{"-Artist=me", f}},
+		{"overwrite", OverwriteOriginal(), true, []string{"-overwrite_original", "-Artist=me", f}},
+		{"backup", BackupDir(filepath.Dir(f)), true, []string{"-overwrite_original", "-Artist=me", f}},
+		{"backupNonExisting", BackupDir("./testdata/nonExisting"), false, nil},
+		{"backupFile", BackupDir(f), false, nil},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			var r CommandRecorder
+			e, err := NewExiftool(SetWritePolicy(tc.in), DryRun(&r))
+			assert.Equal(t, tc.expOk, err == nil)
+			if !tc.expOk {
+				return
+			}
+			defer e.Close()
+
+			assert.Nil(t, e.Write(f, FileMetadataValues{{"Artist", "me"}}))
+			cmds := r.Commands()
+			assert.Equal(t, 1, len(cmds))
+			args := cmds[0][len(cmds[0])-len(tc.expArgs):]
+			assert.Equal(t, tc.expArgs, args)
+		})
+	}
+}
+
+func TestAtomicWriteDryRun(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+
+	var r CommandRecorder
+	e, err := NewExiftool(SetWritePolicy(AtomicWrite()), DryRun(&r))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	assert.Nil(t, e.Write(f, FileMetadataValues{{"Artist", "me"}}))
+	cmds := r.Commands()
+	assert.Equal(t, 1, len(cmds))
+	args := cmds[0][len(cmds[0])-4:]
+	assert.Equal(t, "-Artist=me", args[0])
+	assert.Equal(t, "-o", args[1])
+	assert.Equal(t, filepath.Dir(f), filepath.Dir(args[2]))
+	assert.Equal(t, ".jpg", filepath.Ext(args[2]))
+	assert.Equal(t, f, args[3])
+
+	_, err = os.Stat(args[2])
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestTempPath(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+
+	p, err := tempPath(OSFileSystem{}, f)
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Dir(f), filepath.Dir(p))
+	assert.True(t, strings.HasPrefix(filepath.Base(p), ".20190404_131804-"))
+	assert.Equal(t, ".jpg", filepath.Ext(p))
+	_, err = os.Stat(p)
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = tempPath(OSFileSystem{}, "./testdata/nonExisting/a.jpg")
+	assert.NotNil(t, err)
+}
+
+func TestBackupFile(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+	dir, err := ioutil.TempDir("", "go-exiftool")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.Nil(t, backupFile(OSFileSystem{}, f, dir))
+	orig, err := ioutil.ReadFile(f)
+	assert.Nil(t, err)
+	dst, err := backupPath(dir, f)
+	assert.Nil(t, err)
+	b, err := ioutil.ReadFile(dst)
+	assert.Nil(t, err)
+	assert.Equal(t, orig, b)
+
+	// the first backup is kept
+	assert.Nil(t, ioutil.WriteFile(f, []byte("updated"), 0644))
+	assert.Nil(t, backupFile(OSFileSystem{}, f, dir))
+	b, err = ioutil.ReadFile(dst)
+	assert.Nil(t, err)
+	assert.Equal(t, orig, b)
+
+	// a file of the same name from another directory gets its own backup
+	f2, clean2 := copyTestFile(t, "./testdata/empty.jpg")
+	defer clean2()
+	same := filepath.Join(filepath.Dir(f2), filepath.Base(f))
+	assert.Nil(t, os.Rename(f2, same))
+	assert.Nil(t, backupFile(OSFileSystem{}, same, dir))
+	dst2, err := backupPath(dir, same)
+	assert.Nil(t, err)
+	assert.NotEqual(t, dst, dst2)
+	orig2, err := ioutil.ReadFile(same)
+	assert.Nil(t, err)
+	b, err = ioutil.ReadFile(dst2)
+	assert.Nil(t, err)
+	assert.Equal(t, orig2, b)
+	b, err = ioutil.ReadFile(dst)
+	assert.Nil(t, err)
+	assert.Equal(t, orig, b)
+
+	assert.NotNil(t, backupFile(OSFileSystem{}, "./testdata/nonExisting", dir))
+	assert.NotNil(t, backupFile(OSFileSystem{}, f, "./testdata/nonExisting"))
+	_, err = os.Stat("./testdata/nonExisting")
+	assert.True(t, os.IsNotExist(err))
+	assert.NotNil(t, backupFile(OSFileSystem{}, f, "./testdata/20190404_131804.jpg"))
+}
+
+func TestBackupPath(t *testing.T) {
+	wd, err := os.Getwd()
+	assert.Nil(t, err)
+	vol := filepath.VolumeName(wd)
+	root := filepath.Join("backups", strings.Trim(vol, `\/:`))
+
+	var tcs = []struct {
+		tcID   string
+		inFile string
+		expDst string
+	}{
+		{"absolute", "/photos/a.jpg", filepath.Join(root, "photos", "a.jpg")},
+		{"relative", "photos/a.jpg", filepath.Join(root, wd[len(vol):], "photos", "a.jpg")},
+		{"parent", "../a.jpg", filepath.Join(root, wd[len(vol):], "..", "a.jpg")},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			dst, err := backupPath("backups", tc.inFile)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expDst, dst)
+		})
+	}
+}
+
+func TestWritePolicies(t *testing.T) {
+	backups, err := ioutil.TempDir("", "go-exiftool")
+	assert.Nil(t, err)
+	defer os.RemoveAll(backups)
+
+	var tcs = []struct {
+		tcID      string
+		in        WritePolicy
+		expBackup string
+	}{
+		{"keep", KeepOriginal(), "_original"},
+		{"overwrite", OverwriteOriginal(), ""},
+		{"backup", BackupDir(backups), backups},
+		{"atomic", AtomicWrite(), ""},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+			defer clean()
+
+			e, err := NewExiftool(SetWritePolicy(tc.in))
+			assert.Nil(t, err)
+			defer e.Close()
+
+			assert.Nil(t, e.Write(f, FileMetadataValues{{"Artist", "go-exiftool"}}))
+			fms := e.ExtractMetadata(f)
+			assert.Nil(t, fms[0].Err)
+			artist, err := fms[0].Groups["EXIF"].GetString("Artist")
+			assert.Nil(t, err)
+			assert.Equal(t, "go-exiftool", artist)
+
+			entries, err := ioutil.ReadDir(filepath.Dir(f))
+			assert.Nil(t, err)
+			var names []string
+			for _, en := range entries {
+				names = append(names, en.Name())
+			}
+			if tc.expBackup == "_original" {
+				assert.ElementsMatch(t, []string{filepath.Base(f), filepath.Base(f) + "_original"}, names)
+				return
+			}
+			assert.Equal(t, []string{filepath.Base(f)}, names)
+			if tc.expBackup != "" {
+				dst, err := backupPath(tc.expBackup, f)
+				assert.Nil(t, err)
+				_, err = os.Stat(dst)
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestBackupFileFS(t *testing.T) {
+	m := newMemFileSystem("backups/other.jpg")
+	m.files["photos/a.jpg"] = []byte("original")
+	m.modes["photos/a.jpg"] = 0600
+	m.files["others/a.jpg"] = []byte("other")
+	dst, err := backupPath("backups", "photos/a.jpg")
+	assert.Nil(t, err)
+	dst2, err := backupPath("backups", "others/a.jpg")
+	assert.Nil(t, err)
+
+	assert.Nil(t, backupFile(m, "photos/a.jpg", "backups"))
+	assert.Equal(t, "original", string(m.files[dst]))
+	assert.Equal(t, os.FileMode(0600), m.modes[dst])
+
+	m.files["photos/a.jpg"] = []byte("updated")
+	assert.Nil(t, backupFile(m, "photos/a.jpg", "backups"))
+	assert.Equal(t, "original", string(m.files[dst]))
+
+	// same name, other directory
+	assert.Nil(t, backupFile(m, "others/a.jpg", "backups"))
+	assert.Equal(t, "other", string(m.files[dst2]))
+	assert.Equal(t, "original", string(m.files[dst]))
+
+	assert.NotNil(t, backupFile(m, "photos/nonExisting.jpg", "backups"))
+	assert.NotNil(t, backupFile(m, "photos/a.jpg", "backups/other.jpg"))
+}
+
+func TestBackupOriginal(t *testing.T) {
+	var tcs = []struct {
+		tcID   string
+		policy WritePolicy
+		expDst string
+	}{
+		{"keepOriginal", KeepOriginal(), "photos/a.jpg_original"},
+		{"backupDir", BackupDir("backups"), "backups"},
+		{"overwriteOriginal", OverwriteOriginal(), ""},
+		{"atomicWrite", AtomicWrite(), ""},
+	}
+
+	for _, tc := range tcs {
+		tc := tc // Pin variable
+		t.Run(tc.tcID, func(t *testing.T) {
+			m := newMemFileSystem("backups/other.jpg")
+			m.files["photos/a.jpg"] = []byte("original")
+			e := &Exiftool{fsys: m, policy: tc.policy}
+
+			assert.Nil(t, e.backupOriginal("photos/a.jpg"))
+			m.files["photos/a.jpg"] = []byte("updated")
+			assert.Nil(t, e.backupOriginal("photos/a.jpg"))
+			if tc.expDst == "" {
+				assert.Equal(t, 2, len(m.files))
+				return
+			}
+			if tc.expDst == "backups" {
+				dst, err := backupPath("backups", "photos/a.jpg")
+				assert.Nil(t, err)
+				tc.expDst = dst
+			}
+			assert.Equal(t, "original", string(m.files[tc.expDst]))
+		})
+	}
+}
+
+func TestReplaceFile(t *testing.T) {
+	m := newMemFileSystem()
+	m.files["a.jpg"] = []byte("original")
+	m.files[".a-1.jpg"] = []byte("written")
+
+	assert.Nil(t, replaceFile(m, ".a-1.jpg", "a.jpg", 0640))
+	assert.Equal(t, map[string][]byte{"a.jpg": []byte("written")}, m.files)
+	assert.Equal(t, os.FileMode(0640), m.modes["a.jpg"])
+
+	assert.NotNil(t, replaceFile(m, ".a-2.jpg", "a.jpg", 0640))
+	assert.Equal(t, "written", string(m.files["a.jpg"]))
+}
+
+func TestSetWritePolicyFS(t *testing.T) {
+	m := newMemFileSystem("backups/a.jpg")
+
+	e := Exiftool{}
+	assert.Nil(t, SetFileSystem(m)(&e))
+	assert.Nil(t, SetWritePolicy(BackupDir("backups"))(&e))
+	assert.NotNil(t, SetWritePolicy(BackupDir("nonExisting"))(&e))
+	assert.NotNil(t, SetWritePolicy(BackupDir("backups/a.jpg"))(&e))
+}
+
+func TestAtomicWriteKeepsMode(t *testing.T) {
+	f, clean := copyTestFile(t, "./testdata/20190404_131804.jpg")
+	defer clean()
+	assert.Nil(t, os.Chmod(f, 0600))
+
+	e, err := NewExiftool(SetWritePolicy(AtomicWrite()))
+	assert.Nil(t, err)
+	defer e.Close()
+
+	assert.Nil(t, e.Write(f, FileMetadataValues{{"Artist", "go-exiftool"}}))
+	fi, err := os.Stat(f)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+}
@@ -0,0 +1,251 @@
+package exiftool
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	rdfNamespace  = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	xmlnsSpace    = "xmlns"
+	base64Type    = "http://www.w3.org/2001/XMLSchema#base64Binary"
+	base64Prefix  = "base64:"
+	xmlFileFamily = 1
+)
+
+// ExtractXML extracts the metadata of file as RDF/XML (activates Exiftool's '-X'
+// parameter), as required by some archival systems. The returned document can be
+// parsed with ParseXML. If anything went wrong, a non empty error will be returned.
+// Sample :
+//   rdf, err := e.ExtractXML("photo.jpg")
+func (e *Exiftool) ExtractXML(file string) ([]byte, error) {
+	if err := e.checkFile(file); err != nil {
+		return nil, err
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	out, err := e.execute("-X", file)
+	if err != nil {
+		return nil, err
+	}
+
+	// warnings printed on stderr precede the document
+	idx := bytes.Index(out, []byte("<?xml"))
+	if idx == -1 {
+		return nil, fmt.Errorf("no XML document in output (%v)", strings.TrimSpace(string(out)))
+	}
+
+	b := make([]byte, len(out)-idx)
+	copy(b, out[idx:])
+
+	return b, nil
+}
+
+// ExtractXMLMetadata extracts the metadata of files as RDF/XML (see ExtractXML) and
+// parses it (see ParseXML)
+func (e *Exiftool) ExtractXMLMetadata(files ...string) []FileMetadata {
+	fms := make([]FileMetadata, len(files))
+	for i, f := range files {
+		fms[i].File = f
+
+		b, err := e.ExtractXML(f)
+		if err != nil {
+			fms[i].Err = err
+			continue
+		}
+
+		parsed, err := ParseXML(b)
+		if err != nil {
+			fms[i].Err = err
+			continue
+		}
+		fms[i] = parsed[0]
+		fms[i].File = f
+		fms[i].Err = fms[i].exiftoolError()
+	}
+	return fms
+}
+
+// ParseXML parses the RDF/XML printed by exiftool (see ExtractXML) into a FileMetadata
+// per rdf:Description element. Groups are named after the RDF namespace prefixes,
+// which are exiftool's family 1 groups (ie. "IFD0", "XMP-dc"). As XML has no types,
+// values are strings, lists (rdf:Bag, rdf:Seq, rdf:Alt) are []interface{} and
+// structures are FileMetadataValues. Base64 encoded binary values are prefixed by
+// "base64:", as in the JSON output. If anything went wrong, a non empty error will be
+// returned.
+func ParseXML(data []byte) ([]FileMetadata, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+
+	var fms []FileMetadata
+	for {
+		t, err := dec.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("error while parsing XML: %w", err)
+		}
+
+		se, ok := t.(xml.StartElement)
+		if !ok || !isRDF(se.Name, "Description") {
+			continue
+		}
+		fm, err := parseXMLDescription(dec, se)
+		if err != nil {
+			return nil, err
+		}
+		fms = append(fms, fm)
+	}
+
+	if len(fms) == 0 {
+		return nil, errors.New("no rdf:Description element")
+	}
+
+	return fms, nil
+}
+
+func isRDF(n xml.Name, local string) bool {
+	return n.Space == rdfNamespace && n.Local == local
+}
+
+// parseXMLDescription parses the tags of the rdf:Description element se
+func parseXMLDescription(dec *xml.Decoder, se xml.StartElement) (FileMetadata, error) {
+	fm := FileMetadata{Groups: map[string]FileMetadataValues{}, GroupFamilies: []int{xmlFileFamily}}
+
+	prefixes := map[string]string{}
+	for _, a := range se.Attr {
+		switch {
+		case a.Name.Space == xmlnsSpace:
+			prefixes[a.Value] = a.Name.Local
+		case isRDF(a.Name, "about"):
+			fm.File = a.Value
+		}
+	}
+
+	for {
+		t, err := dec.Token()
+		if err != nil {
+			return fm, fmt.Errorf("error while parsing XML: %w", err)
+		}
+
+		switch t := t.(type) {
+		case xml.StartElement:
+			v, err := parseXMLValue(dec, t)
+			if err != nil {
+				return fm, err
+			}
+			g, found := prefixes[t.Name.Space]
+			if !found {
+				g = t.Name.Space
+			}
+			if _, found := fm.Groups[g]; !found {
+				fm.GroupOrder = append(fm.GroupOrder, g)
+			}
+			fm.Groups[g] = append(fm.Groups[g], FileMetadataValue{Label: t.Name.Local, Value: v})
+		case xml.EndElement:
+			return fm, nil
+		}
+	}
+}
+
+// parseXMLValue parses the value of the element se: its text, a list or a structure
+func parseXMLValue(dec *xml.Decoder, se xml.StartElement) (interface{}, error) {
+	base64 := false
+	for _, a := range se.Attr {
+		if isRDF(a.Name, "parseType") && a.Value == "Resource" {
+			return parseXMLStruct(dec)
+		}
+		if isRDF(a.Name, "datatype") && a.Value == base64Type {
+			base64 = true
+		}
+	}
+
+	var text strings.Builder
+	var v interface{}
+	for {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing XML value of %v: %w", se.Name.Local, err)
+		}
+
+		switch t := t.(type) {
+		case xml.CharData:
+			text.Write(t)
+		case xml.StartElement:
+			switch {
+			case isRDF(t.Name, "Bag"), isRDF(t.Name, "Seq"), isRDF(t.Name, "Alt"):
+				v, err = parseXMLList(dec)
+			case isRDF(t.Name, "Description"):
+				v, err = parseXMLStruct(dec)
+			default:
+				err = dec.Skip()
+			}
+			if err != nil {
+				return nil, err
+			}
+		case xml.EndElement:
+			if v != nil {
+				return v, nil
+			}
+			if base64 {
+				return base64Prefix + strings.TrimSpace(text.String()), nil
+			}
+			return text.String(), nil
+		}
+	}
+}
+
+// parseXMLList parses the rdf:li items of a list whose start element has been read
+func parseXMLList(dec *xml.Decoder) ([]interface{}, error) {
+	l := []interface{}{}
+	for {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing XML list: %w", err)
+		}
+
+		switch t := t.(type) {
+		case xml.StartElement:
+			if !isRDF(t.Name, "li") {
+				if err := dec.Skip(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			v, err := parseXMLValue(dec, t)
+			if err != nil {
+				return nil, err
+			}
+			l = append(l, v)
+		case xml.EndElement:
+			return l, nil
+		}
+	}
+}
+
+// parseXMLStruct parses the fields of a structure whose start element has been read
+func parseXMLStruct(dec *xml.Decoder) (FileMetadataValues, error) {
+	s := FileMetadataValues{}
+	for {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing XML structure: %w", err)
+		}
+
+		switch t := t.(type) {
+		case xml.StartElement:
+			v, err := parseXMLValue(dec, t)
+			if err != nil {
+				return nil, err
+			}
+			s = append(s, FileMetadataValue{Label: t.Name.Local, Value: v})
+		case xml.EndElement:
+			return s, nil
+		}
+	}
+}
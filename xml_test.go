@@ -0,0 +1,100 @@
+package exiftool
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testXML = `<?xml version='1.0' encoding='UTF-8'?>
+<rdf:RDF xmlns:rdf='http://www.w3.org/1999/02/22-rdf-syntax-ns#'>
+
+<rdf:Description rdf:about='a.jpg'
+  xmlns:et='http://ns.exiftool.org/1.0/' et:toolkit='Image::ExifTool 12.40'
+  xmlns:ExifTool='http://ns.exiftool.org/ExifTool/1.0/'
+  xmlns:System='http://ns.exiftool.org/File/System/1.0/'
+  xmlns:IFD0='http://ns.exiftool.org/EXIF/IFD0/1.0/'
+  xmlns:IFD1='http://ns.exiftool.org/EXIF/IFD1/1.0/'
+  xmlns:XMP-dc='http://ns.exiftool.org/XMP/XMP-dc/1.0/'
+  xmlns:XMP-mwg-rs='http://ns.exiftool.org/XMP/XMP-mwg-rs/1.0/'>
+ <ExifTool:ExifToolVersion>12.40</ExifTool:ExifToolVersion>
+ <System:FileName>a.jpg</System:FileName>
+ <IFD0:Make>samsung</IFD0:Make>
+ <IFD0:Copyright>A &amp; B</IFD0:Copyright>
+ <IFD1:ThumbnailImage rdf:datatype='http://www.w3.org/2001/XMLSchema#base64Binary'>
+/9j/4AAQ
+</IFD1:ThumbnailImage>
+ <XMP-dc:Subject>
+  <rdf:Bag>
+   <rdf:li>a</rdf:li>
+   <rdf:li>b</rdf:li>
+  </rdf:Bag>
+ </XMP-dc:Subject>
+ <XMP-mwg-rs:RegionInfo rdf:parseType='Resource'>
+  <XMP-mwg-rs:RegionList>
+   <rdf:Bag>
+    <rdf:li rdf:parseType='Resource'>
+     <XMP-mwg-rs:Name>me</XMP-mwg-rs:Name>
+    </rdf:li>
+   </rdf:Bag>
+  </XMP-mwg-rs:RegionList>
+ </XMP-mwg-rs:RegionInfo>
+</rdf:Description>
+
+<rdf:Description rdf:about='b.jpg'
+  xmlns:IFD0='http://ns.exiftool.org/EXIF/IFD0/1.0/'>
+ <IFD0:Make>Canon</IFD0:Make>
+</rdf:Description>
+</rdf:RDF>
+`
+
+func TestParseXML(t *testing.T) {
+	fms, err := ParseXML([]byte(testXML))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(fms))
+
+	assert.Equal(t, "a.jpg", fms[0].File)
+	assert.Equal(t, []int{1}, fms[0].GroupFamilies)
+	assert.Equal(t, []string{"ExifTool", "System", "IFD0", "IFD1", "XMP-dc", "XMP-mwg-rs"}, fms[0].GroupOrder)
+	assert.Equal(t, map[string]FileMetadataValues{
+		"ExifTool": {{"ExifToolVersion", "12.40"}},
+		"System":   {{"FileName", "a.jpg"}},
+		"IFD0":     {{"Make", "samsung"}, {"Copyright", "A & B"}},
+		"IFD1":     {{"ThumbnailImage", "base64:/9j/4AAQ"}},
+		"XMP-dc":   {{"Subject", []interface{}{"a", "b"}}},
+		"XMP-mwg-rs": {{"RegionInfo", FileMetadataValues{
+			{"RegionList", []interface{}{FileMetadataValues{{"Name", "me"}}}},
+		}}},
+	}, fms[0].Groups)
+
+	assert.Equal(t, "b.jpg", fms[1].File)
+	assert.Equal(t, map[string]FileMetadataValues{"IFD0": {{"Make", "Canon"}}}, fms[1].Groups)
+
+	for _, in := range []string{"", "<rdf:RDF xmlns:rdf='http://www.w3.org/1999/02/22-rdf-syntax-ns#'></rdf:RDF>", testXML[:300]} {
+		_, err = ParseXML([]byte(in))
+		assert.NotNil(t, err, "no error for %q", in)
+	}
+}
+
+func TestExtractXML(t *testing.T) {
+	e, err := NewExiftool()
+	assert.Nil(t, err)
+	defer e.Close()
+
+	_, err = e.ExtractXML("./testdata/nonExisting")
+	assert.True(t, errors.Is(err, ErrNotExist))
+
+	b, err := e.ExtractXML("./testdata/20190404_131804.jpg")
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), "<IFD0:Make>samsung</IFD0:Make>")
+
+	fms := e.ExtractXMLMetadata("./testdata/20190404_131804.jpg", "./testdata/nonExisting")
+	assert.Equal(t, 2, len(fms))
+	assert.Nil(t, fms[0].Err)
+	assert.Equal(t, "./testdata/20190404_131804.jpg", fms[0].File)
+	mk, err := fms[0].Groups["IFD0"].GetString("Make")
+	assert.Nil(t, err)
+	assert.Equal(t, "samsung", mk)
+	assert.True(t, errors.Is(fms[1].Err, ErrNotExist))
+}